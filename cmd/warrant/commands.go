@@ -0,0 +1,593 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	check "github.com/warrant-dev/warrant/pkg/authz/check"
+	manifest "github.com/warrant-dev/warrant/pkg/authz/manifest"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	snapshot "github.com/warrant-dev/warrant/pkg/authz/snapshot"
+	testsuite "github.com/warrant-dev/warrant/pkg/authz/testsuite"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/config"
+	"github.com/warrant-dev/warrant/pkg/database"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+const usage = `Usage: warrant <command> [arguments]
+
+Commands:
+  serve                                             Start the server (default)
+  migrate up <datastore|eventstore>                 Apply all migrations not yet applied
+  migrate down <datastore|eventstore> [steps]       Roll back the given number of migrations (default 1)
+  migrate status <datastore|eventstore>             Print the current and latest migration versions
+  migrate force <datastore|eventstore> <version>    Set the migration version without running migrations
+  check [-url url] [-apiKey key] [-debug] <warrant> Check a warrant (e.g. 'document:1#viewer@user:2')
+  manifest plan [-f file] [-prune]                  Print the changes needed to apply a YAML or JSON manifest
+  manifest apply [-f file] [-prune]                 Apply a YAML or JSON manifest in a single transaction
+  manifest default                                  Print the manifest of the built-in object types
+  objecttypes apply -f <file>                       Create or update the object types in a YAML or JSON file
+  test [-objectTypes file] <suite>...               Run YAML or JSON model test suites in a scratch datastore
+  warrants apply [-f file] [-dryRun]                Apply warrants in the warrant text format
+  warrants export [-o file]                         Export warrants in the warrant text format
+  export [-format json|ndjson] [-o file]            Export a snapshot of the datastore
+  import [-format json|ndjson] [-mode merge|replace] [-f file]
+                                                    Import a snapshot into the datastore
+  purge [-days N]                                   Purge rows soft-deleted more than N days ago
+`
+
+// prepareSchema migrates db to version if autoMigrate is true and otherwise
+// checks that db has already been migrated to version. Only serve migrates
+// automatically, so that running a command with a newer version of warrant
+// never migrates a datastore that older servers are still using.
+func prepareSchema(db database.Database, databaseName string, version uint, autoMigrate bool) error {
+	if autoMigrate {
+		return db.Migrate(context.Background(), version)
+	}
+
+	currentVersion, dirty, err := db.MigrationVersion(context.Background())
+	if err != nil {
+		return err
+	}
+
+	err = database.ValidateMigrationVersion(databaseName, currentVersion, dirty, version)
+	if err != nil {
+		return err
+	}
+
+	if currentVersion < version {
+		return fmt.Errorf("database %s is at version %d, but this version of warrant requires version %d: run `warrant migrate up %s`", databaseName, currentVersion, version, databaseName)
+	}
+
+	return nil
+}
+
+func datastoreMigrationVersion(db database.Database) uint {
+	if db.Type() == database.TypeMySQL {
+		return MySQLDatastoreMigrationVersion
+	}
+
+	return PostgresDatastoreMigrationVersion
+}
+
+func eventstoreMigrationVersion(db database.Database) uint {
+	if db.Type() == database.TypeMySQL {
+		return MySQLEventstoreMigrationVersion
+	}
+
+	return PostgresEventstoreMigrationVersion
+}
+
+type MigrationStatus struct {
+	Database      string `json:"database"`
+	Version       uint   `json:"version"`
+	Dirty         bool   `json:"dirty"`
+	LatestVersion uint   `json:"latestVersion"`
+}
+
+func migrateCommand(config config.Config, args []string) {
+	if len(args) < 2 || (args[1] != "datastore" && args[1] != "eventstore") {
+		exitWithUsage("migrate requires a subcommand (up, down, status, or force) and a database (datastore or eventstore)")
+	}
+
+	subcommand := args[0]
+	dbName := args[1]
+	svcEnv := NewServiceEnv()
+	var db database.Database
+	var latestVersion uint
+	if dbName == "datastore" {
+		err := svcEnv.ConnectDB(config)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Could not connect to the configured datastore")
+		}
+
+		db = svcEnv.DB()
+		latestVersion = datastoreMigrationVersion(db)
+	} else {
+		err := svcEnv.ConnectEventDB(config)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Could not connect to the configured eventstore")
+		}
+
+		db = svcEnv.EventDB()
+		latestVersion = eventstoreMigrationVersion(db)
+	}
+
+	ctx := context.Background()
+	switch subcommand {
+	case "up":
+		err := db.Migrate(ctx, latestVersion)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Could not migrate %s", dbName)
+		}
+	case "down":
+		steps := uint64(1)
+		if len(args) > 2 {
+			var err error
+			steps, err = strconv.ParseUint(args[2], 10, 32)
+			if err != nil || steps == 0 {
+				exitWithUsage("steps must be a number greater than 0")
+			}
+		}
+
+		err := db.MigrateDown(ctx, uint(steps))
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Could not migrate %s", dbName)
+		}
+	case "force":
+		if len(args) < 3 {
+			exitWithUsage("force requires a version")
+		}
+
+		// NOTE: a version of -1 means no migrations have been applied
+		version, err := strconv.Atoi(args[2])
+		if err != nil || version < -1 {
+			exitWithUsage("version must be a number greater than or equal to -1")
+		}
+
+		err = db.ForceMigrationVersion(ctx, version)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Could not force version of %s", dbName)
+		}
+	case "status":
+	default:
+		exitWithUsage(fmt.Sprintf("unknown migrate subcommand %s", subcommand))
+	}
+
+	version, dirty, err := db.MigrationVersion(ctx)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Could not get version of %s", dbName)
+	}
+
+	printJSON(MigrationStatus{
+		Database:      dbName,
+		Version:       version,
+		Dirty:         dirty,
+		LatestVersion: latestVersion,
+	})
+}
+
+// checkCommand checks a warrant against a running server if -url is given
+// and directly against the datastore otherwise. It exits with status 1 if
+// the check is not authorized.
+func checkCommand(config config.Config, args []string) {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	serverUrl := flags.String("url", "", "url of a running server to check against (e.g. http://localhost:8000). If not given, the check is run against the datastore.")
+	apiKey := flags.String("apiKey", config.ApiKey, "api key of the server (defaults to apiKey)")
+	debug := flags.Bool("debug", false, "include the decision path in the result")
+	// NOTE: flag.ExitOnError exits on invalid flags
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		exitWithUsage("check requires a warrant (e.g. 'document:1#viewer@user:2')")
+	}
+
+	warrantSpec, err := warrant.StringToWarrantSpec(flags.Arg(0))
+	if err != nil {
+		exitWithUsage(fmt.Sprintf("invalid warrant %s", flags.Arg(0)))
+	}
+
+	checkManySpec := check.CheckManySpec{
+		Warrants: []warrant.WarrantSpec{*warrantSpec},
+		Debug:    *debug,
+	}
+	var checkResult *check.CheckResultSpec
+	if *serverUrl != "" {
+		checkResult, err = checkAgainstServer(*serverUrl, *apiKey, checkManySpec)
+	} else {
+		svcEnv := NewServiceEnv()
+		svcs := initServices(&svcEnv, config, false)
+		checkResult, err = svcs.Check.CheckMany(context.Background(), nil, &checkManySpec)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not check warrant")
+	}
+
+	printJSON(checkResult)
+	if checkResult.Result != check.Authorized {
+		os.Exit(1)
+	}
+}
+
+func checkAgainstServer(serverUrl string, apiKey string, checkManySpec check.CheckManySpec) (*check.CheckResultSpec, error) {
+	requestBody, err := json.Marshal(checkManySpec)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v2/authorize", strings.TrimSuffix(serverUrl, "/")), bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("ApiKey %s", apiKey))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(responseBody)))
+	}
+
+	var checkResult check.CheckResultSpec
+	err = json.Unmarshal(responseBody, &checkResult)
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkResult, nil
+}
+
+// manifestCommand plans or applies a manifest, or prints the default manifest
+func manifestCommand(config config.Config, args []string) {
+	if len(args) == 0 || (args[0] != "plan" && args[0] != "apply" && args[0] != "default") {
+		exitWithUsage("manifest requires a subcommand (plan, apply or default)")
+	}
+
+	if args[0] == "default" {
+		fmt.Print(string(manifest.DefaultManifestYAML))
+		return
+	}
+
+	flags := flag.NewFlagSet(fmt.Sprintf("manifest %s", args[0]), flag.ExitOnError)
+	inputPath := flags.String("f", "", "YAML or JSON manifest (default stdin)")
+	prune := flags.Bool("prune", false, "delete resources that are not in the manifest")
+	// NOTE: flag.ExitOnError exits on invalid flags
+	_ = flags.Parse(args[1:])
+
+	input, closeInput := openInput(*inputPath)
+	defer closeInput()
+	contents, err := io.ReadAll(input)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not read manifest")
+	}
+
+	manifestSpec, err := manifest.ParseManifest(contents)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid manifest")
+	}
+
+	svcEnv := NewServiceEnv()
+	svcs := initServices(&svcEnv, config, false)
+	var plan *manifest.PlanSpec
+	if args[0] == "plan" {
+		plan, err = svcs.Manifest.Plan(context.Background(), *manifestSpec, *prune)
+	} else {
+		plan, err = svcs.Manifest.Apply(context.Background(), *manifestSpec, *prune)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Could not %s manifest", args[0])
+	}
+
+	printJSON(plan)
+}
+
+type ObjectTypesFile struct {
+	ObjectTypes []objecttype.ObjectTypeSpec `json:"objectTypes"`
+}
+
+// objectTypesCommand creates the object types in a YAML or JSON file that
+// don't exist and updates those that differ, in a single transaction
+func objectTypesCommand(config config.Config, args []string) {
+	if len(args) == 0 || args[0] != "apply" {
+		exitWithUsage("objecttypes requires a subcommand (apply)")
+	}
+
+	flags := flag.NewFlagSet("objecttypes apply", flag.ExitOnError)
+	inputPath := flags.String("f", "", "YAML or JSON file with a list of object types or an objectTypes key")
+	// NOTE: flag.ExitOnError exits on invalid flags
+	_ = flags.Parse(args[1:])
+
+	if *inputPath == "" {
+		exitWithUsage("objecttypes apply requires a file (-f)")
+	}
+
+	objectTypeSpecs, err := readObjectTypesFile(*inputPath)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Could not read %s", *inputPath)
+	}
+
+	svcEnv := NewServiceEnv()
+	svcs := initServices(&svcEnv, config, false)
+	plan, err := svcs.Manifest.Apply(context.Background(), manifest.ManifestSpec{ObjectTypes: objectTypeSpecs}, false)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not apply object types")
+	}
+
+	outcomes := make(map[string]string)
+	for _, objectTypeSpec := range objectTypeSpecs {
+		outcomes[objectTypeSpec.Type] = "unchanged"
+	}
+
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case manifest.ActionCreate:
+			outcomes[change.ResourceId] = "created"
+		case manifest.ActionUpdate:
+			outcomes[change.ResourceId] = "updated"
+		}
+	}
+
+	printJSON(outcomes)
+}
+
+func readObjectTypesFile(path string) ([]objecttype.ObjectTypeSpec, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		contents, err = service.YAMLToJSON(contents)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	objectTypeSpecs := make([]objecttype.ObjectTypeSpec, 0)
+	if service.IsArray(contents) {
+		err = json.Unmarshal(contents, &objectTypeSpecs)
+	} else {
+		var objectTypesFile ObjectTypesFile
+		err = json.Unmarshal(contents, &objectTypesFile)
+		objectTypeSpecs = objectTypesFile.ObjectTypes
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range objectTypeSpecs {
+		err = service.ValidateStruct(&objectTypeSpecs[i])
+		if err != nil {
+			return nil, fmt.Errorf("object type %d: %w", i+1, err)
+		}
+	}
+
+	return objectTypeSpecs, nil
+}
+
+// testCommand runs each test suite file given and exits with status 1 if
+// any assertion fails
+func testCommand(config config.Config, args []string) {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	objectTypesPath := flags.String("objectTypes", "", "YAML or JSON file with the object types to test, overriding those in each suite")
+	// NOTE: flag.ExitOnError exits on invalid flags
+	_ = flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		exitWithUsage("test requires at least one test suite file")
+	}
+
+	testSuites := make([]testsuite.TestSuiteSpec, 0, flags.NArg())
+	for _, path := range flags.Args() {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Could not read %s", path)
+		}
+
+		testSuite, err := testsuite.ParseTestSuite(contents)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Invalid test suite %s", path)
+		}
+
+		if testSuite.Name == "" {
+			testSuite.Name = path
+		}
+
+		testSuites = append(testSuites, *testSuite)
+	}
+
+	if *objectTypesPath != "" {
+		objectTypeSpecs, err := readObjectTypesFile(*objectTypesPath)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Could not read %s", *objectTypesPath)
+		}
+
+		for i := range testSuites {
+			testSuites[i].ObjectTypes = objectTypeSpecs
+		}
+	}
+
+	svcEnv := NewServiceEnv()
+	svcs := initServices(&svcEnv, config, false)
+	results := make([]*testsuite.TestSuiteResultSpec, 0, len(testSuites))
+	passed := true
+	for _, testSuite := range testSuites {
+		result, err := svcs.TestSuite.Run(context.Background(), testSuite)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("Could not run test suite %s", testSuite.Name)
+		}
+
+		passed = passed && result.Passed
+		results = append(results, result)
+	}
+
+	printJSON(results)
+	if !passed {
+		os.Exit(1)
+	}
+}
+
+func warrantsCommand(config config.Config, args []string) {
+	if len(args) == 0 || (args[0] != "apply" && args[0] != "export") {
+		exitWithUsage("warrants requires a subcommand (apply or export)")
+	}
+
+	if args[0] == "export" {
+		flags := flag.NewFlagSet("warrants export", flag.ExitOnError)
+		outputPath := flags.String("o", "", "file to write the warrants to (defaults to stdout)")
+		// NOTE: flag.ExitOnError exits on invalid flags
+		_ = flags.Parse(args[1:])
+
+		svcEnv := NewServiceEnv()
+		svcs := initServices(&svcEnv, config, false)
+		output, closeOutput := openOutput(*outputPath)
+		defer closeOutput()
+
+		err := svcs.Warrant.ExportText(context.Background(), &warrant.FilterOptions{}, output)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Could not export warrants")
+		}
+
+		return
+	}
+
+	flags := flag.NewFlagSet("warrants apply", flag.ExitOnError)
+	inputPath := flags.String("f", "", "file with one warrant per line to apply (defaults to stdin)")
+	dryRun := flags.Bool("dryRun", false, "validate the warrants and count the changes without applying them")
+	// NOTE: flag.ExitOnError exits on invalid flags
+	_ = flags.Parse(args[1:])
+
+	svcEnv := NewServiceEnv()
+	svcs := initServices(&svcEnv, config, false)
+	input, closeInput := openInput(*inputPath)
+	defer closeInput()
+
+	result, err := svcs.Warrant.ApplyText(context.Background(), input, *dryRun)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not apply warrants")
+	}
+
+	printJSON(result)
+}
+
+func exportCommand(config config.Config, args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", snapshot.FormatJSON, "format of the snapshot (json or ndjson)")
+	outputPath := flags.String("o", "", "file to write the snapshot to (defaults to stdout)")
+	// NOTE: flag.ExitOnError exits on invalid flags
+	_ = flags.Parse(args)
+
+	svcEnv := NewServiceEnv()
+	svcs := initServices(&svcEnv, config, false)
+	output, closeOutput := openOutput(*outputPath)
+	defer closeOutput()
+
+	err := svcs.Snapshot.Export(context.Background(), *format, output)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not export snapshot")
+	}
+}
+
+func importCommand(config config.Config, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	format := flags.String("format", snapshot.FormatJSON, "format of the snapshot (json or ndjson)")
+	mode := flags.String("mode", snapshot.ModeMerge, "merge the snapshot into the datastore or replace the datastore with it (merge or replace)")
+	inputPath := flags.String("f", "", "file to read the snapshot from (defaults to stdin)")
+	// NOTE: flag.ExitOnError exits on invalid flags
+	_ = flags.Parse(args)
+
+	svcEnv := NewServiceEnv()
+	svcs := initServices(&svcEnv, config, false)
+	input, closeInput := openInput(*inputPath)
+	defer closeInput()
+
+	result, err := svcs.Snapshot.Import(context.Background(), *format, *mode, input)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not import snapshot")
+	}
+
+	printJSON(result)
+}
+
+// purgeCommand purges soft-deleted rows once. It only requires the datastore.
+func purgeCommand(config config.Config, args []string) {
+	flags := flag.NewFlagSet("purge", flag.ExitOnError)
+	days := flags.Int("days", 0, "purge rows soft-deleted more than this many days ago (defaults to retention.days)")
+	// NOTE: flag.ExitOnError exits on invalid flags
+	_ = flags.Parse(args)
+
+	svcEnv := NewServiceEnv()
+	err := svcEnv.InitDB(config, false)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not initialize and connect to the configured datastore. Shutting down.")
+	}
+
+	result, err := newRetentionService(svcEnv, config).Purge(context.Background(), *days)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not purge deleted rows")
+	}
+
+	printJSON(result)
+}
+
+// openInput opens the file at path, or stdin if path is empty
+func openInput(path string) (io.Reader, func()) {
+	if path == "" {
+		return os.Stdin, func() {}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Could not open %s", path)
+	}
+
+	return file, func() { file.Close() }
+}
+
+// openOutput creates the file at path, or returns stdout if path is empty
+func openOutput(path string) (io.Writer, func()) {
+	if path == "" {
+		return os.Stdout, func() {}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("Could not create %s", path)
+	}
+
+	return file, func() { file.Close() }
+}
+
+func printJSON(result interface{}) {
+	output, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not print result")
+	}
+
+	fmt.Println(string(output))
+}
+
+func exitWithUsage(msg string) {
+	fmt.Fprintf(os.Stderr, "%s\n\n%s", msg, usage)
+	os.Exit(2)
+}
@@ -4,31 +4,39 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	check "github.com/warrant-dev/warrant/pkg/authz/check"
 	feature "github.com/warrant-dev/warrant/pkg/authz/feature"
+	manifest "github.com/warrant-dev/warrant/pkg/authz/manifest"
 	object "github.com/warrant-dev/warrant/pkg/authz/object"
+	objectsync "github.com/warrant-dev/warrant/pkg/authz/objectsync"
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
 	permission "github.com/warrant-dev/warrant/pkg/authz/permission"
 	pricingtier "github.com/warrant-dev/warrant/pkg/authz/pricingtier"
+	quota "github.com/warrant-dev/warrant/pkg/authz/quota"
 	role "github.com/warrant-dev/warrant/pkg/authz/role"
+	snapshot "github.com/warrant-dev/warrant/pkg/authz/snapshot"
 	tenant "github.com/warrant-dev/warrant/pkg/authz/tenant"
+	testsuite "github.com/warrant-dev/warrant/pkg/authz/testsuite"
 	user "github.com/warrant-dev/warrant/pkg/authz/user"
 	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/change"
 	"github.com/warrant-dev/warrant/pkg/config"
 	wntContext "github.com/warrant-dev/warrant/pkg/context"
 	"github.com/warrant-dev/warrant/pkg/database"
 	"github.com/warrant-dev/warrant/pkg/event"
+	"github.com/warrant-dev/warrant/pkg/retention"
 	"github.com/warrant-dev/warrant/pkg/service"
 )
 
 const (
-	MySQLDatastoreMigrationVersion     = 000002
-	MySQLEventstoreMigrationVersion    = 000001
-	PostgresDatastoreMigrationVersion  = 000002
-	PostgresEventstoreMigrationVersion = 000001
+	MySQLDatastoreMigrationVersion     = 10
+	MySQLEventstoreMigrationVersion    = 1
+	PostgresDatastoreMigrationVersion  = 10
+	PostgresEventstoreMigrationVersion = 1
 )
 
 type ServiceEnv struct {
@@ -44,7 +52,8 @@ func (env ServiceEnv) EventDB() database.Database {
 	return env.Eventstore
 }
 
-func (env *ServiceEnv) InitDB(config config.Config) error {
+// ConnectDB connects to the configured datastore without migrating it
+func (env *ServiceEnv) ConnectDB(config config.Config) error {
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancelFunc()
 
@@ -55,11 +64,6 @@ func (env *ServiceEnv) InitDB(config config.Config) error {
 			return err
 		}
 
-		err = db.Migrate(ctx, MySQLDatastoreMigrationVersion)
-		if err != nil {
-			return err
-		}
-
 		env.Datastore = db
 		return nil
 	}
@@ -71,11 +75,6 @@ func (env *ServiceEnv) InitDB(config config.Config) error {
 			return err
 		}
 
-		err = db.Migrate(ctx, PostgresDatastoreMigrationVersion)
-		if err != nil {
-			return err
-		}
-
 		env.Datastore = db
 		return nil
 	}
@@ -83,7 +82,8 @@ func (env *ServiceEnv) InitDB(config config.Config) error {
 	return fmt.Errorf("invalid database configuration provided")
 }
 
-func (env *ServiceEnv) InitEventDB(config config.Config) error {
+// ConnectEventDB connects to the configured eventstore without migrating it
+func (env *ServiceEnv) ConnectEventDB(config config.Config) error {
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancelFunc()
 
@@ -94,11 +94,6 @@ func (env *ServiceEnv) InitEventDB(config config.Config) error {
 			return err
 		}
 
-		err = db.Migrate(ctx, MySQLEventstoreMigrationVersion)
-		if err != nil {
-			return err
-		}
-
 		env.Eventstore = db
 		return nil
 	}
@@ -110,11 +105,6 @@ func (env *ServiceEnv) InitEventDB(config config.Config) error {
 			return err
 		}
 
-		err = db.Migrate(ctx, PostgresEventstoreMigrationVersion)
-		if err != nil {
-			return err
-		}
-
 		env.Eventstore = db
 		return nil
 	}
@@ -122,6 +112,24 @@ func (env *ServiceEnv) InitEventDB(config config.Config) error {
 	return fmt.Errorf("invalid database configuration provided")
 }
 
+func (env *ServiceEnv) InitDB(config config.Config, autoMigrate bool) error {
+	err := env.ConnectDB(config)
+	if err != nil {
+		return err
+	}
+
+	return prepareSchema(env.Datastore, "datastore", datastoreMigrationVersion(env.Datastore), autoMigrate)
+}
+
+func (env *ServiceEnv) InitEventDB(config config.Config, autoMigrate bool) error {
+	err := env.ConnectEventDB(config)
+	if err != nil {
+		return err
+	}
+
+	return prepareSchema(env.Eventstore, "eventstore", eventstoreMigrationVersion(env.Eventstore), autoMigrate)
+}
+
 func NewServiceEnv() ServiceEnv {
 	return ServiceEnv{
 		Datastore:  nil,
@@ -130,25 +138,141 @@ func NewServiceEnv() ServiceEnv {
 }
 
 func main() {
+	command := "serve"
+	args := make([]string, 0)
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+		args = os.Args[2:]
+	}
+
+	if command == "help" || command == "-h" || command == "-help" || command == "--help" {
+		fmt.Print(usage)
+		return
+	}
+
 	config := config.NewConfig()
+	switch command {
+	case "serve":
+		serve(config)
+	case "migrate":
+		migrateCommand(config, args)
+	case "check":
+		checkCommand(config, args)
+	case "manifest":
+		manifestCommand(config, args)
+	case "objecttypes":
+		objectTypesCommand(config, args)
+	case "test":
+		testCommand(config, args)
+	case "warrants":
+		warrantsCommand(config, args)
+	case "export":
+		exportCommand(config, args)
+	case "import":
+		importCommand(config, args)
+	case "purge":
+		purgeCommand(config, args)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %s\n\n%s", command, usage)
+		os.Exit(2)
+	}
+}
+
+func serve(config config.Config) {
 	svcEnv := NewServiceEnv()
-	err := svcEnv.InitDB(config)
+	svcs := initServices(&svcEnv, config, config.AutoMigrate)
+	if config.ObjectSync.PollInterval > 0 {
+		svcs.ObjectSync.StartPolling(context.Background(), time.Duration(config.ObjectSync.PollInterval)*time.Second)
+	}
+
+	if (config.Retention.Days > 0 || config.Retention.ChangeDays > 0) && config.Retention.PurgeInterval > 0 {
+		svcs.Retention.StartPurging(context.Background(), time.Duration(config.Retention.PurgeInterval)*time.Second)
+	}
+
+	routes := make([]service.Route, 0)
+	for _, svc := range svcs.All() {
+		routes = append(routes, svc.Routes()...)
+	}
+
+	log.Debug().Msgf("Listening on port %d", config.Port)
+	shutdownErr := http.ListenAndServe(fmt.Sprintf(":%d", config.Port), service.NewRouter(&config, "", routes, nil))
+	log.Fatal().Err(shutdownErr).Msg("")
+}
+
+type Services struct {
+	Change      change.ChangeService
+	Check       check.CheckService
+	Event       event.EventService
+	Feature     feature.FeatureService
+	Manifest    manifest.ManifestService
+	Object      object.ObjectService
+	ObjectSync  objectsync.ObjectSyncService
+	ObjectType  objecttype.ObjectTypeService
+	Permission  permission.PermissionService
+	PricingTier pricingtier.PricingTierService
+	Quota       quota.QuotaService
+	Retention   retention.RetentionService
+	Role        role.RoleService
+	Snapshot    snapshot.SnapshotService
+	Tenant      tenant.TenantService
+	TestSuite   testsuite.TestSuiteService
+	User        user.UserService
+	Warrant     warrant.WarrantService
+}
+
+func (svcs Services) All() []service.Service {
+	return []service.Service{
+		svcs.Change,
+		svcs.Check,
+		svcs.Event,
+		svcs.Feature,
+		svcs.Manifest,
+		svcs.Object,
+		svcs.ObjectSync,
+		svcs.ObjectType,
+		svcs.Permission,
+		svcs.PricingTier,
+		svcs.Quota,
+		svcs.Retention,
+		svcs.Role,
+		svcs.Snapshot,
+		svcs.Tenant,
+		svcs.TestSuite,
+		svcs.User,
+		svcs.Warrant,
+	}
+}
+
+// initServices initializes the datastore, eventstore, and all services,
+// migrating the databases first if autoMigrate is true
+func initServices(svcEnv *ServiceEnv, config config.Config, autoMigrate bool) Services {
+	err := svcEnv.InitDB(config, autoMigrate)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Could not initialize and connect to the configured datastore. Shutting down.")
 	}
 
-	err = svcEnv.InitEventDB(config)
+	err = svcEnv.InitEventDB(config, autoMigrate)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Could not initialize and connect to the configured eventstore. Shutting down.")
 	}
 
+	retentionSvc := newRetentionService(*svcEnv, config)
+
 	// Init event repo and service
 	eventRepository, err := event.NewRepository(svcEnv.EventDB())
 	if err != nil {
 		log.Fatal().Err(err).Msg("Could not initialize EventRepository")
 	}
 
-	eventSvc := event.NewService(svcEnv, eventRepository)
+	eventSvc := event.NewService(*svcEnv, eventRepository)
+
+	// Init change repo and service
+	changeRepository, err := change.NewRepository(svcEnv.DB())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not initialize ChangeRepository")
+	}
+
+	changeSvc := change.NewService(*svcEnv, changeRepository)
 
 	// Init object type repo and service
 	objectTypeRepository, err := objecttype.NewRepository(svcEnv.DB())
@@ -156,7 +280,7 @@ func main() {
 		log.Fatal().Err(err).Msg("Could not initialize ObjectTypeRepository")
 	}
 
-	objectTypeSvc := objecttype.NewService(svcEnv, objectTypeRepository, eventSvc)
+	objectTypeSvc := objecttype.NewService(*svcEnv, objectTypeRepository, eventSvc, changeSvc)
 
 	// Init context repo and service
 	ctxRepository, err := wntContext.NewRepository(svcEnv.DB())
@@ -164,7 +288,7 @@ func main() {
 		log.Fatal().Err(err).Msg("Could not initialize ContextRepository")
 	}
 
-	ctxSvc := wntContext.NewService(svcEnv, ctxRepository)
+	ctxSvc := wntContext.NewService(*svcEnv, ctxRepository)
 
 	// Init warrant repo and service
 	warrantRepository, err := warrant.NewRepository(svcEnv.DB())
@@ -172,10 +296,13 @@ func main() {
 		log.Fatal().Err(err).Msg("Could not initialize WarrantRepository")
 	}
 
-	warrantSvc := warrant.NewService(svcEnv, warrantRepository, eventSvc, objectTypeSvc, ctxSvc)
+	warrantSvc := warrant.NewService(*svcEnv, warrantRepository, eventSvc, objectTypeSvc, ctxSvc, changeSvc)
 
-	// Init check service
-	checkSvc := check.NewService(svcEnv, warrantRepository, ctxSvc, eventSvc, objectTypeSvc)
+	// Init quota repo (objects delete their quotas along with them)
+	quotaRepository, err := quota.NewRepository(svcEnv.DB())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not initialize QuotaRepository")
+	}
 
 	// Init object repo and service
 	objectRepository, err := object.NewRepository(svcEnv.DB())
@@ -183,15 +310,34 @@ func main() {
 		log.Fatal().Err(err).Msg("Could not initialize ObjectRepository")
 	}
 
-	objectSvc := object.NewService(svcEnv, objectRepository, eventSvc, warrantSvc)
+	objectSvc := object.NewService(*svcEnv, objectRepository, eventSvc, warrantSvc, objectTypeSvc, quotaRepository, config.Retention.Days)
 
-	// Init feature repo and service
-	featureRepository, err := feature.NewRepository(svcEnv.DB())
+	// Init check service
+	checkSvc := check.NewService(*svcEnv, warrantRepository, objectRepository, ctxSvc, eventSvc, objectTypeSvc)
+
+	// Init user repo and service
+	userRepository, err := user.NewRepository(svcEnv.DB())
 	if err != nil {
-		log.Fatal().Err(err).Msg("Could not initialize FeatureRepository")
+		log.Fatal().Err(err).Msg("Could not initialize UserRepository")
+	}
+
+	userSvc := user.NewService(svcEnv, userRepository, eventSvc, objectSvc)
+
+	// Init tenant repo and service
+	tenantRepository, err := tenant.NewRepository(svcEnv.DB())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not initialize TenantRepository")
+	}
+
+	tenantSvc := tenant.NewService(svcEnv, tenantRepository, eventSvc, objectSvc, userSvc, warrantSvc, checkSvc, objectTypeSvc)
+
+	// Init role repo and service
+	roleRepository, err := role.NewRepository(svcEnv.DB())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not initialize RoleRepository")
 	}
 
-	featureSvc := feature.NewService(&svcEnv, featureRepository, eventSvc, objectSvc)
+	roleSvc := role.NewService(svcEnv, roleRepository, eventSvc, objectSvc, userSvc, tenantSvc, warrantSvc)
 
 	// Init permission repo and service
 	permissionRepository, err := permission.NewRepository(svcEnv.DB())
@@ -199,7 +345,7 @@ func main() {
 		log.Fatal().Err(err).Msg("Could not initialize RoleRepository")
 	}
 
-	permissionSvc := permission.NewService(&svcEnv, permissionRepository, eventSvc, objectSvc)
+	permissionSvc := permission.NewService(svcEnv, permissionRepository, eventSvc, objectSvc, roleSvc, userSvc, tenantSvc, warrantSvc, checkSvc)
 
 	// Init pricing tier repo and service
 	pricingTierRepository, err := pricingtier.NewRepository(svcEnv.DB())
@@ -207,52 +353,63 @@ func main() {
 		log.Fatal().Err(err).Msg("Could not initialize PricingTierRepository")
 	}
 
-	pricingTierSvc := pricingtier.NewService(&svcEnv, pricingTierRepository, eventSvc, objectSvc)
+	pricingTierSvc := pricingtier.NewService(svcEnv, pricingTierRepository, eventSvc, objectSvc, userSvc, tenantSvc, warrantSvc)
 
-	// Init role repo and service
-	roleRepository, err := role.NewRepository(svcEnv.DB())
+	// Init feature repo and service
+	featureRepository, err := feature.NewRepository(svcEnv.DB())
 	if err != nil {
-		log.Fatal().Err(err).Msg("Could not initialize RoleRepository")
+		log.Fatal().Err(err).Msg("Could not initialize FeatureRepository")
 	}
 
-	roleSvc := role.NewService(&svcEnv, roleRepository, eventSvc, objectSvc)
-
-	// Init tenant repo and service
-	tenantRepository, err := tenant.NewRepository(svcEnv.DB())
-	if err != nil {
-		log.Fatal().Err(err).Msg("Could not initialize TenantRepository")
-	}
+	featureSvc := feature.NewService(svcEnv, featureRepository, eventSvc, objectSvc, pricingTierSvc, warrantSvc, checkSvc)
 
-	tenantSvc := tenant.NewService(&svcEnv, tenantRepository, eventSvc, objectSvc)
+	// Init quota service
+	quotaSvc := quota.NewService(svcEnv, quotaRepository, eventSvc, pricingTierSvc, featureSvc, checkSvc)
 
-	// Init user repo and service
-	userRepository, err := user.NewRepository(svcEnv.DB())
+	// Init object sync repo and service
+	objectSyncRepository, err := objectsync.NewRepository(svcEnv.DB())
 	if err != nil {
-		log.Fatal().Err(err).Msg("Could not initialize UserRepository")
+		log.Fatal().Err(err).Msg("Could not initialize ObjectSyncRepository")
 	}
 
-	userSvc := user.NewService(&svcEnv, userRepository, eventSvc, objectSvc)
-
-	svcs := []service.Service{
-		checkSvc,
-		eventSvc,
-		featureSvc,
-		objectSvc,
-		objectTypeSvc,
-		permissionSvc,
-		pricingTierSvc,
-		roleSvc,
-		tenantSvc,
-		userSvc,
-		warrantSvc,
+	objectSyncSvc := objectsync.NewService(*svcEnv, objectSyncRepository, config.ObjectSync.Sources, objectTypeSvc, objectSvc, userSvc, tenantSvc, warrantSvc)
+
+	// Init snapshot service
+	snapshotSvc := snapshot.NewService(*svcEnv, objectTypeSvc, objectSvc, userSvc, tenantSvc, roleSvc, permissionSvc, featureSvc, pricingTierSvc, warrantSvc, quotaSvc)
+
+	// Init manifest service
+	manifestSvc := manifest.NewService(*svcEnv, objectTypeSvc, roleSvc, permissionSvc, featureSvc, pricingTierSvc, warrantSvc)
+
+	// Init test suite service
+	testSuiteSvc := testsuite.NewService(*svcEnv, objectTypeSvc, warrantSvc, checkSvc)
+
+	return Services{
+		Change:      changeSvc,
+		Check:       checkSvc,
+		Event:       eventSvc,
+		Feature:     featureSvc,
+		Manifest:    manifestSvc,
+		Object:      objectSvc,
+		ObjectSync:  objectSyncSvc,
+		ObjectType:  objectTypeSvc,
+		Permission:  permissionSvc,
+		PricingTier: pricingTierSvc,
+		Quota:       quotaSvc,
+		Retention:   retentionSvc,
+		Role:        roleSvc,
+		Snapshot:    snapshotSvc,
+		Tenant:      tenantSvc,
+		TestSuite:   testSuiteSvc,
+		User:        userSvc,
+		Warrant:     warrantSvc,
 	}
+}
 
-	routes := make([]service.Route, 0)
-	for _, svc := range svcs {
-		routes = append(routes, svc.Routes()...)
+func newRetentionService(svcEnv ServiceEnv, config config.Config) retention.RetentionService {
+	retentionRepository, err := retention.NewRepository(svcEnv.DB())
+	if err != nil {
+		log.Fatal().Err(err).Msg("Could not initialize RetentionRepository")
 	}
 
-	log.Debug().Msgf("Listening on port %d", config.Port)
-	shutdownErr := http.ListenAndServe(fmt.Sprintf(":%d", config.Port), service.NewRouter(&config, "", routes, nil))
-	log.Fatal().Err(shutdownErr).Msg("")
+	return retention.NewService(svcEnv, retentionRepository, config.Retention)
 }
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE object MODIFY COLUMN objectId varchar(64) NOT NULL;
+ALTER TABLE warrant MODIFY COLUMN subjectId varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL;
+ALTER TABLE warrant MODIFY COLUMN objectId varchar(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL;
+
+COMMIT;
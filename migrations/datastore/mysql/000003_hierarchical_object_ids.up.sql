@@ -0,0 +1,8 @@
+BEGIN;
+
+-- NOTE: object ids can only contain ascii characters, so warrant object and subject ids are stored as ascii to keep warrant_uk_obj_rel_sub_ctx_hash within the 3072 byte key limit
+ALTER TABLE warrant MODIFY COLUMN objectId varchar(255) CHARACTER SET ascii COLLATE ascii_general_ci NOT NULL;
+ALTER TABLE warrant MODIFY COLUMN subjectId varchar(255) CHARACTER SET ascii COLLATE ascii_general_ci NOT NULL;
+ALTER TABLE object MODIFY COLUMN objectId varchar(255) NOT NULL;
+
+COMMIT;
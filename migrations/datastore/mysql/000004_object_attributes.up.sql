@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE object ADD COLUMN attributes json DEFAULT NULL AFTER objectId;
+
+COMMIT;
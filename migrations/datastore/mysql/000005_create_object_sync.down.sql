@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS objectSyncWarrant;
+DROP TABLE IF EXISTS objectSyncObject;
+DROP TABLE IF EXISTS objectSync;
+
+COMMIT;
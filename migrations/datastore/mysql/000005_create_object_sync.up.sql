@@ -0,0 +1,42 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS objectSync (
+  id int NOT NULL AUTO_INCREMENT,
+  objectType varchar(64) NOT NULL,
+  status varchar(16) NOT NULL,
+  syncCursor varchar(64) DEFAULT NULL,
+  rowsSynced bigint NOT NULL DEFAULT 0,
+  lastError text DEFAULT NULL,
+  lastSyncedAt timestamp(6) NULL DEFAULT NULL,
+  lastFullSyncAt timestamp(6) NULL DEFAULT NULL,
+  createdAt timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  updatedAt timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (id),
+  UNIQUE KEY object_sync_uk_object_type (objectType)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS objectSyncObject (
+  id int NOT NULL AUTO_INCREMENT,
+  objectType varchar(64) NOT NULL,
+  objectId varchar(255) NOT NULL,
+  createdAt timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (id),
+  UNIQUE KEY object_sync_object_uk_object_type_object_id (objectType, objectId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS objectSyncWarrant (
+  id int NOT NULL AUTO_INCREMENT,
+  objectType varchar(64) NOT NULL,
+  objectId varchar(255) NOT NULL,
+  foreignKey varchar(64) NOT NULL,
+  warrantObjectType varchar(64) NOT NULL,
+  warrantObjectId varchar(255) NOT NULL,
+  relation varchar(64) NOT NULL,
+  subjectType varchar(64) NOT NULL,
+  subjectId varchar(255) NOT NULL,
+  createdAt timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (id),
+  INDEX object_sync_warrant_idx_object_type_object_id (objectType, objectId)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS changeLogSequence;
+DROP TABLE IF EXISTS changeLog;
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS changeLog (
+  id bigint NOT NULL AUTO_INCREMENT,
+  sequenceNumber bigint DEFAULT NULL,
+  type varchar(64) NOT NULL,
+  resourceType varchar(64) NOT NULL,
+  resourceId varchar(255) NOT NULL,
+  data json DEFAULT NULL,
+  createdAt timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (id),
+  UNIQUE KEY change_log_uk_sequence_number (sequenceNumber),
+  INDEX change_log_idx_created_at (createdAt)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- NOTE: single row holding the last sequence number assigned to a committed change
+CREATE TABLE IF NOT EXISTS changeLogSequence (
+  id int NOT NULL,
+  lastSequenceNumber bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+INSERT INTO changeLogSequence (id, lastSequenceNumber) VALUES (1, 0);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE user DROP COLUMN deletionBatchId;
+ALTER TABLE tenant DROP COLUMN deletionBatchId;
+ALTER TABLE role DROP COLUMN deletionBatchId;
+ALTER TABLE object DROP INDEX object_idx_deletion_batch_id;
+ALTER TABLE object DROP COLUMN deletionBatchId;
+ALTER TABLE warrant DROP INDEX warrant_idx_deletion_batch_id;
+ALTER TABLE warrant DROP COLUMN deletionBatchId;
+
+COMMIT;
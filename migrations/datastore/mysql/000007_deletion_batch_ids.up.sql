@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE warrant ADD COLUMN deletionBatchId varchar(64) DEFAULT NULL AFTER deletedAt;
+ALTER TABLE warrant ADD INDEX warrant_idx_deletion_batch_id (deletionBatchId);
+ALTER TABLE object ADD COLUMN deletionBatchId varchar(64) DEFAULT NULL AFTER deletedAt;
+ALTER TABLE object ADD INDEX object_idx_deletion_batch_id (deletionBatchId);
+ALTER TABLE role ADD COLUMN deletionBatchId varchar(64) DEFAULT NULL AFTER deletedAt;
+ALTER TABLE tenant ADD COLUMN deletionBatchId varchar(64) DEFAULT NULL AFTER deletedAt;
+ALTER TABLE user ADD COLUMN deletionBatchId varchar(64) DEFAULT NULL AFTER deletedAt;
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+DROP TABLE IF EXISTS objectTypeHistory;
+DROP TABLE IF EXISTS warrantHistory;
+DROP TABLE IF EXISTS historyHorizon;
+
+-- NOTE: only the latest warrant with each key is kept so warrant_uk_obj_rel_sub_ctx_hash can cover deleted warrants again
+DELETE context
+FROM context
+JOIN warrant AS older ON older.id = context.warrantId
+JOIN warrant AS newer ON
+  newer.objectType = older.objectType AND
+  newer.objectId = older.objectId AND
+  newer.relation = older.relation AND
+  newer.subjectType = older.subjectType AND
+  newer.subjectId = older.subjectId AND
+  newer.subjectRelation = older.subjectRelation AND
+  newer.contextHash = older.contextHash AND
+  newer.id > older.id;
+
+DELETE older
+FROM warrant AS older
+JOIN warrant AS newer ON
+  newer.objectType = older.objectType AND
+  newer.objectId = older.objectId AND
+  newer.relation = older.relation AND
+  newer.subjectType = older.subjectType AND
+  newer.subjectId = older.subjectId AND
+  newer.subjectRelation = older.subjectRelation AND
+  newer.contextHash = older.contextHash AND
+  newer.id > older.id;
+
+ALTER TABLE warrant
+  DROP INDEX warrant_uk_obj_rel_sub_ctx_hash,
+  ADD UNIQUE KEY warrant_uk_obj_rel_sub_ctx_hash (objectType, objectId, relation, subjectType, subjectId, subjectRelation, contextHash),
+  DROP COLUMN isActive;
+
+COMMIT;
@@ -0,0 +1,49 @@
+BEGIN;
+
+-- NOTE: deleted warrants are moved out of warrant_uk_obj_rel_sub_ctx_hash so that re-creating a deleted warrant inserts a new row instead of undeleting the old one
+ALTER TABLE warrant ADD COLUMN isActive tinyint GENERATED ALWAYS AS (IF(deletedAt IS NULL, 1, NULL)) STORED;
+ALTER TABLE warrant
+  DROP INDEX warrant_uk_obj_rel_sub_ctx_hash,
+  ADD UNIQUE KEY warrant_uk_obj_rel_sub_ctx_hash (objectType, objectId, relation, subjectType, subjectId, subjectRelation, contextHash, isActive);
+
+-- NOTE: history is only recorded from the time of this migration, so point-in-time checks before historyHorizon are rejected
+CREATE TABLE IF NOT EXISTS historyHorizon (
+  id tinyint NOT NULL,
+  horizon timestamp(6) NOT NULL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+INSERT INTO historyHorizon (id, horizon) VALUES (1, CURRENT_TIMESTAMP(6));
+
+CREATE TABLE IF NOT EXISTS warrantHistory (
+  id bigint NOT NULL AUTO_INCREMENT,
+  warrantId int NOT NULL,
+  validFrom timestamp(6) NOT NULL,
+  validTo timestamp(6) NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  INDEX warrant_history_idx_warrant_id_valid_from (warrantId, validFrom),
+  INDEX warrant_history_idx_valid_to (validTo)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+INSERT INTO warrantHistory (warrantId, validFrom)
+SELECT warrant.id, historyHorizon.horizon
+FROM warrant, historyHorizon
+WHERE warrant.deletedAt IS NULL;
+
+CREATE TABLE IF NOT EXISTS objectTypeHistory (
+  id bigint NOT NULL AUTO_INCREMENT,
+  typeId varchar(64) NOT NULL,
+  definition json DEFAULT NULL,
+  validFrom timestamp(6) NOT NULL,
+  validTo timestamp(6) NULL DEFAULT NULL,
+  PRIMARY KEY (id),
+  INDEX object_type_history_idx_type_id_valid_from (typeId, validFrom),
+  INDEX object_type_history_idx_valid_to (validTo)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+INSERT INTO objectTypeHistory (typeId, definition, validFrom)
+SELECT objectType.typeId, objectType.definition, historyHorizon.horizon
+FROM objectType, historyHorizon
+WHERE objectType.deletedAt IS NULL;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS quotaUsage;
+DROP TABLE IF EXISTS quotaLimit;
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS quotaLimit (
+  id int NOT NULL AUTO_INCREMENT,
+  objectType varchar(64) NOT NULL,
+  objectId varchar(64) NOT NULL,
+  name varchar(64) NOT NULL,
+  limitValue bigint NOT NULL,
+  createdAt timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  updatedAt timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (id),
+  UNIQUE KEY quota_limit_uk_object_type_object_id_name (objectType, objectId, name),
+  INDEX quota_limit_idx_object_type_name (objectType, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE IF NOT EXISTS quotaUsage (
+  id int NOT NULL AUTO_INCREMENT,
+  objectType varchar(64) NOT NULL,
+  objectId varchar(64) NOT NULL,
+  name varchar(64) NOT NULL,
+  used bigint NOT NULL DEFAULT 0,
+  createdAt timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  updatedAt timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (id),
+  UNIQUE KEY quota_usage_uk_object_type_object_id_name (objectType, objectId, name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+COMMIT;
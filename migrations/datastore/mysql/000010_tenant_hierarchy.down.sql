@@ -0,0 +1,25 @@
+BEGIN;
+
+UPDATE objectType
+SET definition = '{"type": "tenant", "relations": {"admin": {}, "member": {"inheritIf": "manager"}, "manager": {"inheritIf": "admin"}}}'
+WHERE
+  typeId = 'tenant' AND
+  definition = CAST('{"type": "tenant", "relations": {"admin": {"inheritIf": "admin", "ofType": "tenant", "withRelation": "parent"}, "member": {"inheritIf": "manager"}, "parent": {}, "manager": {"inheritIf": "admin"}}}' AS JSON) AND
+  deletedAt IS NULL;
+
+UPDATE objectTypeHistory
+SET validTo = CURRENT_TIMESTAMP(6)
+WHERE
+  typeId = 'tenant' AND
+  validTo IS NULL AND
+  definition != (SELECT definition FROM objectType WHERE typeId = 'tenant' AND deletedAt IS NULL);
+
+INSERT INTO objectTypeHistory (typeId, definition, validFrom)
+SELECT typeId, definition, CURRENT_TIMESTAMP(6)
+FROM objectType
+WHERE
+  typeId = 'tenant' AND
+  deletedAt IS NULL AND
+  NOT EXISTS (SELECT 1 FROM objectTypeHistory WHERE typeId = 'tenant' AND validTo IS NULL);
+
+COMMIT;
@@ -0,0 +1,26 @@
+BEGIN;
+
+-- NOTE: tenant object types that were customized are left as is and can be updated through the tenant hierarchy settings
+UPDATE objectType
+SET definition = '{"type": "tenant", "relations": {"admin": {"inheritIf": "admin", "ofType": "tenant", "withRelation": "parent"}, "member": {"inheritIf": "manager"}, "parent": {}, "manager": {"inheritIf": "admin"}}}'
+WHERE
+  typeId = 'tenant' AND
+  definition = CAST('{"type": "tenant", "relations": {"admin": {}, "member": {"inheritIf": "manager"}, "manager": {"inheritIf": "admin"}}}' AS JSON) AND
+  deletedAt IS NULL;
+
+UPDATE objectTypeHistory
+SET validTo = CURRENT_TIMESTAMP(6)
+WHERE
+  typeId = 'tenant' AND
+  validTo IS NULL AND
+  definition != (SELECT definition FROM objectType WHERE typeId = 'tenant' AND deletedAt IS NULL);
+
+INSERT INTO objectTypeHistory (typeId, definition, validFrom)
+SELECT typeId, definition, CURRENT_TIMESTAMP(6)
+FROM objectType
+WHERE
+  typeId = 'tenant' AND
+  deletedAt IS NULL AND
+  NOT EXISTS (SELECT 1 FROM objectTypeHistory WHERE typeId = 'tenant' AND validTo IS NULL);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE object ALTER COLUMN object_id TYPE varchar(64);
+ALTER TABLE warrant ALTER COLUMN subject_id TYPE varchar(64);
+ALTER TABLE warrant ALTER COLUMN object_id TYPE varchar(64);
+
+COMMIT;
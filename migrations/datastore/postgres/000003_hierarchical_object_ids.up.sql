@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE warrant ALTER COLUMN object_id TYPE varchar(255);
+ALTER TABLE warrant ALTER COLUMN subject_id TYPE varchar(255);
+ALTER TABLE object ALTER COLUMN object_id TYPE varchar(255);
+
+COMMIT;
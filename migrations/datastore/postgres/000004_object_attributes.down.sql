@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE object DROP COLUMN attributes;
+
+COMMIT;
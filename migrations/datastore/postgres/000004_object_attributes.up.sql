@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE object ADD COLUMN attributes jsonb DEFAULT NULL;
+
+COMMIT;
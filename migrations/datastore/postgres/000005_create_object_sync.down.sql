@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS object_sync_warrant;
+DROP TABLE IF EXISTS object_sync_object;
+DROP TABLE IF EXISTS object_sync;
+
+COMMIT;
@@ -0,0 +1,43 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS object_sync (
+  id bigserial PRIMARY KEY,
+  object_type varchar(64) NOT NULL CONSTRAINT object_sync_uk_object_type UNIQUE,
+  status varchar(16) NOT NULL,
+  sync_cursor varchar(64) DEFAULT NULL,
+  rows_synced bigint NOT NULL DEFAULT 0,
+  last_error text DEFAULT NULL,
+  last_synced_at timestamp(6) NULL DEFAULT NULL,
+  last_full_sync_at timestamp(6) NULL DEFAULT NULL,
+  created_at timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  updated_at timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6)
+);
+
+CREATE TRIGGER update_updated_at
+BEFORE UPDATE ON object_sync
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
+
+CREATE TABLE IF NOT EXISTS object_sync_object (
+  id bigserial PRIMARY KEY,
+  object_type varchar(64) NOT NULL,
+  object_id varchar(255) NOT NULL,
+  created_at timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  CONSTRAINT object_sync_object_uk_object_type_object_id UNIQUE (object_type, object_id)
+);
+
+CREATE TABLE IF NOT EXISTS object_sync_warrant (
+  id bigserial PRIMARY KEY,
+  object_type varchar(64) NOT NULL,
+  object_id varchar(255) NOT NULL,
+  foreign_key varchar(64) NOT NULL,
+  warrant_object_type varchar(64) NOT NULL,
+  warrant_object_id varchar(255) NOT NULL,
+  relation varchar(64) NOT NULL,
+  subject_type varchar(64) NOT NULL,
+  subject_id varchar(255) NOT NULL,
+  created_at timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6)
+);
+
+CREATE INDEX IF NOT EXISTS object_sync_warrant_idx_object_type_object_id ON object_sync_warrant(object_type, object_id);
+
+COMMIT;
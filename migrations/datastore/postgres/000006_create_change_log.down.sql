@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS change_log_sequence;
+DROP TABLE IF EXISTS change_log;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS change_log (
+  id bigserial PRIMARY KEY,
+  sequence_number bigint DEFAULT NULL CONSTRAINT change_log_uk_sequence_number UNIQUE,
+  type varchar(64) NOT NULL,
+  resource_type varchar(64) NOT NULL,
+  resource_id varchar(255) NOT NULL,
+  data jsonb DEFAULT NULL,
+  created_at timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6)
+);
+
+CREATE INDEX IF NOT EXISTS change_log_idx_created_at ON change_log(created_at);
+CREATE INDEX IF NOT EXISTS change_log_idx_unsequenced ON change_log(id) WHERE sequence_number IS NULL;
+
+-- NOTE: single row holding the last sequence number assigned to a committed change
+CREATE TABLE IF NOT EXISTS change_log_sequence (
+  id int PRIMARY KEY,
+  last_sequence_number bigint NOT NULL DEFAULT 0
+);
+
+INSERT INTO change_log_sequence (id, last_sequence_number) VALUES (1, 0);
+
+COMMIT;
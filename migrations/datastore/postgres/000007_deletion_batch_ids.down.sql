@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE "user" DROP COLUMN deletion_batch_id;
+ALTER TABLE tenant DROP COLUMN deletion_batch_id;
+ALTER TABLE role DROP COLUMN deletion_batch_id;
+DROP INDEX IF EXISTS object_idx_deletion_batch_id;
+ALTER TABLE object DROP COLUMN deletion_batch_id;
+DROP INDEX IF EXISTS warrant_idx_deletion_batch_id;
+ALTER TABLE warrant DROP COLUMN deletion_batch_id;
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE warrant ADD COLUMN deletion_batch_id varchar(64) DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS warrant_idx_deletion_batch_id ON warrant(deletion_batch_id);
+ALTER TABLE object ADD COLUMN deletion_batch_id varchar(64) DEFAULT NULL;
+CREATE INDEX IF NOT EXISTS object_idx_deletion_batch_id ON object(deletion_batch_id);
+ALTER TABLE role ADD COLUMN deletion_batch_id varchar(64) DEFAULT NULL;
+ALTER TABLE tenant ADD COLUMN deletion_batch_id varchar(64) DEFAULT NULL;
+ALTER TABLE "user" ADD COLUMN deletion_batch_id varchar(64) DEFAULT NULL;
+
+COMMIT;
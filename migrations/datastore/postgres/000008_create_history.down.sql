@@ -0,0 +1,36 @@
+BEGIN;
+
+DROP TABLE IF EXISTS object_type_history;
+DROP TABLE IF EXISTS warrant_history;
+DROP TABLE IF EXISTS history_horizon;
+
+-- NOTE: only the latest warrant with each key is kept so warrant_uk_obj_rel_sub_ctx_hash can cover deleted warrants again
+DELETE FROM context
+USING warrant AS older, warrant AS newer
+WHERE
+  older.id = context.warrant_id AND
+  newer.object_type = older.object_type AND
+  newer.object_id = older.object_id AND
+  newer.relation = older.relation AND
+  newer.subject_type = older.subject_type AND
+  newer.subject_id = older.subject_id AND
+  newer.subject_relation = older.subject_relation AND
+  newer.context_hash = older.context_hash AND
+  newer.id > older.id;
+
+DELETE FROM warrant AS older
+USING warrant AS newer
+WHERE
+  newer.object_type = older.object_type AND
+  newer.object_id = older.object_id AND
+  newer.relation = older.relation AND
+  newer.subject_type = older.subject_type AND
+  newer.subject_id = older.subject_id AND
+  newer.subject_relation = older.subject_relation AND
+  newer.context_hash = older.context_hash AND
+  newer.id > older.id;
+
+DROP INDEX IF EXISTS warrant_uk_obj_rel_sub_ctx_hash;
+ALTER TABLE warrant ADD CONSTRAINT warrant_uk_obj_rel_sub_ctx_hash UNIQUE (object_type, object_id, relation, subject_type, subject_id, subject_relation, context_hash);
+
+COMMIT;
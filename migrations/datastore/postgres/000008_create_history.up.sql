@@ -0,0 +1,46 @@
+BEGIN;
+
+-- NOTE: deleted warrants are moved out of warrant_uk_obj_rel_sub_ctx_hash so that re-creating a deleted warrant inserts a new row instead of undeleting the old one
+ALTER TABLE warrant DROP CONSTRAINT IF EXISTS warrant_uk_obj_rel_sub_ctx_hash;
+CREATE UNIQUE INDEX IF NOT EXISTS warrant_uk_obj_rel_sub_ctx_hash ON warrant(object_type, object_id, relation, subject_type, subject_id, subject_relation, context_hash) WHERE deleted_at IS NULL;
+
+-- NOTE: history is only recorded from the time of this migration, so point-in-time checks before history_horizon are rejected
+CREATE TABLE IF NOT EXISTS history_horizon (
+  id smallint PRIMARY KEY,
+  horizon timestamp(6) NOT NULL
+);
+
+INSERT INTO history_horizon (id, horizon) VALUES (1, CURRENT_TIMESTAMP(6));
+
+CREATE TABLE IF NOT EXISTS warrant_history (
+  id bigserial PRIMARY KEY,
+  warrant_id bigint NOT NULL,
+  valid_from timestamp(6) NOT NULL,
+  valid_to timestamp(6) NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS warrant_history_idx_warrant_id_valid_from ON warrant_history(warrant_id, valid_from);
+CREATE INDEX IF NOT EXISTS warrant_history_idx_valid_to ON warrant_history(valid_to);
+
+INSERT INTO warrant_history (warrant_id, valid_from)
+SELECT warrant.id, history_horizon.horizon
+FROM warrant, history_horizon
+WHERE warrant.deleted_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS object_type_history (
+  id bigserial PRIMARY KEY,
+  type_id varchar(64) NOT NULL,
+  definition jsonb DEFAULT NULL,
+  valid_from timestamp(6) NOT NULL,
+  valid_to timestamp(6) NULL DEFAULT NULL
+);
+
+CREATE INDEX IF NOT EXISTS object_type_history_idx_type_id_valid_from ON object_type_history(type_id, valid_from);
+CREATE INDEX IF NOT EXISTS object_type_history_idx_valid_to ON object_type_history(valid_to);
+
+INSERT INTO object_type_history (type_id, definition, valid_from)
+SELECT object_type.type_id, object_type.definition, history_horizon.horizon
+FROM object_type, history_horizon
+WHERE object_type.deleted_at IS NULL;
+
+COMMIT;
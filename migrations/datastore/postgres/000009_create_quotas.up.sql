@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS quota_limit (
+  id bigserial PRIMARY KEY,
+  object_type varchar(64) NOT NULL,
+  object_id varchar(64) NOT NULL,
+  name varchar(64) NOT NULL,
+  limit_value bigint NOT NULL,
+  created_at timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  updated_at timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  CONSTRAINT quota_limit_uk_object_type_object_id_name UNIQUE (object_type, object_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS quota_limit_idx_object_type_name ON quota_limit(object_type, name);
+
+CREATE TRIGGER update_updated_at
+BEFORE UPDATE ON quota_limit
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
+
+CREATE TABLE IF NOT EXISTS quota_usage (
+  id bigserial PRIMARY KEY,
+  object_type varchar(64) NOT NULL,
+  object_id varchar(64) NOT NULL,
+  name varchar(64) NOT NULL,
+  used bigint NOT NULL DEFAULT 0,
+  created_at timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  updated_at timestamp(6) NULL DEFAULT CURRENT_TIMESTAMP(6),
+  CONSTRAINT quota_usage_uk_object_type_object_id_name UNIQUE (object_type, object_id, name)
+);
+
+CREATE TRIGGER update_updated_at
+BEFORE UPDATE ON quota_usage
+FOR EACH ROW EXECUTE PROCEDURE update_updated_at();
+
+COMMIT;
@@ -0,0 +1,25 @@
+BEGIN;
+
+UPDATE object_type
+SET definition = '{"type": "tenant", "relations": {"admin": {}, "member": {"inheritIf": "manager"}, "manager": {"inheritIf": "admin"}}}'
+WHERE
+  type_id = 'tenant' AND
+  definition = '{"type": "tenant", "relations": {"admin": {"inheritIf": "admin", "ofType": "tenant", "withRelation": "parent"}, "member": {"inheritIf": "manager"}, "parent": {}, "manager": {"inheritIf": "admin"}}}'::jsonb AND
+  deleted_at IS NULL;
+
+UPDATE object_type_history
+SET valid_to = CURRENT_TIMESTAMP(6)
+WHERE
+  type_id = 'tenant' AND
+  valid_to IS NULL AND
+  definition != (SELECT definition FROM object_type WHERE type_id = 'tenant' AND deleted_at IS NULL);
+
+INSERT INTO object_type_history (type_id, definition, valid_from)
+SELECT type_id, definition, CURRENT_TIMESTAMP(6)
+FROM object_type
+WHERE
+  type_id = 'tenant' AND
+  deleted_at IS NULL AND
+  NOT EXISTS (SELECT 1 FROM object_type_history WHERE type_id = 'tenant' AND valid_to IS NULL);
+
+COMMIT;
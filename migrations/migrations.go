@@ -0,0 +1,8 @@
+// Package migrations embeds the datastore and eventstore migrations in the
+// binary so databases can be migrated without fetching them at startup.
+package migrations
+
+import "embed"
+
+//go:embed datastore eventstore
+var FS embed.FS
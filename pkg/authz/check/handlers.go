@@ -20,6 +20,13 @@ func (svc CheckService) Routes() []service.Route {
 			),
 			EnableSessionAuth: true,
 		},
+
+		// Access diff
+		{
+			Pattern: "/v1/access-diff",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AccessDiffHandler),
+		},
 	}
 }
 
@@ -32,6 +39,11 @@ func AuthorizeHandler(svc CheckService, w http.ResponseWriter, r *http.Request)
 			return err
 		}
 
+		err = svc.ValidateImpersonation(r.Context(), authInfo)
+		if err != nil {
+			return err
+		}
+
 		warrantSpecs := make([]warrant.WarrantSpec, 0)
 		for _, warrantSpec := range sessionCheckManySpec.Warrants {
 			warrantSpecs = append(warrantSpecs, warrant.WarrantSpec{
@@ -45,12 +57,14 @@ func AuthorizeHandler(svc CheckService, w http.ResponseWriter, r *http.Request)
 			})
 		}
 
+		// NOTE: session checks don't accept contextual warrants since they would let users grant themselves access
 		checkManySpec := CheckManySpec{
 			Op:             sessionCheckManySpec.Op,
 			Warrants:       warrantSpecs,
 			Context:        sessionCheckManySpec.Context,
 			ConsistentRead: sessionCheckManySpec.ConsistentRead,
 			Debug:          sessionCheckManySpec.Debug,
+			AsOf:           sessionCheckManySpec.AsOf,
 		}
 
 		checkResult, err := svc.CheckMany(r.Context(), authInfo, &checkManySpec)
@@ -76,3 +90,19 @@ func AuthorizeHandler(svc CheckService, w http.ResponseWriter, r *http.Request)
 	service.SendJSONResponse(w, checkResult)
 	return nil
 }
+
+func AccessDiffHandler(svc CheckService, w http.ResponseWriter, r *http.Request) error {
+	var accessDiffSpec AccessDiffSpec
+	err := service.ParseJSONBody(r.Body, &accessDiffSpec)
+	if err != nil {
+		return err
+	}
+
+	accessDiff, err := svc.Diff(r.Context(), accessDiffSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, accessDiff)
+	return nil
+}
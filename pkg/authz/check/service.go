@@ -2,10 +2,15 @@ package authz
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"reflect"
+	"sort"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	object "github.com/warrant-dev/warrant/pkg/authz/object"
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
 	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
 	wntContext "github.com/warrant-dev/warrant/pkg/context"
@@ -13,45 +18,85 @@ import (
 	"github.com/warrant-dev/warrant/pkg/service"
 )
 
+// MaxAccessDiffObjects is the max number of objects an access diff checks
+const MaxAccessDiffObjects = 1000
+
+// MaxContextualWarrants is the max number of contextual warrants a check can include
+const MaxContextualWarrants = 100
+
+// errCheckCycle is returned by a check that re-enters a check already in
+// progress further up the call stack. Its result is unknown, so rules that
+// match it treat it as not matched, but rules that negate it (noneOf and
+// butNot) deny rather than grant.
+var errCheckCycle = errors.New("check cycle")
+
 type CheckService struct {
 	service.BaseService
 	warrantRepo   warrant.WarrantRepository
+	objectRepo    object.ObjectRepository
 	eventSvc      event.EventService
 	ctxSvc        wntContext.ContextService
 	objectTypeSvc objecttype.ObjectTypeService
 }
 
-func NewService(env service.Env, warrantRepo warrant.WarrantRepository, ctxSvc wntContext.ContextService, eventSvc event.EventService, objectTypeSvc objecttype.ObjectTypeService) CheckService {
+func NewService(env service.Env, warrantRepo warrant.WarrantRepository, objectRepo object.ObjectRepository, ctxSvc wntContext.ContextService, eventSvc event.EventService, objectTypeSvc objecttype.ObjectTypeService) CheckService {
 	return CheckService{
 		BaseService:   service.NewBaseService(env),
 		warrantRepo:   warrantRepo,
+		objectRepo:    objectRepo,
 		ctxSvc:        ctxSvc,
 		eventSvc:      eventSvc,
 		objectTypeSvc: objectTypeSvc,
 	}
 }
 
-func (svc CheckService) getWithContextMatch(ctx context.Context, spec warrant.WarrantSpec) (*warrant.WarrantSpec, error) {
-	warrant, err := svc.warrantRepo.GetWithContextMatch(ctx, spec.ObjectType, spec.ObjectId, spec.Relation, spec.Subject.ObjectType, spec.Subject.ObjectId, spec.Subject.Relation, spec.Context.ToHash())
-	if err != nil || warrant == nil {
+func (svc CheckService) getWithContextMatch(ctx context.Context, spec warrant.WarrantSpec, asOf *time.Time, contextualWarrants []warrant.WarrantSpec) (*warrant.WarrantSpec, error) {
+	objectTypeSpec, err := svc.objectTypeSvc.GetByTypeIdAsOf(ctx, spec.ObjectType, asOf)
+	if err != nil {
 		return nil, err
 	}
 
-	contextSetSpec, err := svc.ctxSvc.ListByWarrantId(ctx, []int64{warrant.GetID()})
+	objectIds := objectTypeSpec.MatchingObjectIds(spec.ObjectId)
+	warrant, err := svc.warrantRepo.GetWithContextMatch(ctx, spec.ObjectType, objectIds, spec.Relation, spec.Subject.ObjectType, spec.Subject.ObjectId, spec.Subject.Relation, spec.Context.ToHash(), asOf)
 	if err != nil {
 		return nil, err
 	}
 
+	if warrant == nil {
+		for _, contextualWarrant := range matchingContextualWarrants(contextualWarrants, spec.ObjectType, objectIds, spec.Relation, spec.Subject.ObjectType, spec.Context) {
+			if contextualWarrant.Subject.ObjectId == spec.Subject.ObjectId && contextualWarrant.Subject.Relation == spec.Subject.Relation {
+				return &contextualWarrant, nil
+			}
+		}
+
+		return nil, nil
+	}
+
+	// NOTE: warrant contexts aren't versioned, so a past warrant's context is
+	// derived from its context hash, which only matches the checked context
 	warrantSpec := warrant.ToWarrantSpec()
+	if asOf != nil {
+		if warrant.GetContextHash() != "" {
+			warrantSpec.Context = spec.Context
+		}
+
+		return warrantSpec, nil
+	}
+
+	contextSetSpec, err := svc.ctxSvc.ListByWarrantId(ctx, []int64{warrant.GetID()})
+	if err != nil {
+		return nil, err
+	}
+
 	warrantSpec.Context = contextSetSpec[warrant.GetID()]
 	return warrantSpec, nil
 }
 
-func (svc CheckService) getMatchingSubjects(ctx context.Context, objectType string, objectId string, relation string, subjectType string, wntCtx wntContext.ContextSetSpec) ([]warrant.WarrantSpec, error) {
+func (svc CheckService) getMatchingSubjects(ctx context.Context, objectType string, objectId string, relation string, subjectType string, wntCtx wntContext.ContextSetSpec, asOf *time.Time, contextualWarrants []warrant.WarrantSpec) ([]warrant.WarrantSpec, error) {
 	log.Debug().Msgf("Getting matching subjects for %s:%s#%s@%s:___%s", objectType, objectId, relation, subjectType, wntCtx)
 
 	warrantSpecs := make([]warrant.WarrantSpec, 0)
-	objectTypeSpec, err := svc.objectTypeSvc.GetByTypeId(ctx, objectType)
+	objectTypeSpec, err := svc.objectTypeSvc.GetByTypeIdAsOf(ctx, objectType, asOf)
 	if err != nil {
 		return warrantSpecs, err
 	}
@@ -63,10 +108,11 @@ func (svc CheckService) getMatchingSubjects(ctx context.Context, objectType stri
 	warrants, err := svc.warrantRepo.GetAllMatchingObjectAndRelation(
 		ctx,
 		objectType,
-		objectId,
+		append([]string{objectId}, objectTypeSpec.PrefixWildcardObjectIds(objectId)...),
 		relation,
 		subjectType,
 		wntCtx.ToHash(),
+		asOf,
 	)
 	if err != nil {
 		log.Err(err).Msg("Error fetching warrants for object")
@@ -87,6 +133,7 @@ func (svc CheckService) getMatchingSubjects(ctx context.Context, objectType stri
 		objectId,
 		relation,
 		wntCtx.ToHash(),
+		asOf,
 	)
 	if err != nil {
 		log.Err(err).Msg("Error fetching warrants matching wildcard")
@@ -97,9 +144,124 @@ func (svc CheckService) getMatchingSubjects(ctx context.Context, objectType stri
 		warrantSpecs = append(warrantSpecs, *warrant.ToWarrantSpec())
 	}
 
+	warrantSpecs = append(warrantSpecs, matchingContextualWarrants(contextualWarrants, objectType, objectTypeSpec.MatchingObjectIds(objectId), relation, subjectType, wntCtx)...)
 	return warrantSpecs, nil
 }
 
+// validateContextualWarrants checks that each contextual warrant could be
+// created as a stored warrant
+func (svc CheckService) validateContextualWarrants(ctx context.Context, contextualWarrants []warrant.WarrantSpec, asOf *time.Time) error {
+	if len(contextualWarrants) > MaxContextualWarrants {
+		return service.NewInvalidParameterError("contextualWarrants", fmt.Sprintf("must have at most %d warrants", MaxContextualWarrants))
+	}
+
+	for i, contextualWarrant := range contextualWarrants {
+		if contextualWarrant.Subject == nil {
+			return service.NewInvalidParameterError("contextualWarrants", fmt.Sprintf("warrant %d must have a subject", i+1))
+		}
+
+		objectTypeSpec, err := svc.objectTypeSvc.GetByTypeIdAsOf(ctx, contextualWarrant.ObjectType, asOf)
+		if err != nil {
+			return service.NewInvalidParameterError("contextualWarrants", fmt.Sprintf("warrant %d has an object type that does not exist", i+1))
+		}
+
+		if _, exists := objectTypeSpec.Relations[contextualWarrant.Relation]; !exists {
+			return service.NewInvalidParameterError("contextualWarrants", fmt.Sprintf("warrant %d has a relation that does not exist on object type %s", i+1, contextualWarrant.ObjectType))
+		}
+
+		err = objectTypeSpec.ValidateObjectId(contextualWarrant.ObjectId)
+		if err != nil {
+			return service.NewInvalidParameterError("contextualWarrants", fmt.Sprintf("warrant %d has an invalid objectId", i+1))
+		}
+
+		err = svc.objectTypeSvc.ValidateObjectId(ctx, contextualWarrant.Subject.ObjectType, contextualWarrant.Subject.ObjectId)
+		if err != nil {
+			return service.NewInvalidParameterError("contextualWarrants", fmt.Sprintf("warrant %d has an invalid subject objectId", i+1))
+		}
+	}
+
+	return nil
+}
+
+// matchingContextualWarrants returns the contextual warrants whose object id
+// is one of objectIds (e.g. an object's id and "*") with the given object type,
+// relation and subject type ("%" matches any subject type) and whose context is
+// empty or matches wntCtx
+func matchingContextualWarrants(contextualWarrants []warrant.WarrantSpec, objectType string, objectIds []string, relation string, subjectType string, wntCtx wntContext.ContextSetSpec) []warrant.WarrantSpec {
+	matchingWarrants := make([]warrant.WarrantSpec, 0)
+	for _, contextualWarrant := range contextualWarrants {
+		if contextualWarrant.ObjectType != objectType || contextualWarrant.Relation != relation {
+			continue
+		}
+
+		if subjectType != "%" && contextualWarrant.Subject.ObjectType != subjectType {
+			continue
+		}
+
+		if len(contextualWarrant.Context) > 0 && contextualWarrant.Context.ToHash() != wntCtx.ToHash() {
+			continue
+		}
+
+		for _, objectId := range objectIds {
+			if contextualWarrant.ObjectId == objectId {
+				matchingWarrants = append(matchingWarrants, contextualWarrant)
+				break
+			}
+		}
+	}
+
+	return matchingWarrants
+}
+
+func (svc CheckService) getConditionAttributes(ctx context.Context, spec warrant.WarrantSpec, condition *objecttype.AttributeCondition) (map[string]map[string]interface{}, error) {
+	attributes := make(map[string]map[string]interface{})
+	for _, source := range condition.Sources() {
+		if _, ok := attributes[source]; ok {
+			continue
+		}
+
+		switch source {
+		case objecttype.AttributeSourceObject:
+			objectAttributes, err := svc.getObjectAttributes(ctx, spec.ObjectType, spec.ObjectId)
+			if err != nil {
+				return attributes, err
+			}
+
+			attributes[source] = objectAttributes
+		case objecttype.AttributeSourceSubject:
+			subjectAttributes, err := svc.getObjectAttributes(ctx, spec.Subject.ObjectType, spec.Subject.ObjectId)
+			if err != nil {
+				return attributes, err
+			}
+
+			attributes[source] = subjectAttributes
+		case objecttype.AttributeSourceContext:
+			contextAttributes := make(map[string]interface{})
+			for name, value := range spec.Context {
+				contextAttributes[name] = value
+			}
+
+			attributes[source] = contextAttributes
+		}
+	}
+
+	return attributes, nil
+}
+
+func (svc CheckService) getObjectAttributes(ctx context.Context, objectType string, objectId string) (map[string]interface{}, error) {
+	obj, err := svc.objectRepo.GetByObjectTypeAndId(ctx, objectType, objectId)
+	if err != nil {
+		switch err.(type) {
+		case *service.RecordNotFoundError:
+			return nil, nil
+		default:
+			return nil, err
+		}
+	}
+
+	return obj.ToObjectSpec().Attributes, nil
+}
+
 func (svc CheckService) checkRule(ctx context.Context, authInfo *service.AuthInfo, warrantCheck CheckSpec, rule *objecttype.RelationRule) (match bool, decisionPath []warrant.WarrantSpec, err error) {
 	warrantSpec := warrantCheck.WarrantSpec
 	if rule == nil {
@@ -111,8 +273,14 @@ func (svc CheckService) checkRule(ctx context.Context, authInfo *service.AuthInf
 		// No match found
 		return false, decisionPath, nil
 	case objecttype.InheritIfAllOf:
+		cycle := false
 		for _, r := range rule.Rules {
 			isMatch, matchedPath, err := svc.checkRule(ctx, authInfo, warrantCheck, &r)
+			if errors.Is(err, errCheckCycle) {
+				cycle = true
+				continue
+			}
+
 			if err != nil {
 				return false, decisionPath, err
 			}
@@ -123,10 +291,20 @@ func (svc CheckService) checkRule(ctx context.Context, authInfo *service.AuthInf
 			}
 		}
 
+		if cycle {
+			return false, decisionPath, errCheckCycle
+		}
+
 		return true, decisionPath, nil
 	case objecttype.InheritIfAnyOf:
+		cycle := false
 		for _, r := range rule.Rules {
 			isMatch, matchedPath, err := svc.checkRule(ctx, authInfo, warrantCheck, &r)
+			if errors.Is(err, errCheckCycle) {
+				cycle = true
+				continue
+			}
+
 			if err != nil {
 				return false, decisionPath, err
 			}
@@ -137,9 +315,15 @@ func (svc CheckService) checkRule(ctx context.Context, authInfo *service.AuthInf
 			}
 		}
 
+		if cycle {
+			return false, decisionPath, errCheckCycle
+		}
+
 		return false, decisionPath, nil
 	case objecttype.InheritIfNoneOf:
 		for _, r := range rule.Rules {
+			// NOTE: a rule that re-enters a check in progress isn't known not to
+			// match, so the check is denied instead of granted
 			isMatch, matchedPath, err := svc.checkRule(ctx, authInfo, warrantCheck, &r)
 			if err != nil {
 				return false, decisionPath, err
@@ -152,11 +336,92 @@ func (svc CheckService) checkRule(ctx context.Context, authInfo *service.AuthInf
 		}
 
 		return true, decisionPath, nil
+	case objecttype.InheritIfButNot:
+		if len(rule.Rules) != 2 {
+			return false, decisionPath, service.NewInternalError("butNot rules must contain exactly 2 rules")
+		}
+
+		isMatch, matchedPath, err := svc.checkRule(ctx, authInfo, warrantCheck, &rule.Rules[0])
+		if err != nil {
+			return false, decisionPath, err
+		}
+
+		decisionPath = append(decisionPath, matchedPath...)
+		if !isMatch {
+			return false, decisionPath, nil
+		}
+
+		// NOTE: an excluded rule that re-enters a check in progress isn't known
+		// not to match, so the check is denied instead of granted
+		isExcluded, excludedPath, err := svc.checkRule(ctx, authInfo, warrantCheck, &rule.Rules[1])
+		if err != nil {
+			return false, decisionPath, err
+		}
+
+		decisionPath = append(decisionPath, excludedPath...)
+		if isExcluded {
+			return false, decisionPath, nil
+		}
+
+		return true, decisionPath, nil
+	case objecttype.InheritIfAtLeast:
+		numMatched := 0
+		cycle := false
+		for i, r := range rule.Rules {
+			// Stop early if the remaining rules can no longer satisfy the count
+			if numMatched+len(rule.Rules)-i < rule.Count {
+				break
+			}
+
+			isMatch, matchedPath, err := svc.checkRule(ctx, authInfo, warrantCheck, &r)
+			if errors.Is(err, errCheckCycle) {
+				cycle = true
+				continue
+			}
+
+			if err != nil {
+				return false, decisionPath, err
+			}
+
+			decisionPath = append(decisionPath, matchedPath...)
+			if isMatch {
+				numMatched++
+			}
+
+			if numMatched >= rule.Count {
+				return true, decisionPath, nil
+			}
+		}
+
+		if cycle {
+			return false, decisionPath, errCheckCycle
+		}
+
+		return false, decisionPath, nil
+	case objecttype.InheritIfCondition:
+		if rule.Condition == nil {
+			return false, decisionPath, service.NewInternalError("condition rules must contain a condition")
+		}
+
+		// NOTE: object attributes aren't versioned, so they can't be evaluated as of a past time
+		if warrantCheck.AsOf != nil && rule.Condition.UsesObjectAttributes() {
+			return false, decisionPath, service.NewInvalidParameterError("asOf", fmt.Sprintf("cannot be used to check %s#%s, which has a condition on object or subject attributes", warrantSpec.ObjectType, warrantSpec.Relation))
+		}
+
+		attributes, err := svc.getConditionAttributes(ctx, warrantSpec, rule.Condition)
+		if err != nil {
+			return false, decisionPath, err
+		}
+
+		log.Debug().Msgf("Evaluating condition %s for %s", rule.Condition, warrantSpec)
+		return rule.Condition.Evaluate(attributes), decisionPath, nil
 	default:
 		if rule.OfType == "" && rule.WithRelation == "" {
-			return svc.Check(ctx, authInfo, CheckSpec{
-				ConsistentRead: warrantCheck.ConsistentRead,
-				Debug:          warrantCheck.Debug,
+			return svc.check(ctx, authInfo, CheckSpec{
+				ConsistentRead:     warrantCheck.ConsistentRead,
+				Debug:              warrantCheck.Debug,
+				AsOf:               warrantCheck.AsOf,
+				ContextualWarrants: warrantCheck.ContextualWarrants,
 				WarrantSpec: warrant.WarrantSpec{
 					ObjectType: warrantSpec.ObjectType,
 					ObjectId:   warrantSpec.ObjectId,
@@ -167,15 +432,18 @@ func (svc CheckService) checkRule(ctx context.Context, authInfo *service.AuthInf
 			})
 		}
 
-		matchingWarrants, err := svc.getMatchingSubjects(ctx, warrantSpec.ObjectType, warrantSpec.ObjectId, rule.WithRelation, rule.OfType, warrantSpec.Context)
+		matchingWarrants, err := svc.getMatchingSubjects(ctx, warrantSpec.ObjectType, warrantSpec.ObjectId, rule.WithRelation, rule.OfType, warrantSpec.Context, warrantCheck.AsOf, warrantCheck.ContextualWarrants)
 		if err != nil {
 			return false, decisionPath, err
 		}
 
+		cycle := false
 		for _, matchingWarrant := range matchingWarrants {
-			match, decisionPath, err := svc.Check(ctx, authInfo, CheckSpec{
-				ConsistentRead: warrantCheck.ConsistentRead,
-				Debug:          warrantCheck.Debug,
+			match, decisionPath, err := svc.check(ctx, authInfo, CheckSpec{
+				ConsistentRead:     warrantCheck.ConsistentRead,
+				Debug:              warrantCheck.Debug,
+				AsOf:               warrantCheck.AsOf,
+				ContextualWarrants: warrantCheck.ContextualWarrants,
 				WarrantSpec: warrant.WarrantSpec{
 					ObjectType: matchingWarrant.Subject.ObjectType,
 					ObjectId:   matchingWarrant.Subject.ObjectId,
@@ -184,6 +452,11 @@ func (svc CheckService) checkRule(ctx context.Context, authInfo *service.AuthInf
 					Context:    warrantSpec.Context,
 				},
 			})
+			if errors.Is(err, errCheckCycle) {
+				cycle = true
+				continue
+			}
+
 			if err != nil {
 				return false, decisionPath, err
 			}
@@ -193,25 +466,65 @@ func (svc CheckService) checkRule(ctx context.Context, authInfo *service.AuthInf
 			}
 		}
 
+		if cycle {
+			return false, decisionPath, errCheckCycle
+		}
+
 		return false, decisionPath, nil
 	}
 }
 
+// validateHistoryHorizon returns an error if the given time is before history
+// was recorded from, since the relation graph at that time isn't known
+func (svc CheckService) validateHistoryHorizon(ctx context.Context, param string, asOf time.Time) error {
+	horizon, err := svc.warrantRepo.GetHistoryHorizon(ctx)
+	if err != nil {
+		return err
+	}
+
+	if asOf.Before(horizon) {
+		return service.NewInvalidParameterError(param, "must not be before the earliest recorded history")
+	}
+
+	return nil
+}
+
 func (svc CheckService) CheckMany(ctx context.Context, authInfo *service.AuthInfo, warrantCheck *CheckManySpec) (*CheckResultSpec, error) {
 	start := time.Now().UTC()
 	if warrantCheck.Op != "" && warrantCheck.Op != objecttype.InheritIfAllOf && warrantCheck.Op != objecttype.InheritIfAnyOf {
 		return nil, service.NewInvalidParameterError("op", "must be either anyOf or allOf")
 	}
 
+	if warrantCheck.AsOf != nil {
+		if warrantCheck.AsOf.After(start) {
+			return nil, service.NewInvalidParameterError("asOf", "must not be in the future")
+		}
+
+		asOf := warrantCheck.AsOf.UTC()
+		err := svc.validateHistoryHorizon(ctx, "asOf", asOf)
+		if err != nil {
+			return nil, err
+		}
+
+		warrantCheck.AsOf = &asOf
+	}
+
+	err := svc.validateContextualWarrants(ctx, warrantCheck.ContextualWarrants, warrantCheck.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
 	var checkResult CheckResultSpec
 	checkResult.DecisionPath = make(map[string][]warrant.WarrantSpec, 0)
 	if warrantCheck.Op == objecttype.InheritIfAllOf {
 		var processingTime int64
 		for _, warrantSpec := range warrantCheck.Warrants {
 			match, decisionPath, err := svc.Check(ctx, authInfo, CheckSpec{
-				WarrantSpec:    warrantSpec,
-				ConsistentRead: warrantCheck.ConsistentRead,
-				Debug:          warrantCheck.Debug,
+				WarrantSpec:        warrantSpec,
+				ConsistentRead:     warrantCheck.ConsistentRead,
+				Debug:              warrantCheck.Debug,
+				AsOf:               warrantCheck.AsOf,
+				ContextualWarrants: warrantCheck.ContextualWarrants,
 			})
 			if err != nil {
 				return nil, err
@@ -240,9 +553,11 @@ func (svc CheckService) CheckMany(ctx context.Context, authInfo *service.AuthInf
 		var processingTime int64
 		for _, warrantSpec := range warrantCheck.Warrants {
 			match, decisionPath, err := svc.Check(ctx, authInfo, CheckSpec{
-				WarrantSpec:    warrantSpec,
-				ConsistentRead: warrantCheck.ConsistentRead,
-				Debug:          warrantCheck.Debug,
+				WarrantSpec:        warrantSpec,
+				ConsistentRead:     warrantCheck.ConsistentRead,
+				Debug:              warrantCheck.Debug,
+				AsOf:               warrantCheck.AsOf,
+				ContextualWarrants: warrantCheck.ContextualWarrants,
 			})
 			if err != nil {
 				return nil, err
@@ -273,9 +588,11 @@ func (svc CheckService) CheckMany(ctx context.Context, authInfo *service.AuthInf
 
 	warrantSpec := warrantCheck.Warrants[0]
 	match, decisionPath, err := svc.Check(ctx, authInfo, CheckSpec{
-		WarrantSpec:    warrantSpec,
-		ConsistentRead: warrantCheck.ConsistentRead,
-		Debug:          warrantCheck.Debug,
+		WarrantSpec:        warrantSpec,
+		ConsistentRead:     warrantCheck.ConsistentRead,
+		Debug:              warrantCheck.Debug,
+		AsOf:               warrantCheck.AsOf,
+		ContextualWarrants: warrantCheck.ContextualWarrants,
 	})
 	if err != nil {
 		return nil, err
@@ -300,7 +617,18 @@ func (svc CheckService) CheckMany(ctx context.Context, authInfo *service.AuthInf
 }
 
 // Check returns true if the subject has a warrant (explicitly or implicitly) for given objectType:objectId#relation and context
-func (svc CheckService) Check(ctx context.Context, authInfo *service.AuthInfo, warrantCheck CheckSpec) (match bool, decisionPath []warrant.WarrantSpec, err error) {
+func (svc CheckService) Check(ctx context.Context, authInfo *service.AuthInfo, warrantCheck CheckSpec) (bool, []warrant.WarrantSpec, error) {
+	match, decisionPath, err := svc.check(ctx, authInfo, warrantCheck)
+	if errors.Is(err, errCheckCycle) {
+		return false, decisionPath, nil
+	}
+
+	return match, decisionPath, err
+}
+
+// check is the same as Check, except that a check re-entering a check already
+// in progress returns errCheckCycle
+func (svc CheckService) check(ctx context.Context, authInfo *service.AuthInfo, warrantCheck CheckSpec) (match bool, decisionPath []warrant.WarrantSpec, err error) {
 	log.Debug().Msgf("Checking for warrant %s", warrantCheck.String())
 
 	// Used to automatically append tenant context for session token w/ tenantId checks
@@ -308,13 +636,23 @@ func (svc CheckService) Check(ctx context.Context, authInfo *service.AuthInfo, w
 		svc.appendTenantContext(&warrantCheck, authInfo.TenantId)
 	}
 
+	// NOTE: warrants can form a cycle (e.g. tenant:a#parent@tenant:b and
+	// tenant:b#parent@tenant:a), so a check that is already in progress
+	// further up the call stack returns errCheckCycle instead of recursing forever
+	ctx, started := startCheck(ctx, warrantCheck.WarrantSpec.String())
+	if !started {
+		return false, decisionPath, errCheckCycle
+	}
+	defer finishCheck(ctx, warrantCheck.WarrantSpec.String())
+
 	// Check for direct warrant match -> doc:readme#viewer@[10]
-	matchedWarrant, err := svc.getWithContextMatch(ctx, warrantCheck.WarrantSpec)
+	matchedWarrant, err := svc.getWithContextMatch(ctx, warrantCheck.WarrantSpec, warrantCheck.AsOf, warrantCheck.ContextualWarrants)
 	if err != nil {
 		return false, decisionPath, err
 	}
 
 	if matchedWarrant != nil {
+		svc.trackImpersonatedMatch(ctx, authInfo, warrantCheck)
 		return true, []warrant.WarrantSpec{{
 			ObjectType: matchedWarrant.ObjectType,
 			ObjectId:   matchedWarrant.ObjectId,
@@ -326,19 +664,22 @@ func (svc CheckService) Check(ctx context.Context, authInfo *service.AuthInfo, w
 	}
 
 	// Check against indirectly related warrants
-	matchingWarrants, err := svc.getMatchingSubjects(ctx, warrantCheck.ObjectType, warrantCheck.ObjectId, warrantCheck.Relation, "%", warrantCheck.Context)
+	matchingWarrants, err := svc.getMatchingSubjects(ctx, warrantCheck.ObjectType, warrantCheck.ObjectId, warrantCheck.Relation, "%", warrantCheck.Context, warrantCheck.AsOf, warrantCheck.ContextualWarrants)
 	if err != nil {
 		return false, decisionPath, err
 	}
 
+	cycle := false
 	for _, matchingWarrant := range matchingWarrants {
 		if matchingWarrant.Subject.Relation == "" {
 			continue
 		}
 
-		match, decisionPath, err := svc.Check(ctx, authInfo, CheckSpec{
-			ConsistentRead: warrantCheck.ConsistentRead,
-			Debug:          warrantCheck.Debug,
+		match, decisionPath, err := svc.check(ctx, authInfo, CheckSpec{
+			ConsistentRead:     warrantCheck.ConsistentRead,
+			Debug:              warrantCheck.Debug,
+			AsOf:               warrantCheck.AsOf,
+			ContextualWarrants: warrantCheck.ContextualWarrants,
 			WarrantSpec: warrant.WarrantSpec{
 				ObjectType: matchingWarrant.Subject.ObjectType,
 				ObjectId:   matchingWarrant.Subject.ObjectId,
@@ -347,42 +688,317 @@ func (svc CheckService) Check(ctx context.Context, authInfo *service.AuthInfo, w
 				Context:    warrantCheck.Context,
 			},
 		})
+		if errors.Is(err, errCheckCycle) {
+			cycle = true
+			continue
+		}
+
 		if err != nil {
 			return false, decisionPath, err
 		}
 
 		if match {
+			svc.trackImpersonatedMatch(ctx, authInfo, warrantCheck)
 			return true, decisionPath, nil
 		}
 	}
 
 	// Attempt to match against defined rules for target relation
-	objectTypeSpec, err := svc.objectTypeSvc.GetByTypeId(ctx, warrantCheck.ObjectType)
+	objectTypeSpec, err := svc.objectTypeSvc.GetByTypeIdAsOf(ctx, warrantCheck.ObjectType, warrantCheck.AsOf)
 	if err != nil {
 		return false, decisionPath, err
 	}
 
 	relationRule := objectTypeSpec.Relations[warrantCheck.Relation]
 	match, decisionPath, err = svc.checkRule(ctx, authInfo, warrantCheck, &relationRule)
+	if errors.Is(err, errCheckCycle) || (err == nil && !match && cycle) {
+		svc.trackAccess(ctx, authInfo, warrantCheck, false)
+		return false, decisionPath, errCheckCycle
+	}
+
 	if err != nil {
 		return false, decisionPath, err
 	}
 
+	svc.trackAccess(ctx, authInfo, warrantCheck, match)
+	return match, decisionPath, nil
+}
+
+// trackImpersonatedMatch tracks a direct or indirect match of a check made on
+// behalf of an impersonated user, so every check made while impersonating is
+// recorded. Matches of other checks are only tracked when matched by a rule.
+func (svc CheckService) trackImpersonatedMatch(ctx context.Context, authInfo *service.AuthInfo, warrantCheck CheckSpec) {
+	if authInfo == nil || !authInfo.Impersonated {
+		return
+	}
+
+	svc.trackAccess(ctx, authInfo, warrantCheck, true)
+}
+
+// trackAccess tracks the result of a check as an access allowed or denied event
+func (svc CheckService) trackAccess(ctx context.Context, authInfo *service.AuthInfo, warrantCheck CheckSpec, match bool) {
+	// NOTE: point-in-time checks and checks with contextual warrants evaluate
+	// access that isn't (or is no longer) stored, so they aren't tracked as access events
+	if warrantCheck.AsOf != nil || len(warrantCheck.ContextualWarrants) > 0 {
+		return
+	}
+
 	if match {
-		svc.eventSvc.TrackAccessAllowedEvent(ctx, warrantCheck.ObjectType, warrantCheck.ObjectId, warrantCheck.Relation, warrantCheck.Subject.ObjectType, warrantCheck.Subject.ObjectId, warrantCheck.Subject.Relation, warrantCheck.Context)
-		return true, decisionPath, nil
+		svc.eventSvc.TrackAccessAllowedEvent(ctx, warrantCheck.ObjectType, warrantCheck.ObjectId, warrantCheck.Relation, warrantCheck.Subject.ObjectType, warrantCheck.Subject.ObjectId, warrantCheck.Subject.Relation, warrantCheck.Context, accessEventMeta(authInfo))
+		return
+	}
+
+	svc.eventSvc.TrackAccessDeniedEvent(ctx, warrantCheck.ObjectType, warrantCheck.ObjectId, warrantCheck.Relation, warrantCheck.Subject.ObjectType, warrantCheck.Subject.ObjectId, warrantCheck.Subject.Relation, warrantCheck.Context, accessEventMeta(authInfo))
+}
+
+// ValidateImpersonation returns an error if the user (or tenant) impersonated
+// by an API key caller doesn't exist. Unlike session tokens, impersonation
+// headers aren't issued by the authentication provider.
+func (svc CheckService) ValidateImpersonation(ctx context.Context, authInfo *service.AuthInfo) error {
+	if authInfo == nil || !authInfo.Impersonated {
+		return nil
 	}
 
-	svc.eventSvc.TrackAccessDeniedEvent(ctx, warrantCheck.ObjectType, warrantCheck.ObjectId, warrantCheck.Relation, warrantCheck.Subject.ObjectType, warrantCheck.Subject.ObjectId, warrantCheck.Subject.Relation, warrantCheck.Context)
-	return false, decisionPath, nil
+	_, err := svc.objectRepo.GetByObjectTypeAndId(ctx, objecttype.ObjectTypeUser, authInfo.UserId)
+	if err != nil {
+		return err
+	}
+
+	if authInfo.TenantId != "" {
+		_, err = svc.objectRepo.GetByObjectTypeAndId(ctx, objecttype.ObjectTypeTenant, authInfo.TenantId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type checksInProgressKey struct{}
+
+// startCheck marks the given check as in progress in the returned context,
+// returning false if it already was
+func startCheck(ctx context.Context, check string) (context.Context, bool) {
+	checksInProgress, ok := ctx.Value(checksInProgressKey{}).(map[string]bool)
+	if !ok {
+		checksInProgress = make(map[string]bool)
+		ctx = context.WithValue(ctx, checksInProgressKey{}, checksInProgress)
+	}
+
+	if checksInProgress[check] {
+		return ctx, false
+	}
+
+	checksInProgress[check] = true
+	return ctx, true
+}
+
+func finishCheck(ctx context.Context, check string) {
+	if checksInProgress, ok := ctx.Value(checksInProgressKey{}).(map[string]bool); ok {
+		delete(checksInProgress, check)
+	}
+}
+
+// accessEventMeta returns the meta of the access events tracked for a check,
+// which records the user and tenant impersonated by an API key caller
+func accessEventMeta(authInfo *service.AuthInfo) interface{} {
+	if authInfo == nil || !authInfo.Impersonated {
+		return nil
+	}
+
+	return ImpersonationMetaSpec{
+		Impersonated: true,
+		UserId:       authInfo.UserId,
+		TenantId:     authInfo.TenantId,
+	}
 }
 
 func (svc CheckService) appendTenantContext(warrantCheck *CheckSpec, tenantId string) {
 	if warrantCheck.WarrantSpec.Context == nil {
-		warrantCheck.WarrantSpec.Context = wntContext.ContextSetSpec{
-			"tenant": tenantId,
-		}
+		warrantCheck.WarrantSpec.Context = TenantContext(tenantId)
 	} else {
-		warrantCheck.WarrantSpec.Context["tenant"] = tenantId
+		warrantCheck.WarrantSpec.Context[ContextTenant] = tenantId
 	}
 }
+
+// Diff returns the object relations the given subject gained or lost between
+// two points in time. Candidate objects are the objects of all warrants valid
+// at either time, so access granted only through a wildcard warrant to an
+// object without warrants of its own is not included.
+func (svc CheckService) Diff(ctx context.Context, diffSpec AccessDiffSpec) (*AccessDiffResultSpec, error) {
+	if diffSpec.Subject.ObjectType == "" || diffSpec.Subject.ObjectId == "" {
+		return nil, service.NewMissingRequiredParameterError("subject")
+	}
+
+	if diffSpec.From.IsZero() {
+		return nil, service.NewMissingRequiredParameterError("from")
+	}
+
+	now := time.Now().UTC()
+	from := diffSpec.From.UTC()
+	to := now
+	if !diffSpec.To.IsZero() {
+		to = diffSpec.To.UTC()
+	}
+
+	if !from.Before(to) {
+		return nil, service.NewInvalidParameterError("from", "must be before to")
+	}
+
+	if to.After(now) {
+		return nil, service.NewInvalidParameterError("to", "must not be in the future")
+	}
+
+	err := svc.validateHistoryHorizon(ctx, "from", from)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := svc.warrantRepo.GetAllObjectsValidAt(ctx, diffSpec.ObjectType, []time.Time{from, to}, MaxAccessDiffObjects+1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(objects) > MaxAccessDiffObjects {
+		return nil, service.NewInvalidRequestError(fmt.Sprintf("More than %d objects had warrants between from and to. Provide an objectType to narrow the diff.", MaxAccessDiffObjects))
+	}
+
+	result := AccessDiffResultSpec{
+		Subject: diffSpec.Subject,
+		From:    from,
+		To:      to,
+		Gained:  make([]AccessChangeSpec, 0),
+		Lost:    make([]AccessChangeSpec, 0),
+	}
+	objectTypeSpecs := make(map[string][2]*objecttype.ObjectTypeSpec)
+	for _, object := range objects {
+		typeSpecs, ok := objectTypeSpecs[object.GetObjectType()]
+		if !ok {
+			typeSpecs[0], err = svc.getObjectTypeAsOf(ctx, object.GetObjectType(), from)
+			if err != nil {
+				return nil, err
+			}
+
+			typeSpecs[1], err = svc.getObjectTypeAsOf(ctx, object.GetObjectType(), to)
+			if err != nil {
+				return nil, err
+			}
+
+			objectTypeSpecs[object.GetObjectType()] = typeSpecs
+		}
+
+		for _, relation := range relationsOf(typeSpecs[0], typeSpecs[1]) {
+			warrantSpec := warrant.WarrantSpec{
+				ObjectType: object.GetObjectType(),
+				ObjectId:   object.GetObjectId(),
+				Relation:   relation,
+				Subject:    &diffSpec.Subject,
+				Context:    diffSpec.Context,
+			}
+			matchedFrom, decisionPathFrom, err := svc.checkAsOf(ctx, warrantSpec, from)
+			if err != nil {
+				return nil, err
+			}
+
+			matchedTo, decisionPathTo, err := svc.checkAsOf(ctx, warrantSpec, to)
+			if err != nil {
+				return nil, err
+			}
+
+			if matchedFrom == matchedTo {
+				continue
+			}
+
+			accessChange := AccessChangeSpec{
+				ObjectType: warrantSpec.ObjectType,
+				ObjectId:   warrantSpec.ObjectId,
+				Relation:   relation,
+				RuleChange: ruleChangeOf(typeSpecs[0], typeSpecs[1], relation),
+			}
+			if matchedTo {
+				accessChange.Warrants = decisionPathTo
+				result.Gained = append(result.Gained, accessChange)
+			} else {
+				accessChange.Warrants = decisionPathFrom
+				result.Lost = append(result.Lost, accessChange)
+			}
+		}
+	}
+
+	return &result, nil
+}
+
+// checkAsOf checks the given warrant as of the given time, treating object
+// types that didn't exist at that time as granting no access
+func (svc CheckService) checkAsOf(ctx context.Context, warrantSpec warrant.WarrantSpec, asOf time.Time) (bool, []warrant.WarrantSpec, error) {
+	match, decisionPath, err := svc.Check(ctx, nil, CheckSpec{
+		WarrantSpec: warrantSpec,
+		AsOf:        &asOf,
+	})
+	if err != nil {
+		switch err.(type) {
+		case *service.RecordNotFoundError:
+			return false, nil, nil
+		default:
+			return false, nil, err
+		}
+	}
+
+	return match, decisionPath, nil
+}
+
+func (svc CheckService) getObjectTypeAsOf(ctx context.Context, typeId string, asOf time.Time) (*objecttype.ObjectTypeSpec, error) {
+	objectTypeSpec, err := svc.objectTypeSvc.GetByTypeIdAsOf(ctx, typeId, &asOf)
+	if err != nil {
+		switch err.(type) {
+		case *service.RecordNotFoundError:
+			return nil, nil
+		default:
+			return nil, err
+		}
+	}
+
+	return objectTypeSpec, nil
+}
+
+// relationsOf returns the names of the relations defined in either of the given object types
+func relationsOf(objectTypeSpecs ...*objecttype.ObjectTypeSpec) []string {
+	relations := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, objectTypeSpec := range objectTypeSpecs {
+		if objectTypeSpec == nil {
+			continue
+		}
+
+		for relation := range objectTypeSpec.Relations {
+			if !seen[relation] {
+				seen[relation] = true
+				relations = append(relations, relation)
+			}
+		}
+	}
+
+	sort.Strings(relations)
+	return relations
+}
+
+func ruleChangeOf(fromSpec *objecttype.ObjectTypeSpec, toSpec *objecttype.ObjectTypeSpec, relation string) *RuleChangeSpec {
+	var ruleChange RuleChangeSpec
+	if fromSpec != nil {
+		if rule, ok := fromSpec.Relations[relation]; ok {
+			ruleChange.From = &rule
+		}
+	}
+
+	if toSpec != nil {
+		if rule, ok := toSpec.Relations[relation]; ok {
+			ruleChange.To = &rule
+		}
+	}
+
+	if reflect.DeepEqual(ruleChange.From, ruleChange.To) {
+		return nil
+	}
+
+	return &ruleChange
+}
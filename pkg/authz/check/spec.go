@@ -1,6 +1,9 @@
 package authz
 
 import (
+	"time"
+
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
 	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
 	context "github.com/warrant-dev/warrant/pkg/context"
 )
@@ -8,18 +11,45 @@ import (
 const Authorized = "Authorized"
 const NotAuthorized = "Not Authorized"
 
+// ContextTenant is the context name that scopes warrants and session checks to a tenant
+const ContextTenant = "tenant"
+
+// TenantContext returns the context of warrants scoped to the given tenant,
+// or nil if tenantId is empty
+func TenantContext(tenantId string) context.ContextSetSpec {
+	if tenantId == "" {
+		return nil
+	}
+
+	return context.ContextSetSpec{
+		ContextTenant: tenantId,
+	}
+}
+
 type CheckSpec struct {
 	warrant.WarrantSpec
-	ConsistentRead bool `json:"consistentRead"`
-	Debug          bool `json:"debug"`
+	ConsistentRead     bool                  `json:"consistentRead"`
+	Debug              bool                  `json:"debug"`
+	AsOf               *time.Time            `json:"asOf,omitempty"`
+	ContextualWarrants []warrant.WarrantSpec `json:"contextualWarrants,omitempty"`
 }
 
 func (spec CheckSpec) ToMap() map[string]interface{} {
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"warrant":        spec.WarrantSpec.ToMap(),
 		"consistentRead": spec.ConsistentRead,
 		"debug":          spec.Debug,
 	}
+
+	if spec.AsOf != nil {
+		result["asOf"] = spec.AsOf
+	}
+
+	if len(spec.ContextualWarrants) > 0 {
+		result["contextualWarrants"] = contextualWarrantMaps(spec.ContextualWarrants)
+	}
+
+	return result
 }
 
 type CheckManySpec struct {
@@ -28,6 +58,10 @@ type CheckManySpec struct {
 	Context        context.ContextSetSpec `json:"context"`
 	ConsistentRead bool                   `json:"consistentRead"`
 	Debug          bool                   `json:"debug"`
+	AsOf           *time.Time             `json:"asOf,omitempty"` // NOTE: evaluate the checks against warrants and object types as they were at this time
+
+	// NOTE: warrants that only exist for the duration of the checks
+	ContextualWarrants []warrant.WarrantSpec `json:"contextualWarrants,omitempty" validate:"dive"`
 }
 
 func (spec CheckManySpec) ToMap() map[string]interface{} {
@@ -37,21 +71,47 @@ func (spec CheckManySpec) ToMap() map[string]interface{} {
 		"debug":          spec.Debug,
 	}
 
+	if spec.AsOf != nil {
+		result["asOf"] = spec.AsOf
+	}
+
 	warrantMaps := make([]map[string]interface{}, 0)
 	for _, warrantSpec := range spec.Warrants {
 		warrantMaps = append(warrantMaps, warrantSpec.ToMap())
 	}
 
 	result["warrants"] = warrantMaps
+	if len(spec.ContextualWarrants) > 0 {
+		result["contextualWarrants"] = contextualWarrantMaps(spec.ContextualWarrants)
+	}
+
 	return result
 }
 
+func contextualWarrantMaps(contextualWarrants []warrant.WarrantSpec) []map[string]interface{} {
+	warrantMaps := make([]map[string]interface{}, 0, len(contextualWarrants))
+	for _, warrantSpec := range contextualWarrants {
+		warrantMaps = append(warrantMaps, warrantSpec.ToMap())
+	}
+
+	return warrantMaps
+}
+
 type SessionCheckManySpec struct {
 	Op             string                       `json:"op"`
 	Warrants       []warrant.SessionWarrantSpec `json:"warrants" validate:"min=1,dive"`
 	Context        context.ContextSetSpec       `json:"context"`
 	ConsistentRead bool                         `json:"consistentRead"`
 	Debug          bool                         `json:"debug"`
+	AsOf           *time.Time                   `json:"asOf,omitempty"`
+}
+
+// ImpersonationMetaSpec is the meta of access events tracked for checks an
+// API key caller makes on behalf of a user
+type ImpersonationMetaSpec struct {
+	Impersonated bool   `json:"impersonated"`
+	UserId       string `json:"userId"`
+	TenantId     string `json:"tenantId,omitempty"`
 }
 
 type CheckResultSpec struct {
@@ -60,3 +120,32 @@ type CheckResultSpec struct {
 	ProcessingTime int64                            `json:"processingTime,omitempty"`
 	DecisionPath   map[string][]warrant.WarrantSpec `json:"decisionPath,omitempty"`
 }
+
+type AccessDiffSpec struct {
+	Subject    warrant.SubjectSpec    `json:"subject"`
+	From       time.Time              `json:"from"`
+	To         time.Time              `json:"to"` // NOTE: defaults to now
+	ObjectType string                 `json:"objectType,omitempty" validate:"valid_object_type"`
+	Context    context.ContextSetSpec `json:"context,omitempty"`
+}
+
+type AccessChangeSpec struct {
+	ObjectType string                `json:"objectType"`
+	ObjectId   string                `json:"objectId"`
+	Relation   string                `json:"relation"`
+	Warrants   []warrant.WarrantSpec `json:"warrants,omitempty"`   // NOTE: the warrants that granted access at from if lost, or at to if gained
+	RuleChange *RuleChangeSpec       `json:"ruleChange,omitempty"` // NOTE: only set if the relation's rule changed between from and to
+}
+
+type RuleChangeSpec struct {
+	From *objecttype.RelationRule `json:"from"`
+	To   *objecttype.RelationRule `json:"to"`
+}
+
+type AccessDiffResultSpec struct {
+	Subject warrant.SubjectSpec `json:"subject"`
+	From    time.Time           `json:"from"`
+	To      time.Time           `json:"to"`
+	Gained  []AccessChangeSpec  `json:"gained"`
+	Lost    []AccessChangeSpec  `json:"lost"`
+}
@@ -5,6 +5,7 @@ import (
 	"net/url"
 
 	"github.com/gorilla/mux"
+	pricingtier "github.com/warrant-dev/warrant/pkg/authz/pricingtier"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
 )
@@ -52,6 +53,65 @@ func (svc FeatureService) Routes() []service.Route {
 			Method:  "DELETE",
 			Handler: service.NewRouteHandler(svc, DeleteHandler),
 		},
+
+		// pricing tier features
+		{
+			Pattern: "/v1/pricing-tiers/{pricingTierId}/features",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListForPricingTierHandler),
+		},
+		{
+			Pattern: "/v1/pricing-tiers/{pricingTierId}/features",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AddToPricingTierHandler),
+		},
+		{
+			Pattern: "/v1/pricing-tiers/{pricingTierId}/features/{featureId}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveFromPricingTierHandler),
+		},
+
+		// entitlements
+		{
+			Pattern: "/v1/tenants/{tenantId}/features",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListEffectiveForSubjectHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/features",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AssignToSubjectHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/features/{featureId}",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, CheckForSubjectHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/features/{featureId}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveFromSubjectHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/features",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListEffectiveForSubjectHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/features",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AssignToSubjectHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/features/{featureId}",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, CheckForSubjectHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/features/{featureId}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveFromSubjectHandler),
+		},
 	}
 }
 
@@ -94,6 +154,7 @@ func ListHandler(svc FeatureService, w http.ResponseWriter, r *http.Request) err
 		return err
 	}
 
+	middleware.SetCursorHeaders(w, listParams, features)
 	service.SendJSONResponse(w, features)
 	return nil
 }
@@ -133,3 +194,138 @@ func DeleteHandler(svc FeatureService, w http.ResponseWriter, r *http.Request) e
 
 	return nil
 }
+
+func ListForPricingTierHandler(svc FeatureService, w http.ResponseWriter, r *http.Request) error {
+	pricingTierId, err := url.QueryUnescape(mux.Vars(r)["pricingTierId"])
+	if err != nil {
+		return service.NewInvalidParameterError("pricingTierId", "")
+	}
+
+	features, err := svc.ListForPricingTier(r.Context(), pricingTierId)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, features)
+	return nil
+}
+
+func AddToPricingTierHandler(svc FeatureService, w http.ResponseWriter, r *http.Request) error {
+	var assignSpec AssignFeatureSpec
+	err := service.ParseJSONBody(r.Body, &assignSpec)
+	if err != nil {
+		return err
+	}
+
+	pricingTierId, err := url.QueryUnescape(mux.Vars(r)["pricingTierId"])
+	if err != nil {
+		return service.NewInvalidParameterError("pricingTierId", "")
+	}
+
+	assignedFeature, err := svc.AddToPricingTier(r.Context(), pricingTierId, assignSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedFeature)
+	return nil
+}
+
+func RemoveFromPricingTierHandler(svc FeatureService, w http.ResponseWriter, r *http.Request) error {
+	pricingTierId, err := url.QueryUnescape(mux.Vars(r)["pricingTierId"])
+	if err != nil {
+		return service.NewInvalidParameterError("pricingTierId", "")
+	}
+
+	featureId, err := url.QueryUnescape(mux.Vars(r)["featureId"])
+	if err != nil {
+		return service.NewInvalidParameterError("featureId", "")
+	}
+
+	err = svc.RemoveFromPricingTier(r.Context(), pricingTierId, featureId)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func ListEffectiveForSubjectHandler(svc FeatureService, w http.ResponseWriter, r *http.Request) error {
+	subject, err := pricingtier.SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	effectiveFeatures, err := svc.ListEffectiveForSubject(r.Context(), *subject)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, effectiveFeatures)
+	return nil
+}
+
+func AssignToSubjectHandler(svc FeatureService, w http.ResponseWriter, r *http.Request) error {
+	var assignSpec AssignFeatureSpec
+	err := service.ParseJSONBody(r.Body, &assignSpec)
+	if err != nil {
+		return err
+	}
+
+	subject, err := pricingtier.SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	assignedFeature, err := svc.AssignToSubject(r.Context(), *subject, assignSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedFeature)
+	return nil
+}
+
+func CheckForSubjectHandler(svc FeatureService, w http.ResponseWriter, r *http.Request) error {
+	subject, err := pricingtier.SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	featureId, err := url.QueryUnescape(mux.Vars(r)["featureId"])
+	if err != nil {
+		return service.NewInvalidParameterError("featureId", "")
+	}
+
+	authInfo := service.GetAuthInfoFromRequestContext(r.Context())
+	checkResult, err := svc.CheckForSubject(r.Context(), authInfo, *subject, featureId)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, checkResult)
+	return nil
+}
+
+func RemoveFromSubjectHandler(svc FeatureService, w http.ResponseWriter, r *http.Request) error {
+	subject, err := pricingtier.SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	featureId, err := url.QueryUnescape(mux.Vars(r)["featureId"])
+	if err != nil {
+		return service.NewInvalidParameterError("featureId", "")
+	}
+
+	err = svc.RemoveFromSubject(r.Context(), *subject, featureId)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
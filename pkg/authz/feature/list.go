@@ -3,6 +3,8 @@ package authz
 import (
 	"fmt"
 	"time"
+
+	"github.com/warrant-dev/warrant/pkg/middleware"
 )
 
 type FeatureListParamParser struct{}
@@ -41,3 +43,18 @@ func (parser FeatureListParamParser) ParseValue(val string, sortBy string) (inte
 		return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
 	}
 }
+
+func (spec FeatureSpec) ToCursor(sortBy string) middleware.Cursor {
+	switch sortBy {
+	case "createdAt":
+		return middleware.NewCursor(spec.FeatureId, spec.CreatedAt)
+	case "name":
+		if !spec.Name.Valid {
+			return middleware.NewCursor(spec.FeatureId, middleware.NullValue{})
+		}
+
+		return middleware.NewCursor(spec.FeatureId, spec.Name.String)
+	default:
+		return middleware.NewCursor(spec.FeatureId, nil)
+	}
+}
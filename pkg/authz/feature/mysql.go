@@ -131,21 +131,9 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 
 	if listParams.AfterId != "" {
 		if listParams.AfterValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s > ? OR (featureId > ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s < ? OR (featureId < ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(listParams.SortBy, "featureId", listParams.SortOrder, listParams.AfterValue, listParams.AfterId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND featureId > ?", query)
@@ -159,37 +147,26 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 
 	if listParams.BeforeId != "" {
 		if listParams.BeforeValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s < ? OR (featureId < ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s > ? OR (featureId > ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(listParams.SortBy, "featureId", listParams.SortOrder.Reverse(), listParams.BeforeValue, listParams.BeforeId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND featureId < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND featureId > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	if listParams.SortBy != "featureId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s, featureId %s LIMIT ?", query, listParams.SortBy, listParams.SortOrder, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s, featureId %s LIMIT ?", query, listParams.SortBy, sortOrder, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY featureId %s LIMIT ?", query, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY featureId %s LIMIT ?", query, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -212,6 +189,8 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 		models = append(models, &features[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -2,9 +2,13 @@ package authz
 
 import (
 	"context"
+	"sort"
 
+	check "github.com/warrant-dev/warrant/pkg/authz/check"
 	object "github.com/warrant-dev/warrant/pkg/authz/object"
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	pricingtier "github.com/warrant-dev/warrant/pkg/authz/pricingtier"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
 	"github.com/warrant-dev/warrant/pkg/event"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
@@ -14,17 +18,23 @@ const ResourceTypeFeature = "feature"
 
 type FeatureService struct {
 	service.BaseService
-	repo      FeatureRepository
-	eventSvc  event.EventService
-	objectSvc object.ObjectService
+	repo           FeatureRepository
+	eventSvc       event.EventService
+	objectSvc      object.ObjectService
+	pricingTierSvc pricingtier.PricingTierService
+	warrantSvc     warrant.WarrantService
+	checkSvc       check.CheckService
 }
 
-func NewService(env service.Env, repo FeatureRepository, eventSvc event.EventService, objectSvc object.ObjectService) FeatureService {
+func NewService(env service.Env, repo FeatureRepository, eventSvc event.EventService, objectSvc object.ObjectService, pricingTierSvc pricingtier.PricingTierService, warrantSvc warrant.WarrantService, checkSvc check.CheckService) FeatureService {
 	return FeatureService{
-		BaseService: service.NewBaseService(env),
-		repo:        repo,
-		eventSvc:    eventSvc,
-		objectSvc:   objectSvc,
+		BaseService:    service.NewBaseService(env),
+		repo:           repo,
+		eventSvc:       eventSvc,
+		objectSvc:      objectSvc,
+		pricingTierSvc: pricingTierSvc,
+		warrantSvc:     warrantSvc,
+		checkSvc:       checkSvc,
 	}
 }
 
@@ -126,3 +136,335 @@ func (svc FeatureService) DeleteByFeatureId(ctx context.Context, featureId strin
 
 	return err
 }
+
+// ListForPricingTier returns the features in a pricing tier, sorted by featureId
+func (svc FeatureService) ListForPricingTier(ctx context.Context, pricingTierId string) ([]AssignedFeatureSpec, error) {
+	_, err := svc.pricingTierSvc.GetByPricingTierId(ctx, pricingTierId)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.listAssigned(ctx, pricingTierSubject(pricingTierId))
+}
+
+// AddToPricingTier adds a feature to a pricing tier. Members of the pricing
+// tier inherit the feature through the feature object type's rules.
+func (svc FeatureService) AddToPricingTier(ctx context.Context, pricingTierId string, assignSpec AssignFeatureSpec) (*AssignedFeatureSpec, error) {
+	_, err := svc.pricingTierSvc.GetByPricingTierId(ctx, pricingTierId)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.assign(ctx, pricingTierSubject(pricingTierId), assignSpec)
+}
+
+// RemoveFromPricingTier removes a feature from a pricing tier
+func (svc FeatureService) RemoveFromPricingTier(ctx context.Context, pricingTierId string, featureId string) error {
+	return svc.warrantSvc.Delete(ctx, featureWarrantSpec(featureId, pricingTierSubject(pricingTierId)))
+}
+
+// AssignToSubject assigns a feature directly to a tenant or user
+func (svc FeatureService) AssignToSubject(ctx context.Context, subject warrant.SubjectSpec, assignSpec AssignFeatureSpec) (*AssignedFeatureSpec, error) {
+	err := svc.pricingTierSvc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.assign(ctx, subject, assignSpec)
+}
+
+// RemoveFromSubject removes a feature's direct assignment to a tenant or user.
+// The tenant or user keeps the feature if one of its pricing tiers has it.
+func (svc FeatureService) RemoveFromSubject(ctx context.Context, subject warrant.SubjectSpec, featureId string) error {
+	return svc.warrantSvc.Delete(ctx, featureWarrantSpec(featureId, subject))
+}
+
+// ListEffectiveForSubject returns the features a tenant or user has, sorted
+// by featureId. Features are resolved by the CheckService through the rules
+// of the feature and pricing tier object types, so features inherited through
+// pricing tiers are included.
+func (svc FeatureService) ListEffectiveForSubject(ctx context.Context, subject warrant.SubjectSpec) ([]EffectiveFeatureSpec, error) {
+	err := svc.pricingTierSvc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: listing features doesn't grant or deny any access, so the checks aren't tracked as access events
+	checkCtx := event.WithoutTracking(ctx)
+	grants, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+		ObjectType: objecttype.ObjectTypeFeature,
+		Relation:   objecttype.RelationMember,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	grantsByFeatureId := make(map[string][]*warrant.WarrantSpec)
+	for _, grant := range grants {
+		if len(grant.Context) == 0 {
+			grantsByFeatureId[grant.ObjectId] = append(grantsByFeatureId[grant.ObjectId], grant)
+		}
+	}
+
+	// NOTE: the subject's membership in each source is checked once
+	isMember := make(map[string]bool)
+	checkMember := func(objectType string, objectId string, relation string) (bool, error) {
+		warrantSpec := warrant.WarrantSpec{
+			ObjectType: objectType,
+			ObjectId:   objectId,
+			Relation:   relation,
+			Subject:    &subject,
+		}
+		if match, checked := isMember[warrantSpec.String()]; checked {
+			return match, nil
+		}
+
+		match, _, err := svc.checkSvc.Check(checkCtx, nil, check.CheckSpec{WarrantSpec: warrantSpec})
+		if err != nil {
+			return false, err
+		}
+
+		isMember[warrantSpec.String()] = match
+		return match, nil
+	}
+
+	effectiveFeatureSpecs := make([]EffectiveFeatureSpec, 0)
+	listParams := middleware.ListParams{
+		Page:      1,
+		Limit:     warrant.ListAllBatchSize,
+		SortBy:    FeatureListParamParser{}.GetDefaultSortBy(),
+		SortOrder: middleware.SortOrderAsc,
+	}
+	for {
+		featureSpecs, err := svc.List(ctx, listParams)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, featureSpec := range featureSpecs {
+			match, err := checkMember(objecttype.ObjectTypeFeature, featureSpec.FeatureId, objecttype.RelationMember)
+			if err != nil {
+				return nil, err
+			}
+
+			if !match {
+				continue
+			}
+
+			sources := make([]warrant.SubjectSpec, 0)
+			for _, grant := range append(grantsByFeatureId[featureSpec.FeatureId], grantsByFeatureId[objecttype.ObjectIdWildcard]...) {
+				source := *grant.Subject
+				var isSource bool
+				switch {
+				case source.Relation != "":
+					isSource, err = checkMember(source.ObjectType, source.ObjectId, source.Relation)
+				case source.ObjectType == subject.ObjectType:
+					isSource = source.ObjectId == subject.ObjectId
+				case source.ObjectType == objecttype.ObjectTypePricingTier || source.ObjectType == objecttype.ObjectTypeFeature:
+					isSource, err = checkMember(source.ObjectType, source.ObjectId, objecttype.RelationMember)
+				}
+				if err != nil {
+					return nil, err
+				}
+
+				if isSource {
+					sources = append(sources, source)
+				}
+			}
+
+			effectiveFeatureSpecs = append(effectiveFeatureSpecs, EffectiveFeatureSpec{
+				FeatureId:   featureSpec.FeatureId,
+				Name:        featureSpec.Name,
+				Description: featureSpec.Description,
+				Sources:     sources,
+			})
+		}
+
+		if len(featureSpecs) < listParams.Limit {
+			break
+		}
+
+		cursor := featureSpecs[len(featureSpecs)-1].ToCursor(listParams.SortBy)
+		listParams.AfterId = cursor.ID
+		listParams.AfterValue = cursor.Value
+	}
+
+	sort.Slice(effectiveFeatureSpecs, func(i, j int) bool {
+		return effectiveFeatureSpecs[i].FeatureId < effectiveFeatureSpecs[j].FeatureId
+	})
+	return effectiveFeatureSpecs, nil
+}
+
+// CheckForSubject checks if a tenant or user has a feature and, if it does,
+// returns the path of warrants that grants it
+func (svc FeatureService) CheckForSubject(ctx context.Context, authInfo *service.AuthInfo, subject warrant.SubjectSpec, featureId string) (*FeatureCheckResultSpec, error) {
+	err := svc.pricingTierSvc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.GetByFeatureId(ctx, featureId)
+	if err != nil {
+		return nil, err
+	}
+
+	match, _, err := svc.checkSvc.Check(ctx, authInfo, check.CheckSpec{
+		WarrantSpec: warrant.WarrantSpec{
+			ObjectType: objecttype.ObjectTypeFeature,
+			ObjectId:   featureId,
+			Relation:   objecttype.RelationMember,
+			Subject:    &subject,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	checkResultSpec := FeatureCheckResultSpec{
+		FeatureId: featureId,
+		Subject:   subject,
+		Result:    check.NotAuthorized,
+		Path:      make([]warrant.WarrantSpec, 0),
+	}
+	if !match {
+		return &checkResultSpec, nil
+	}
+
+	checkResultSpec.Result = check.Authorized
+	path, err := svc.grantingPath(ctx, subject, featureId)
+	if err != nil {
+		return nil, err
+	}
+
+	if path != nil {
+		checkResultSpec.Path = path
+	}
+
+	return &checkResultSpec, nil
+}
+
+// grantingPath returns the shortest chain of feature and pricing tier member
+// warrants from the subject to the feature, or nil if the feature is granted
+// some other way (e.g. through a userset).
+func (svc FeatureService) grantingPath(ctx context.Context, subject warrant.SubjectSpec, featureId string) ([]warrant.WarrantSpec, error) {
+	warrantsBySubject := make(map[string][]*warrant.WarrantSpec)
+	for _, objectType := range []string{objecttype.ObjectTypeFeature, objecttype.ObjectTypePricingTier} {
+		warrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+			ObjectType: objectType,
+			Relation:   objecttype.RelationMember,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, warrantSpec := range warrantSpecs {
+			if len(warrantSpec.Context) > 0 || (warrantSpec.Subject.Relation != "" && warrantSpec.Subject.Relation != objecttype.RelationMember) {
+				continue
+			}
+
+			subjectKey := subjectKey(warrantSpec.Subject.ObjectType, warrantSpec.Subject.ObjectId)
+			warrantsBySubject[subjectKey] = append(warrantsBySubject[subjectKey], warrantSpec)
+		}
+	}
+
+	// NOTE: breadth-first search from the subject, following warrants that make each visited object a member of another
+	start := subjectKey(subject.ObjectType, subject.ObjectId)
+	visited := map[string]bool{start: true}
+	paths := map[string][]warrant.WarrantSpec{start: {}}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, warrantSpec := range warrantsBySubject[current] {
+			path := append(append(make([]warrant.WarrantSpec, 0, len(paths[current])+1), paths[current]...), *warrantSpec)
+			if warrantSpec.ObjectType == objecttype.ObjectTypeFeature && (warrantSpec.ObjectId == featureId || warrantSpec.ObjectId == objecttype.ObjectIdWildcard) {
+				return path, nil
+			}
+
+			next := subjectKey(warrantSpec.ObjectType, warrantSpec.ObjectId)
+			if visited[next] {
+				continue
+			}
+
+			visited[next] = true
+			paths[next] = path
+			queue = append(queue, next)
+		}
+	}
+
+	return nil, nil
+}
+
+func (svc FeatureService) listAssigned(ctx context.Context, subject warrant.SubjectSpec) ([]AssignedFeatureSpec, error) {
+	warrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+		ObjectType: objecttype.ObjectTypeFeature,
+		Relation:   objecttype.RelationMember,
+		Subject:    &subject,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	assignedFeatureSpecs := make([]AssignedFeatureSpec, 0)
+	for _, warrantSpec := range warrantSpecs {
+		if warrantSpec.Subject.Relation != "" || len(warrantSpec.Context) > 0 {
+			continue
+		}
+
+		// NOTE: warrants don't require their object to exist, so assignments of features that were never created are skipped
+		featureSpec, err := svc.GetByFeatureId(ctx, warrantSpec.ObjectId)
+		if err != nil {
+			if _, ok := err.(*service.RecordNotFoundError); ok {
+				continue
+			}
+
+			return nil, err
+		}
+
+		assignedFeatureSpecs = append(assignedFeatureSpecs, *toAssignedFeatureSpec(featureSpec))
+	}
+
+	sort.Slice(assignedFeatureSpecs, func(i, j int) bool {
+		return assignedFeatureSpecs[i].FeatureId < assignedFeatureSpecs[j].FeatureId
+	})
+	return assignedFeatureSpecs, nil
+}
+
+func (svc FeatureService) assign(ctx context.Context, subject warrant.SubjectSpec, assignSpec AssignFeatureSpec) (*AssignedFeatureSpec, error) {
+	featureSpec, err := svc.GetByFeatureId(ctx, assignSpec.FeatureId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.warrantSvc.Create(ctx, featureWarrantSpec(assignSpec.FeatureId, subject))
+	if err != nil {
+		return nil, err
+	}
+
+	return toAssignedFeatureSpec(featureSpec), nil
+}
+
+func featureWarrantSpec(featureId string, subject warrant.SubjectSpec) warrant.WarrantSpec {
+	return warrant.WarrantSpec{
+		ObjectType: objecttype.ObjectTypeFeature,
+		ObjectId:   featureId,
+		Relation:   objecttype.RelationMember,
+		Subject:    &subject,
+	}
+}
+
+func pricingTierSubject(pricingTierId string) warrant.SubjectSpec {
+	return warrant.SubjectSpec{ObjectType: objecttype.ObjectTypePricingTier, ObjectId: pricingTierId}
+}
+
+func subjectKey(objectType string, objectId string) string {
+	return objectType + ":" + objectId
+}
+
+func toAssignedFeatureSpec(featureSpec *FeatureSpec) *AssignedFeatureSpec {
+	return &AssignedFeatureSpec{
+		FeatureId:   featureSpec.FeatureId,
+		Name:        featureSpec.Name,
+		Description: featureSpec.Description,
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	object "github.com/warrant-dev/warrant/pkg/authz/object"
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
 	context "github.com/warrant-dev/warrant/pkg/context"
 	"github.com/warrant-dev/warrant/pkg/database"
 )
@@ -37,3 +38,37 @@ type UpdateFeatureSpec struct {
 	Name        database.NullString `json:"name"`
 	Description database.NullString `json:"description"`
 }
+
+// AssignFeatureSpec type for adding a feature to a pricing tier or assigning
+// it directly to a tenant or user
+type AssignFeatureSpec struct {
+	FeatureId string `json:"featureId" validate:"required"`
+}
+
+// AssignedFeatureSpec type for a feature in a pricing tier or assigned
+// directly to a tenant or user
+type AssignedFeatureSpec struct {
+	FeatureId   string              `json:"featureId"`
+	Name        database.NullString `json:"name"`
+	Description database.NullString `json:"description"`
+}
+
+// EffectiveFeatureSpec type for a feature a tenant or user has, either
+// directly or through its pricing tiers. Sources are the subjects the feature
+// is assigned to that the tenant or user is, or is a member of.
+type EffectiveFeatureSpec struct {
+	FeatureId   string                `json:"featureId"`
+	Name        database.NullString   `json:"name"`
+	Description database.NullString   `json:"description"`
+	Sources     []warrant.SubjectSpec `json:"sources"`
+}
+
+// FeatureCheckResultSpec type for the result of checking if a tenant or user
+// has a feature. Path is the chain of warrants, starting from the tenant or
+// user, that grants the feature.
+type FeatureCheckResultSpec struct {
+	FeatureId string                `json:"featureId"`
+	Subject   warrant.SubjectSpec   `json:"subject"`
+	Result    string                `json:"result"`
+	Path      []warrant.WarrantSpec `json:"path"`
+}
@@ -0,0 +1,106 @@
+package authz
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+// MaxManifestBytes is the largest manifest accepted by the plan and apply endpoints
+const MaxManifestBytes = 10 << 20
+
+func (svc ManifestService) Routes() []service.Route {
+	return []service.Route{
+		// plan
+		{
+			Pattern: "/v1/manifest/plan",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, PlanHandler),
+		},
+
+		// apply
+		{
+			Pattern: "/v1/manifest/apply",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, ApplyHandler),
+		},
+
+		// default
+		{
+			Pattern: "/v1/manifest/default",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, GetDefaultHandler),
+		},
+	}
+}
+
+func PlanHandler(svc ManifestService, w http.ResponseWriter, r *http.Request) error {
+	manifest, prune, err := parseManifestRequest(r)
+	if err != nil {
+		return err
+	}
+
+	plan, err := svc.Plan(r.Context(), *manifest, prune)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, plan)
+	return nil
+}
+
+func ApplyHandler(svc ManifestService, w http.ResponseWriter, r *http.Request) error {
+	manifest, prune, err := parseManifestRequest(r)
+	if err != nil {
+		return err
+	}
+
+	plan, err := svc.Apply(r.Context(), *manifest, prune)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, plan)
+	return nil
+}
+
+func GetDefaultHandler(svc ManifestService, w http.ResponseWriter, r *http.Request) error {
+	manifest, err := DefaultManifest()
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, manifest)
+	return nil
+}
+
+// parseManifestRequest parses the YAML or JSON manifest in the request body
+// and the prune query param
+func parseManifestRequest(r *http.Request) (*ManifestSpec, bool, error) {
+	var err error
+	prune := false
+	if r.URL.Query().Has("prune") {
+		prune, err = strconv.ParseBool(r.URL.Query().Get("prune"))
+		if err != nil {
+			return nil, false, service.NewInvalidParameterError("prune", "must be true or false")
+		}
+	}
+
+	contents, err := io.ReadAll(io.LimitReader(r.Body, MaxManifestBytes+1))
+	if err != nil {
+		return nil, false, service.NewInvalidRequestError("Invalid request body")
+	}
+
+	if len(contents) > MaxManifestBytes {
+		return nil, false, service.NewInvalidRequestError("Manifest must be at most 10MB")
+	}
+
+	manifest, err := ParseManifest(contents)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return manifest, prune, nil
+}
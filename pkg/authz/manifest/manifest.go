@@ -0,0 +1,113 @@
+package authz
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+// DefaultManifestYAML declares the built-in object types as created by the
+// datastore migrations. It must be updated along with any migration that
+// changes a built-in object type.
+//
+//go:embed default.yaml
+var DefaultManifestYAML []byte
+
+func DefaultManifest() (*ManifestSpec, error) {
+	return ParseManifest(DefaultManifestYAML)
+}
+
+// ParseManifest parses and validates a manifest given in YAML or JSON
+func ParseManifest(contents []byte) (*ManifestSpec, error) {
+	jsonContents, err := service.YAMLToJSON(contents)
+	if err != nil {
+		return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid manifest: %s", err.Error()))
+	}
+
+	var manifest ManifestSpec
+	err = json.Unmarshal(jsonContents, &manifest)
+	if err != nil {
+		switch err := err.(type) {
+		case *json.UnmarshalTypeError:
+			return nil, service.NewInvalidParameterError(err.Field, "has an invalid type")
+		default:
+			return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid manifest: %s", err.Error()))
+		}
+	}
+
+	err = validateManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+// validateManifest validates each resource in the manifest and checks that
+// no resource is declared more than once
+func validateManifest(manifest ManifestSpec) error {
+	declared := make(map[string]map[string]bool)
+	for _, resourceType := range resourceTypes {
+		declared[resourceType] = make(map[string]bool)
+	}
+
+	// NOTE: resources are numbered from 1 in invalid resource errors since their ids may be missing
+	validate := func(resourceType string, resourceNumber int, resourceId string, spec interface{}) error {
+		err := service.ValidateStruct(spec)
+		if err != nil {
+			return service.NewInvalidRequestError(fmt.Sprintf("Invalid %s %d: %s", resourceType, resourceNumber, err.Error()))
+		}
+
+		if declared[resourceType][resourceId] {
+			return service.NewInvalidRequestError(fmt.Sprintf("The %s %s is declared more than once", resourceType, resourceId))
+		}
+
+		declared[resourceType][resourceId] = true
+		return nil
+	}
+
+	for i := range manifest.ObjectTypes {
+		if err := validate(ResourceTypeObjectType, i+1, manifest.ObjectTypes[i].Type, &manifest.ObjectTypes[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.Roles {
+		if err := validate(ResourceTypeRole, i+1, manifest.Roles[i].RoleId, &manifest.Roles[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.Permissions {
+		if err := validate(ResourceTypePermission, i+1, manifest.Permissions[i].PermissionId, &manifest.Permissions[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.Features {
+		if err := validate(ResourceTypeFeature, i+1, manifest.Features[i].FeatureId, &manifest.Features[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.PricingTiers {
+		if err := validate(ResourceTypePricingTier, i+1, manifest.PricingTiers[i].PricingTierId, &manifest.PricingTiers[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range manifest.Warrants {
+		warrantSpec := &manifest.Warrants[i].WarrantSpec
+		if warrantSpec.Subject == nil {
+			return service.NewInvalidRequestError(fmt.Sprintf("Invalid %s %d: subject is required", ResourceTypeWarrant, i+1))
+		}
+
+		if err := validate(ResourceTypeWarrant, i+1, warrantSpec.String(), warrantSpec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
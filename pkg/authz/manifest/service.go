@@ -0,0 +1,504 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	feature "github.com/warrant-dev/warrant/pkg/authz/feature"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	permission "github.com/warrant-dev/warrant/pkg/authz/permission"
+	pricingtier "github.com/warrant-dev/warrant/pkg/authz/pricingtier"
+	role "github.com/warrant-dev/warrant/pkg/authz/role"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/middleware"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+const ListBatchSize = 500
+
+type ManifestService struct {
+	service.BaseService
+	objectTypeSvc  objecttype.ObjectTypeService
+	roleSvc        role.RoleService
+	permissionSvc  permission.PermissionService
+	featureSvc     feature.FeatureService
+	pricingTierSvc pricingtier.PricingTierService
+	warrantSvc     warrant.WarrantService
+}
+
+func NewService(env service.Env, objectTypeSvc objecttype.ObjectTypeService, roleSvc role.RoleService, permissionSvc permission.PermissionService, featureSvc feature.FeatureService, pricingTierSvc pricingtier.PricingTierService, warrantSvc warrant.WarrantService) ManifestService {
+	return ManifestService{
+		BaseService:    service.NewBaseService(env),
+		objectTypeSvc:  objectTypeSvc,
+		roleSvc:        roleSvc,
+		permissionSvc:  permissionSvc,
+		featureSvc:     featureSvc,
+		pricingTierSvc: pricingTierSvc,
+		warrantSvc:     warrantSvc,
+	}
+}
+
+// change is a ChangeSpec along with the spec needed to apply it
+type change struct {
+	ChangeSpec
+	spec interface{}
+}
+
+// Plan computes the changes needed to bring the datastore to the state
+// declared in manifest. Resources that are not in the manifest are left
+// alone unless prune is true, in which case they are deleted, except for:
+//  1. built-in object types, which are required by other endpoints
+//  2. warrants other than those between roles, permissions, features and
+//     pricing tiers, which assign them to users and tenants at runtime
+func (svc ManifestService) Plan(ctx context.Context, manifest ManifestSpec, prune bool) (*PlanSpec, error) {
+	changes, unchanged, err := svc.plan(ctx, manifest, prune)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := PlanSpec{
+		Prune:     prune,
+		Changes:   make([]ChangeSpec, 0, len(changes)),
+		Unchanged: unchanged,
+	}
+	for _, change := range changes {
+		plan.Changes = append(plan.Changes, change.ChangeSpec)
+	}
+
+	return &plan, nil
+}
+
+// Apply plans and applies the changes needed to bring the datastore to the
+// state declared in manifest in a single transaction. If any change fails,
+// none are applied.
+func (svc ManifestService) Apply(ctx context.Context, manifest ManifestSpec, prune bool) (*PlanSpec, error) {
+	plan := PlanSpec{
+		Prune:   prune,
+		Applied: true,
+		Changes: make([]ChangeSpec, 0),
+	}
+	err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		changes, unchanged, err := svc.plan(txCtx, manifest, prune)
+		if err != nil {
+			return err
+		}
+
+		for i, change := range changes {
+			err = svc.applyChange(txCtx, change)
+			if err != nil {
+				return changeError(i+1, change.ChangeSpec, err)
+			}
+
+			plan.Changes = append(plan.Changes, change.ChangeSpec)
+		}
+
+		plan.Unchanged = unchanged
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// plan returns the creates and updates of each resource type in
+// resourceTypes order followed by the deletes in reverse order, so warrants
+// are deleted before the roles, permissions, etc. they reference
+func (svc ManifestService) plan(ctx context.Context, manifest ManifestSpec, prune bool) ([]change, int64, error) {
+	upserts := make(map[string][]change)
+	deletes := make(map[string][]change)
+	var unchanged int64
+
+	// object types
+	declaredObjectTypes := make(map[string]bool)
+	for _, spec := range manifest.ObjectTypes {
+		declaredObjectTypes[spec.Type] = true
+		existing, err := svc.objectTypeSvc.GetByTypeId(ctx, spec.Type)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, 0, err
+			}
+
+			upserts[ResourceTypeObjectType] = append(upserts[ResourceTypeObjectType], newChange(ActionCreate, ResourceTypeObjectType, spec.Type, nil, spec, spec))
+			continue
+		}
+
+		if objectTypesEqual(*existing, spec) {
+			unchanged++
+			continue
+		}
+
+		upserts[ResourceTypeObjectType] = append(upserts[ResourceTypeObjectType], newChange(ActionUpdate, ResourceTypeObjectType, spec.Type, *existing, spec, spec))
+	}
+
+	if prune {
+		existingObjectTypes, err := listAll(objecttype.ObjectTypeListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]objecttype.ObjectTypeSpec, error) {
+			return svc.objectTypeSvc.List(ctx, listParams)
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, existing := range existingObjectTypes {
+			if declaredObjectTypes[existing.Type] || objecttype.IsBuiltIn(existing.Type) {
+				continue
+			}
+
+			deletes[ResourceTypeObjectType] = append(deletes[ResourceTypeObjectType], newChange(ActionDelete, ResourceTypeObjectType, existing.Type, existing, nil, nil))
+		}
+	}
+
+	// roles, permissions, features and pricing tiers
+	for _, resourceType := range []string{ResourceTypeRole, ResourceTypePermission, ResourceTypeFeature, ResourceTypePricingTier} {
+		declared := make(map[string]bool)
+		for _, resource := range declaredResources(manifest, resourceType) {
+			declared[resource.id] = true
+			existing, err := svc.getResource(ctx, resourceType, resource.id)
+			if err != nil {
+				if !isNotFound(err) {
+					return nil, 0, err
+				}
+
+				upserts[resourceType] = append(upserts[resourceType], newChange(ActionCreate, resourceType, resource.id, nil, resource.details, resource.details))
+				continue
+			}
+
+			if *existing == resource.details {
+				unchanged++
+				continue
+			}
+
+			upserts[resourceType] = append(upserts[resourceType], newChange(ActionUpdate, resourceType, resource.id, *existing, resource.details, resource.details))
+		}
+
+		if prune {
+			existingResources, err := svc.listResources(ctx, resourceType)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			for _, existing := range existingResources {
+				if declared[existing.id] {
+					continue
+				}
+
+				deletes[resourceType] = append(deletes[resourceType], newChange(ActionDelete, resourceType, existing.id, existing.details, nil, nil))
+			}
+		}
+	}
+
+	// warrants
+	declaredWarrants := make(map[string]bool)
+	for _, manifestWarrant := range manifest.Warrants {
+		spec := manifestWarrant.WarrantSpec
+		declaredWarrants[spec.String()] = true
+		_, err := svc.warrantSvc.Get(ctx, spec.ObjectType, spec.ObjectId, spec.Relation, spec.Subject.ObjectType, spec.Subject.ObjectId, spec.Subject.Relation, spec.Context)
+		if err != nil {
+			if !isNotFound(err) {
+				return nil, 0, err
+			}
+
+			upserts[ResourceTypeWarrant] = append(upserts[ResourceTypeWarrant], newChange(ActionCreate, ResourceTypeWarrant, spec.String(), nil, nil, spec))
+			continue
+		}
+
+		unchanged++
+	}
+
+	if prune {
+		for _, objectType := range []string{objecttype.ObjectTypeRole, objecttype.ObjectTypePermission, objecttype.ObjectTypeFeature, objecttype.ObjectTypePricingTier} {
+			existingWarrants, err := listAll(warrant.WarrantListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]*warrant.WarrantSpec, error) {
+				return svc.warrantSvc.List(ctx, &warrant.FilterOptions{ObjectType: objectType}, listParams)
+			})
+			if err != nil {
+				return nil, 0, err
+			}
+
+			for _, existing := range existingWarrants {
+				if declaredWarrants[existing.String()] || !isManagedWarrantSubject(existing.Subject.ObjectType) {
+					continue
+				}
+
+				deletes[ResourceTypeWarrant] = append(deletes[ResourceTypeWarrant], newChange(ActionDelete, ResourceTypeWarrant, existing.String(), nil, nil, *existing))
+			}
+		}
+	}
+
+	changes := make([]change, 0)
+	for _, resourceType := range resourceTypes {
+		changes = append(changes, upserts[resourceType]...)
+	}
+
+	for i := len(resourceTypes) - 1; i >= 0; i-- {
+		changes = append(changes, deletes[resourceTypes[i]]...)
+	}
+
+	return changes, unchanged, nil
+}
+
+func (svc ManifestService) applyChange(ctx context.Context, change change) error {
+	switch change.ResourceType {
+	case ResourceTypeObjectType:
+		switch change.Action {
+		case ActionCreate:
+			_, err := svc.objectTypeSvc.Create(ctx, change.spec.(objecttype.ObjectTypeSpec))
+			return err
+		case ActionUpdate:
+			_, err := svc.objectTypeSvc.UpdateByTypeId(ctx, change.ResourceId, change.spec.(objecttype.ObjectTypeSpec))
+			return err
+		default:
+			return svc.objectTypeSvc.DeleteByTypeId(ctx, change.ResourceId)
+		}
+	case ResourceTypeWarrant:
+		switch change.Action {
+		case ActionCreate:
+			_, err := svc.warrantSvc.Create(ctx, change.spec.(warrant.WarrantSpec))
+			return err
+		default:
+			return svc.warrantSvc.Delete(ctx, change.spec.(warrant.WarrantSpec))
+		}
+	default:
+		switch change.Action {
+		case ActionCreate:
+			return svc.createResource(ctx, change.ResourceType, change.ResourceId, change.spec.(ResourceDetailsSpec))
+		case ActionUpdate:
+			return svc.updateResource(ctx, change.ResourceType, change.ResourceId, change.spec.(ResourceDetailsSpec))
+		default:
+			return svc.deleteResource(ctx, change.ResourceType, change.ResourceId)
+		}
+	}
+}
+
+// resource is a role, permission, feature or pricing tier
+type resource struct {
+	id      string
+	details ResourceDetailsSpec
+}
+
+func declaredResources(manifest ManifestSpec, resourceType string) []resource {
+	resources := make([]resource, 0)
+	switch resourceType {
+	case ResourceTypeRole:
+		for _, spec := range manifest.Roles {
+			resources = append(resources, resource{spec.RoleId, ResourceDetailsSpec{spec.Name, spec.Description}})
+		}
+	case ResourceTypePermission:
+		for _, spec := range manifest.Permissions {
+			resources = append(resources, resource{spec.PermissionId, ResourceDetailsSpec{spec.Name, spec.Description}})
+		}
+	case ResourceTypeFeature:
+		for _, spec := range manifest.Features {
+			resources = append(resources, resource{spec.FeatureId, ResourceDetailsSpec{spec.Name, spec.Description}})
+		}
+	case ResourceTypePricingTier:
+		for _, spec := range manifest.PricingTiers {
+			resources = append(resources, resource{spec.PricingTierId, ResourceDetailsSpec{spec.Name, spec.Description}})
+		}
+	}
+
+	return resources
+}
+
+func (svc ManifestService) getResource(ctx context.Context, resourceType string, id string) (*ResourceDetailsSpec, error) {
+	switch resourceType {
+	case ResourceTypeRole:
+		spec, err := svc.roleSvc.GetByRoleId(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResourceDetailsSpec{spec.Name, spec.Description}, nil
+	case ResourceTypePermission:
+		spec, err := svc.permissionSvc.GetByPermissionId(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResourceDetailsSpec{spec.Name, spec.Description}, nil
+	case ResourceTypeFeature:
+		spec, err := svc.featureSvc.GetByFeatureId(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResourceDetailsSpec{spec.Name, spec.Description}, nil
+	default:
+		spec, err := svc.pricingTierSvc.GetByPricingTierId(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ResourceDetailsSpec{spec.Name, spec.Description}, nil
+	}
+}
+
+func (svc ManifestService) listResources(ctx context.Context, resourceType string) ([]resource, error) {
+	resources := make([]resource, 0)
+	switch resourceType {
+	case ResourceTypeRole:
+		specs, err := listAll(role.RoleListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]role.RoleSpec, error) {
+			return svc.roleSvc.List(ctx, listParams)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, spec := range specs {
+			resources = append(resources, resource{spec.RoleId, ResourceDetailsSpec{spec.Name, spec.Description}})
+		}
+	case ResourceTypePermission:
+		specs, err := listAll(permission.PermissionListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]permission.PermissionSpec, error) {
+			return svc.permissionSvc.List(ctx, listParams)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, spec := range specs {
+			resources = append(resources, resource{spec.PermissionId, ResourceDetailsSpec{spec.Name, spec.Description}})
+		}
+	case ResourceTypeFeature:
+		specs, err := listAll(feature.FeatureListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]feature.FeatureSpec, error) {
+			return svc.featureSvc.List(ctx, listParams)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, spec := range specs {
+			resources = append(resources, resource{spec.FeatureId, ResourceDetailsSpec{spec.Name, spec.Description}})
+		}
+	default:
+		specs, err := listAll(pricingtier.PricingTierListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]pricingtier.PricingTierSpec, error) {
+			return svc.pricingTierSvc.List(ctx, listParams)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, spec := range specs {
+			resources = append(resources, resource{spec.PricingTierId, ResourceDetailsSpec{spec.Name, spec.Description}})
+		}
+	}
+
+	return resources, nil
+}
+
+func (svc ManifestService) createResource(ctx context.Context, resourceType string, id string, details ResourceDetailsSpec) error {
+	var err error
+	switch resourceType {
+	case ResourceTypeRole:
+		_, err = svc.roleSvc.Create(ctx, role.RoleSpec{RoleId: id, Name: details.Name, Description: details.Description})
+	case ResourceTypePermission:
+		_, err = svc.permissionSvc.Create(ctx, permission.PermissionSpec{PermissionId: id, Name: details.Name, Description: details.Description})
+	case ResourceTypeFeature:
+		_, err = svc.featureSvc.Create(ctx, feature.FeatureSpec{FeatureId: id, Name: details.Name, Description: details.Description})
+	default:
+		_, err = svc.pricingTierSvc.Create(ctx, pricingtier.PricingTierSpec{PricingTierId: id, Name: details.Name, Description: details.Description})
+	}
+
+	return err
+}
+
+func (svc ManifestService) updateResource(ctx context.Context, resourceType string, id string, details ResourceDetailsSpec) error {
+	var err error
+	switch resourceType {
+	case ResourceTypeRole:
+		_, err = svc.roleSvc.UpdateByRoleId(ctx, id, role.UpdateRoleSpec{Name: details.Name, Description: details.Description})
+	case ResourceTypePermission:
+		_, err = svc.permissionSvc.UpdateByPermissionId(ctx, id, permission.UpdatePermissionSpec{Name: details.Name, Description: details.Description})
+	case ResourceTypeFeature:
+		_, err = svc.featureSvc.UpdateByFeatureId(ctx, id, feature.UpdateFeatureSpec{Name: details.Name, Description: details.Description})
+	default:
+		_, err = svc.pricingTierSvc.UpdateByPricingTierId(ctx, id, pricingtier.UpdatePricingTierSpec{Name: details.Name, Description: details.Description})
+	}
+
+	return err
+}
+
+func (svc ManifestService) deleteResource(ctx context.Context, resourceType string, id string) error {
+	switch resourceType {
+	case ResourceTypeRole:
+		return svc.roleSvc.DeleteByRoleId(ctx, id)
+	case ResourceTypePermission:
+		return svc.permissionSvc.DeleteByPermissionId(ctx, id)
+	case ResourceTypeFeature:
+		return svc.featureSvc.DeleteByFeatureId(ctx, id)
+	default:
+		return svc.pricingTierSvc.DeleteByPricingTierId(ctx, id)
+	}
+}
+
+func newChange(action string, resourceType string, resourceId string, before interface{}, after interface{}, spec interface{}) change {
+	return change{
+		ChangeSpec: ChangeSpec{
+			Action:       action,
+			ResourceType: resourceType,
+			ResourceId:   resourceId,
+			Before:       before,
+			After:        after,
+		},
+		spec: spec,
+	}
+}
+
+func listAll[T middleware.CursorSpec](sortBy string, list func(listParams middleware.ListParams) ([]T, error)) ([]T, error) {
+	all := make([]T, 0)
+	listParams := middleware.ListParams{
+		Page:      1,
+		Limit:     ListBatchSize,
+		SortBy:    sortBy,
+		SortOrder: middleware.SortOrderAsc,
+	}
+	for {
+		results, err := list(listParams)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, results...)
+		if len(results) < listParams.Limit {
+			return all, nil
+		}
+
+		cursor := results[len(results)-1].ToCursor(sortBy)
+		listParams.AfterId = cursor.ID
+		listParams.AfterValue = cursor.Value
+	}
+}
+
+// isManagedWarrantSubject returns true if warrants with subjects of the given
+// type are pruned from roles, permissions, features and pricing tiers
+func isManagedWarrantSubject(subjectType string) bool {
+	switch subjectType {
+	case objecttype.ObjectTypeRole, objecttype.ObjectTypePermission, objecttype.ObjectTypeFeature, objecttype.ObjectTypePricingTier:
+		return true
+	default:
+		return false
+	}
+}
+
+func isNotFound(err error) bool {
+	_, ok := err.(*service.RecordNotFoundError)
+	return ok
+}
+
+// changeError prefixes client errors with the number and description of the change that caused them
+func changeError(changeNumber int, change ChangeSpec, err error) error {
+	apiError, ok := err.(service.Error)
+	if !ok || apiError.GetStatus() >= http.StatusInternalServerError {
+		return err
+	}
+
+	return service.NewInvalidRequestError(fmt.Sprintf("Change %d (%s %s %s) could not be applied: %s", changeNumber, change.Action, change.ResourceType, change.ResourceId, err.Error()))
+}
+
+func objectTypesEqual(a objecttype.ObjectTypeSpec, b objecttype.ObjectTypeSpec) bool {
+	// NOTE: compare serialized definitions so unset and empty fields are equal
+	aJson, aErr := json.Marshal(a)
+	bJson, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJson) == string(bJson)
+}
@@ -0,0 +1,104 @@
+package authz
+
+import (
+	"encoding/json"
+	"fmt"
+
+	feature "github.com/warrant-dev/warrant/pkg/authz/feature"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	permission "github.com/warrant-dev/warrant/pkg/authz/permission"
+	pricingtier "github.com/warrant-dev/warrant/pkg/authz/pricingtier"
+	role "github.com/warrant-dev/warrant/pkg/authz/role"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// NOTE: resources are created and updated in this order (and deleted in
+// reverse order) so object types exist before the warrants that use them
+const (
+	ResourceTypeObjectType  = "object-type"
+	ResourceTypeRole        = "role"
+	ResourceTypePermission  = "permission"
+	ResourceTypeFeature     = "feature"
+	ResourceTypePricingTier = "pricing-tier"
+	ResourceTypeWarrant     = "warrant"
+)
+
+var resourceTypes = []string{
+	ResourceTypeObjectType,
+	ResourceTypeRole,
+	ResourceTypePermission,
+	ResourceTypeFeature,
+	ResourceTypePricingTier,
+	ResourceTypeWarrant,
+}
+
+// ManifestSpec type for the declared state of a datastore's object types,
+// roles, permissions, features and pricing tiers and the warrants that assign
+// them to each other
+type ManifestSpec struct {
+	ObjectTypes  []objecttype.ObjectTypeSpec   `json:"objectTypes,omitempty"`
+	Roles        []role.RoleSpec               `json:"roles,omitempty"`
+	Permissions  []permission.PermissionSpec   `json:"permissions,omitempty"`
+	Features     []feature.FeatureSpec         `json:"features,omitempty"`
+	PricingTiers []pricingtier.PricingTierSpec `json:"pricingTiers,omitempty"`
+	Warrants     []ManifestWarrantSpec         `json:"warrants,omitempty"`
+}
+
+// ManifestWarrantSpec is a warrant in a manifest. It can be given either as a
+// warrant string (e.g. permission:view-reports#member@role:admin#member) or
+// as a warrant object, and is always written as a warrant string.
+type ManifestWarrantSpec struct {
+	warrant.WarrantSpec
+}
+
+func (spec ManifestWarrantSpec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(spec.String())
+}
+
+func (spec *ManifestWarrantSpec) UnmarshalJSON(data []byte) error {
+	var warrantString string
+	if err := json.Unmarshal(data, &warrantString); err != nil {
+		return json.Unmarshal(data, &spec.WarrantSpec)
+	}
+
+	warrantSpec, err := warrant.StringToWarrantSpec(warrantString)
+	if err != nil {
+		return fmt.Errorf("%s must be a warrant in the format type:id#relation@subject[context]", warrantString)
+	}
+
+	spec.WarrantSpec = *warrantSpec
+	return nil
+}
+
+// ResourceDetailsSpec type for the fields of a role, permission, feature or
+// pricing tier that are managed by a manifest
+type ResourceDetailsSpec struct {
+	Name        database.NullString `json:"name"`
+	Description database.NullString `json:"description"`
+}
+
+// ChangeSpec type for a single create, update or delete in a plan. Before
+// and After hold the object type definition or the resource details.
+type ChangeSpec struct {
+	Action       string      `json:"action"`
+	ResourceType string      `json:"resourceType"`
+	ResourceId   string      `json:"resourceId"`
+	Before       interface{} `json:"before,omitempty"`
+	After        interface{} `json:"after,omitempty"`
+}
+
+// PlanSpec type for the changes needed to bring the datastore to the state
+// declared in a manifest, in the order they are (or were) applied
+type PlanSpec struct {
+	Prune     bool         `json:"prune"`
+	Applied   bool         `json:"applied"`
+	Changes   []ChangeSpec `json:"changes"`
+	Unchanged int64        `json:"unchanged"`
+}
@@ -28,14 +28,21 @@ func (svc ObjectService) Routes() []service.Route {
 			),
 		},
 		{
-			Pattern: "/v1/objects/{objectType}/{objectId}",
+			Pattern: "/v1/objects/{objectType}/{objectId:.+}",
 			Method:  "GET",
 			Handler: service.NewRouteHandler(svc, GetHandler),
 		},
 
+		// update
+		{
+			Pattern: "/v1/objects/{objectType}/{objectId:.+}",
+			Method:  "PUT",
+			Handler: service.NewRouteHandler(svc, UpdateHandler),
+		},
+
 		// delete
 		{
-			Pattern: "/v1/objects/{objectType}/{objectId}",
+			Pattern: "/v1/objects/{objectType}/{objectId:.+}",
 			Method:  "DELETE",
 			Handler: service.NewRouteHandler(svc, DeleteHandler),
 		},
@@ -72,6 +79,7 @@ func ListHandler(svc ObjectService, w http.ResponseWriter, r *http.Request) erro
 		return err
 	}
 
+	middleware.SetCursorHeaders(w, listParams, objects)
 	service.SendJSONResponse(w, objects)
 	return nil
 }
@@ -88,6 +96,24 @@ func GetHandler(svc ObjectService, w http.ResponseWriter, r *http.Request) error
 	return nil
 }
 
+func UpdateHandler(svc ObjectService, w http.ResponseWriter, r *http.Request) error {
+	var updateObject UpdateObjectSpec
+	err := service.ParseJSONBody(r.Body, &updateObject)
+	if err != nil {
+		return err
+	}
+
+	objectType := mux.Vars(r)["objectType"]
+	objectId := mux.Vars(r)["objectId"]
+	updatedObject, err := svc.UpdateByObjectTypeAndId(r.Context(), objectType, objectId, updateObject)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, updatedObject)
+	return nil
+}
+
 func DeleteHandler(svc ObjectService, w http.ResponseWriter, r *http.Request) error {
 	objectType := mux.Vars(r)["objectType"]
 	objectId := mux.Vars(r)["objectId"]
@@ -3,6 +3,8 @@ package authz
 import (
 	"fmt"
 	"time"
+
+	"github.com/warrant-dev/warrant/pkg/middleware"
 )
 
 type ObjectListParamParser struct{}
@@ -34,3 +36,14 @@ func (parser ObjectListParamParser) ParseValue(val string, sortBy string) (inter
 		return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
 	}
 }
+
+func (spec ObjectSpec) ToCursor(sortBy string) middleware.Cursor {
+	switch sortBy {
+	case "createdAt":
+		return middleware.NewCursor(spec.ObjectId, spec.CreatedAt)
+	case "objectType":
+		return middleware.NewCursor(spec.ObjectId, spec.ObjectType)
+	default:
+		return middleware.NewCursor(spec.ObjectId, nil)
+	}
+}
@@ -1,8 +1,10 @@
 package authz
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/rs/zerolog/log"
 	"github.com/warrant-dev/warrant/pkg/database"
 )
 
@@ -10,6 +12,8 @@ type Model interface {
 	GetID() int64
 	GetObjectType() string
 	GetObjectId() string
+	GetAttributes() database.NullString
+	SetAttributes(attributes database.NullString)
 	GetCreatedAt() time.Time
 	GetUpdatedAt() time.Time
 	GetDeletedAt() database.NullTime
@@ -17,12 +21,13 @@ type Model interface {
 }
 
 type Object struct {
-	ID         int64             `mysql:"id" postgres:"id"`
-	ObjectType string            `mysql:"objectType" postgres:"object_type"`
-	ObjectId   string            `mysql:"objectId" postgres:"object_id"`
-	CreatedAt  time.Time         `mysql:"createdAt" postgres:"created_at"`
-	UpdatedAt  time.Time         `mysql:"updatedAt" postgres:"updated_at"`
-	DeletedAt  database.NullTime `mysql:"deletedAt" postgres:"deleted_at"`
+	ID         int64               `mysql:"id" postgres:"id"`
+	ObjectType string              `mysql:"objectType" postgres:"object_type"`
+	ObjectId   string              `mysql:"objectId" postgres:"object_id"`
+	Attributes database.NullString `mysql:"attributes" postgres:"attributes"`
+	CreatedAt  time.Time           `mysql:"createdAt" postgres:"created_at"`
+	UpdatedAt  time.Time           `mysql:"updatedAt" postgres:"updated_at"`
+	DeletedAt  database.NullTime   `mysql:"deletedAt" postgres:"deleted_at"`
 }
 
 func (object Object) GetID() int64 {
@@ -37,6 +42,14 @@ func (object Object) GetObjectId() string {
 	return object.ObjectId
 }
 
+func (object Object) GetAttributes() database.NullString {
+	return object.Attributes
+}
+
+func (object *Object) SetAttributes(attributes database.NullString) {
+	object.Attributes = attributes
+}
+
 func (object Object) GetCreatedAt() time.Time {
 	return object.CreatedAt
 }
@@ -50,10 +63,19 @@ func (object Object) GetDeletedAt() database.NullTime {
 }
 
 func (object Object) ToObjectSpec() *ObjectSpec {
+	var attributes map[string]interface{}
+	if object.Attributes.Valid {
+		err := json.Unmarshal([]byte(object.Attributes.String), &attributes)
+		if err != nil {
+			log.Err(err).Msgf("Unable to unmarshal attributes of object %s:%s", object.ObjectType, object.ObjectId)
+		}
+	}
+
 	return &ObjectSpec{
 		ID:         object.ID,
 		ObjectType: object.ObjectType,
 		ObjectId:   object.ObjectId,
+		Attributes: attributes,
 		CreatedAt:  object.CreatedAt,
 	}
 }
@@ -29,14 +29,19 @@ func (repo MySQLRepository) Create(ctx context.Context, model Model) (int64, err
 		`
 			INSERT INTO object (
 				objectType,
-				objectId
-			) VALUES (?, ?)
+				objectId,
+				attributes
+			) VALUES (?, ?, ?)
 			ON DUPLICATE KEY UPDATE
+				attributes = ?,
 				createdAt = CURRENT_TIMESTAMP(6),
-				deletedAt = NULL
+				deletedAt = NULL,
+				deletionBatchId = NULL
 		`,
 		model.GetObjectType(),
 		model.GetObjectId(),
+		model.GetAttributes(),
+		model.GetAttributes(),
 	)
 	if err != nil {
 		return 0, errors.Wrap(err, "Unable to create object")
@@ -57,7 +62,7 @@ func (repo MySQLRepository) GetById(ctx context.Context, id int64) (Model, error
 		ctx,
 		&object,
 		`
-			SELECT id, objectType, objectId, createdAt, updatedAt, deletedAt
+			SELECT id, objectType, objectId, attributes, createdAt, updatedAt, deletedAt
 			FROM object
 			WHERE
 				id = ? AND
@@ -83,7 +88,7 @@ func (repo MySQLRepository) GetByObjectTypeAndId(ctx context.Context, objectType
 		ctx,
 		&object,
 		`
-			SELECT id, objectType, objectId, createdAt, updatedAt, deletedAt
+			SELECT id, objectType, objectId, attributes, createdAt, updatedAt, deletedAt
 			FROM object
 			WHERE
 				objectType = ? AND
@@ -109,7 +114,7 @@ func (repo MySQLRepository) List(ctx context.Context, filterOptions *FilterOptio
 	models := make([]Model, 0)
 	objects := make([]Object, 0)
 	query := `
-		SELECT id, objectType, objectId, createdAt, updatedAt, deletedAt
+		SELECT id, objectType, objectId, attributes, createdAt, updatedAt, deletedAt
 		FROM object
 		WHERE
 			deletedAt IS NULL
@@ -175,19 +180,20 @@ func (repo MySQLRepository) List(ctx context.Context, filterOptions *FilterOptio
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND objectId < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND objectId > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	if listParams.SortBy != "objectId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s, objectId %s LIMIT ?", query, listParams.SortBy, listParams.SortOrder, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s, objectId %s LIMIT ?", query, listParams.SortBy, sortOrder, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY objectId %s LIMIT ?", query, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY objectId %s LIMIT ?", query, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -210,22 +216,49 @@ func (repo MySQLRepository) List(ctx context.Context, filterOptions *FilterOptio
 		models = append(models, &objects[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
-func (repo MySQLRepository) DeleteByObjectTypeAndId(ctx context.Context, objectType string, objectId string) error {
+func (repo MySQLRepository) UpdateByObjectTypeAndId(ctx context.Context, objectType string, objectId string, model Model) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE object
+			SET
+				attributes = ?
+			WHERE
+				objectType = ? AND
+				objectId = ? AND
+				deletedAt IS NULL
+		`,
+		model.GetAttributes(),
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error updating object %s:%s", objectType, objectId))
+	}
+
+	return nil
+}
+
+func (repo MySQLRepository) DeleteByObjectTypeAndId(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE object
 			SET
-				deletedAt = ?
+				deletedAt = ?,
+				deletionBatchId = ?
 			WHERE
 				objectType = ? AND
 				objectId = ? AND
 				deletedAt IS NULL
 		`,
 		time.Now().UTC(),
+		deletionBatchId,
 		objectType,
 		objectId,
 	)
@@ -240,3 +273,28 @@ func (repo MySQLRepository) DeleteByObjectTypeAndId(ctx context.Context, objectT
 
 	return nil
 }
+
+func (repo MySQLRepository) RestoreByObjectTypeAndId(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE object
+			SET
+				deletedAt = NULL,
+				deletionBatchId = NULL
+			WHERE
+				objectType = ? AND
+				objectId = ? AND
+				deletionBatchId = ? AND
+				deletedAt IS NOT NULL
+		`,
+		objectType,
+		objectId,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to restore object %s:%s from mysql", objectType, objectId))
+	}
+
+	return nil
+}
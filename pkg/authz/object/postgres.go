@@ -31,15 +31,20 @@ func (repo PostgresRepository) Create(ctx context.Context, model Model) (int64,
 		`
 			INSERT INTO object (
 				object_type,
-				object_id
-			) VALUES (?, ?)
+				object_id,
+				attributes
+			) VALUES (?, ?, ?)
 			ON CONFLICT (object_type, object_id) DO UPDATE SET
+				attributes = ?,
 				created_at = CURRENT_TIMESTAMP(6),
-				deleted_at = NULL
+				deleted_at = NULL,
+				deletion_batch_id = NULL
 			RETURNING id
 		`,
 		model.GetObjectType(),
 		model.GetObjectId(),
+		model.GetAttributes(),
+		model.GetAttributes(),
 	)
 	if err != nil {
 		return 0, errors.Wrap(err, "Unable to create object")
@@ -54,7 +59,7 @@ func (repo PostgresRepository) GetById(ctx context.Context, id int64) (Model, er
 		ctx,
 		&object,
 		`
-			SELECT id, object_type, object_id, created_at, updated_at, deleted_at
+			SELECT id, object_type, object_id, attributes, created_at, updated_at, deleted_at
 			FROM object
 			WHERE
 				id = ? AND
@@ -80,7 +85,7 @@ func (repo PostgresRepository) GetByObjectTypeAndId(ctx context.Context, objectT
 		ctx,
 		&object,
 		`
-			SELECT id, object_type, object_id, created_at, updated_at, deleted_at
+			SELECT id, object_type, object_id, attributes, created_at, updated_at, deleted_at
 			FROM object
 			WHERE
 				object_type = ? AND
@@ -106,7 +111,7 @@ func (repo PostgresRepository) List(ctx context.Context, filterOptions *FilterOp
 	models := make([]Model, 0)
 	objects := make([]Object, 0)
 	query := `
-		SELECT id, object_type, object_id, created_at, updated_at, deleted_at
+		SELECT id, object_type, object_id, attributes, created_at, updated_at, deleted_at
 		FROM object
 		WHERE
 			deleted_at IS NULL
@@ -173,24 +178,25 @@ func (repo PostgresRepository) List(ctx context.Context, filterOptions *FilterOp
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND object_id < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND object_id > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	nullSortClause := "NULLS LAST"
-	if listParams.SortOrder == middleware.SortOrderAsc {
+	if sortOrder == middleware.SortOrderAsc {
 		nullSortClause = "NULLS FIRST"
 	}
 
 	if listParams.SortBy != "objectId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s %s, object_id %s LIMIT ?", query, sortBy, listParams.SortOrder, nullSortClause, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s %s, object_id %s LIMIT ?", query, sortBy, sortOrder, nullSortClause, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY object_id %s %s LIMIT ?", query, listParams.SortOrder, nullSortClause)
+		query = fmt.Sprintf("%s ORDER BY object_id %s %s LIMIT ?", query, sortOrder, nullSortClause)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -213,22 +219,49 @@ func (repo PostgresRepository) List(ctx context.Context, filterOptions *FilterOp
 		models = append(models, &objects[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
-func (repo PostgresRepository) DeleteByObjectTypeAndId(ctx context.Context, objectType string, objectId string) error {
+func (repo PostgresRepository) UpdateByObjectTypeAndId(ctx context.Context, objectType string, objectId string, model Model) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE object
+			SET
+				attributes = ?
+			WHERE
+				object_type = ? AND
+				object_id = ? AND
+				deleted_at IS NULL
+		`,
+		model.GetAttributes(),
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error updating object %s:%s", objectType, objectId))
+	}
+
+	return nil
+}
+
+func (repo PostgresRepository) DeleteByObjectTypeAndId(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE object
 			SET
-				deleted_at = ?
+				deleted_at = ?,
+				deletion_batch_id = ?
 			WHERE
 				object_type = ? AND
 				object_id = ? AND
 				deleted_at IS NULL
 		`,
 		time.Now().UTC(),
+		deletionBatchId,
 		objectType,
 		objectId,
 	)
@@ -243,3 +276,28 @@ func (repo PostgresRepository) DeleteByObjectTypeAndId(ctx context.Context, obje
 
 	return nil
 }
+
+func (repo PostgresRepository) RestoreByObjectTypeAndId(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE object
+			SET
+				deleted_at = NULL,
+				deletion_batch_id = NULL
+			WHERE
+				object_type = ? AND
+				object_id = ? AND
+				deletion_batch_id = ? AND
+				deleted_at IS NOT NULL
+		`,
+		objectType,
+		objectId,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to restore object %s:%s from postgres", objectType, objectId))
+	}
+
+	return nil
+}
@@ -13,7 +13,9 @@ type ObjectRepository interface {
 	GetById(ctx context.Context, id int64) (Model, error)
 	GetByObjectTypeAndId(ctx context.Context, objectType string, objectId string) (Model, error)
 	List(ctx context.Context, filterOptions *FilterOptions, listParams middleware.ListParams) ([]Model, error)
-	DeleteByObjectTypeAndId(ctx context.Context, objectType string, objectId string) error
+	UpdateByObjectTypeAndId(ctx context.Context, objectType string, objectId string, object Model) error
+	DeleteByObjectTypeAndId(ctx context.Context, objectType string, objectId string, deletionBatchId string) error
+	RestoreByObjectTypeAndId(ctx context.Context, objectType string, objectId string, deletionBatchId string) error
 }
 
 func NewRepository(db database.Database) (ObjectRepository, error) {
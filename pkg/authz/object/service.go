@@ -3,36 +3,63 @@ package authz
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
 	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/database"
 	"github.com/warrant-dev/warrant/pkg/event"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
 )
 
+// QuotaRepository deletes the quota limits and usage of an object. It's
+// implemented by the repositories of the quota package, which can't be
+// imported here since quotas depend on pricing tiers and features.
+type QuotaRepository interface {
+	DeleteAllForObject(ctx context.Context, objectType string, objectId string) error
+}
+
 type ObjectService struct {
 	service.BaseService
-	repo       ObjectRepository
-	eventSvc   event.EventService
-	warrantSvc warrant.WarrantService
+	repo          ObjectRepository
+	eventSvc      event.EventService
+	warrantSvc    warrant.WarrantService
+	objectTypeSvc objecttype.ObjectTypeService
+	quotaRepo     QuotaRepository
+	retentionDays int
 }
 
-func NewService(env service.Env, repo ObjectRepository, eventSvc event.EventService, warrantSvc warrant.WarrantService) ObjectService {
+func NewService(env service.Env, repo ObjectRepository, eventSvc event.EventService, warrantSvc warrant.WarrantService, objectTypeSvc objecttype.ObjectTypeService, quotaRepo QuotaRepository, retentionDays int) ObjectService {
 	return ObjectService{
-		BaseService: service.NewBaseService(env),
-		repo:        repo,
-		eventSvc:    eventSvc,
-		warrantSvc:  warrantSvc,
+		BaseService:   service.NewBaseService(env),
+		repo:          repo,
+		eventSvc:      eventSvc,
+		warrantSvc:    warrantSvc,
+		objectTypeSvc: objectTypeSvc,
+		quotaRepo:     quotaRepo,
+		retentionDays: retentionDays,
 	}
 }
 
 func (svc ObjectService) Create(ctx context.Context, objectSpec ObjectSpec) (*ObjectSpec, error) {
-	_, err := svc.repo.GetByObjectTypeAndId(ctx, objectSpec.ObjectType, objectSpec.ObjectId)
+	err := svc.objectTypeSvc.ValidateObjectId(ctx, objectSpec.ObjectType, objectSpec.ObjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.repo.GetByObjectTypeAndId(ctx, objectSpec.ObjectType, objectSpec.ObjectId)
 	if err == nil {
 		return nil, service.NewDuplicateRecordError("Object", fmt.Sprintf("%s:%s", objectSpec.ObjectType, objectSpec.ObjectId), "An object with the given objectType and objectId already exists")
 	}
 
-	newObjectId, err := svc.repo.Create(ctx, *objectSpec.ToObject())
+	object, err := objectSpec.ToObject()
+	if err != nil {
+		return nil, err
+	}
+
+	newObjectId, err := svc.repo.Create(ctx, object)
 	if err != nil {
 		return nil, err
 	}
@@ -46,23 +73,66 @@ func (svc ObjectService) Create(ctx context.Context, objectSpec ObjectSpec) (*Ob
 }
 
 func (svc ObjectService) DeleteByObjectTypeAndId(ctx context.Context, objectType string, objectId string) error {
+	return svc.DeleteByObjectTypeAndIdInBatch(ctx, objectType, objectId, uuid.New().String())
+}
+
+// DeleteByObjectTypeAndIdInBatch deletes the given object and its warrants,
+// tagging them with deletionBatchId so they can be restored together later.
+// The quota limits and usage of pricing tiers, features, tenants, and users
+// are deleted permanently, so they aren't restored with them.
+func (svc ObjectService) DeleteByObjectTypeAndIdInBatch(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
 	err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
-		err := svc.repo.DeleteByObjectTypeAndId(txCtx, objectType, objectId)
+		err := svc.repo.DeleteByObjectTypeAndId(txCtx, objectType, objectId, deletionBatchId)
 		if err != nil {
 			return err
 		}
 
-		err = svc.warrantSvc.DeleteRelatedWarrants(txCtx, objectType, objectId)
+		err = svc.warrantSvc.DeleteRelatedWarrants(txCtx, objectType, objectId, deletionBatchId)
 		if err != nil {
 			return err
 		}
 
+		switch objectType {
+		case objecttype.ObjectTypePricingTier, objecttype.ObjectTypeFeature, objecttype.ObjectTypeTenant, objecttype.ObjectTypeUser:
+			err = svc.quotaRepo.DeleteAllForObject(txCtx, objectType, objectId)
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 
 	return err
 }
 
+// ValidateRestorable returns an error if the given object was deleted longer
+// ago than the configured retention days, after which it may have been purged
+func (svc ObjectService) ValidateRestorable(objectType string, objectId string, deletedAt database.NullTime) error {
+	if svc.retentionDays <= 0 || !deletedAt.Valid {
+		return nil
+	}
+
+	if deletedAt.Time.Before(time.Now().UTC().AddDate(0, 0, -svc.retentionDays)) {
+		return service.NewInvalidRequestError(fmt.Sprintf("%s %s was deleted more than %d days ago and can no longer be restored", objectType, objectId, svc.retentionDays))
+	}
+
+	return nil
+}
+
+// RestoreByObjectTypeAndId restores the given object and the warrants that
+// were deleted along with it in the deletion batch with id deletionBatchId
+func (svc ObjectService) RestoreByObjectTypeAndId(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
+	return svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		err := svc.repo.RestoreByObjectTypeAndId(txCtx, objectType, objectId, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		return svc.warrantSvc.RestoreRelatedWarrants(txCtx, objectType, objectId, deletionBatchId)
+	})
+}
+
 func (svc ObjectService) GetByObjectId(ctx context.Context, objectType string, objectId string) (*ObjectSpec, error) {
 	object, err := svc.repo.GetByObjectTypeAndId(ctx, objectType, objectId)
 	if err != nil {
@@ -72,6 +142,31 @@ func (svc ObjectService) GetByObjectId(ctx context.Context, objectType string, o
 	return object.ToObjectSpec(), nil
 }
 
+func (svc ObjectService) UpdateByObjectTypeAndId(ctx context.Context, objectType string, objectId string, objectSpec UpdateObjectSpec) (*ObjectSpec, error) {
+	currentObject, err := svc.repo.GetByObjectTypeAndId(ctx, objectType, objectId)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := attributesToNullString(objectSpec.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	currentObject.SetAttributes(attributes)
+	err = svc.repo.UpdateByObjectTypeAndId(ctx, objectType, objectId, currentObject)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedObject, err := svc.repo.GetByObjectTypeAndId(ctx, objectType, objectId)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedObject.ToObjectSpec(), nil
+}
+
 func (svc ObjectService) List(ctx context.Context, filterOptions *FilterOptions, listParams middleware.ListParams) ([]ObjectSpec, error) {
 	objectSpecs := make([]ObjectSpec, 0)
 	objects, err := svc.repo.List(ctx, filterOptions, listParams)
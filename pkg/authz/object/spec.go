@@ -1,6 +1,12 @@
 package authz
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/warrant-dev/warrant/pkg/database"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
 
 type FilterOptions struct {
 	ObjectType string
@@ -9,21 +15,46 @@ type FilterOptions struct {
 type ObjectSpec struct {
 	// NOTE: ID is required here for internal use.
 	// However, we don't return it to the client.
-	ID         int64     `json:"-"`
-	ObjectType string    `json:"objectType" validate:"required,valid_object_type"`
-	ObjectId   string    `json:"objectId" validate:"required,valid_object_id"`
-	CreatedAt  time.Time `json:"createdAt"`
+	ID         int64                  `json:"-"`
+	ObjectType string                 `json:"objectType" validate:"required,valid_object_type"`
+	ObjectId   string                 `json:"objectId" validate:"required,valid_object_id_or_path"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	CreatedAt  time.Time              `json:"createdAt"`
 }
 
-func (spec ObjectSpec) ToObject() *Object {
+func (spec ObjectSpec) ToObject() (*Object, error) {
+	attributes, err := attributesToNullString(spec.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Object{
 		ObjectType: spec.ObjectType,
 		ObjectId:   spec.ObjectId,
+		Attributes: attributes,
 		CreatedAt:  spec.CreatedAt,
-	}
+	}, nil
 }
 
 type CreateObjectSpec struct {
 	ObjectType string `json:"objectType" validate:"required"`
 	ObjectId   string `json:"objectId" validate:"required"`
 }
+
+type UpdateObjectSpec struct {
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+func attributesToNullString(attributes map[string]interface{}) (database.NullString, error) {
+	if len(attributes) == 0 {
+		return database.StringToNullString(nil), nil
+	}
+
+	attributesJson, err := json.Marshal(attributes)
+	if err != nil {
+		return database.NullString{}, service.NewInvalidParameterError("attributes", "must be a valid json object")
+	}
+
+	attributesStr := string(attributesJson)
+	return database.StringToNullString(&attributesStr), nil
+}
@@ -0,0 +1,92 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+func (svc ObjectSyncService) Routes() []service.Route {
+	return []service.Route{
+		// sync
+		{
+			Pattern: "/v1/object-types/{type}/sync",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, SyncHandler),
+		},
+
+		// get
+		{
+			Pattern: "/v1/object-types/{type}/sync",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, GetHandler),
+		},
+		{
+			Pattern: "/v1/syncs",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListHandler),
+		},
+
+		// sync all
+		{
+			Pattern: "/v1/syncs",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, SyncAllHandler),
+		},
+	}
+}
+
+func SyncHandler(svc ObjectSyncService, w http.ResponseWriter, r *http.Request) error {
+	var runObjectSync RunObjectSyncSpec
+	err := service.ParseJSONBody(r.Body, &runObjectSync)
+	if err != nil {
+		return err
+	}
+
+	objectType := mux.Vars(r)["type"]
+	objectSync, err := svc.Sync(r.Context(), objectType, runObjectSync.Full)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, objectSync)
+	return nil
+}
+
+func SyncAllHandler(svc ObjectSyncService, w http.ResponseWriter, r *http.Request) error {
+	var runObjectSync RunObjectSyncSpec
+	err := service.ParseJSONBody(r.Body, &runObjectSync)
+	if err != nil {
+		return err
+	}
+
+	objectSyncs, err := svc.SyncAll(r.Context(), runObjectSync.Full)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, objectSyncs)
+	return nil
+}
+
+func GetHandler(svc ObjectSyncService, w http.ResponseWriter, r *http.Request) error {
+	objectType := mux.Vars(r)["type"]
+	objectSync, err := svc.GetByObjectType(r.Context(), objectType)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, objectSync)
+	return nil
+}
+
+func ListHandler(svc ObjectSyncService, w http.ResponseWriter, r *http.Request) error {
+	objectSyncs, err := svc.List(r.Context())
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, objectSyncs)
+	return nil
+}
@@ -0,0 +1,144 @@
+package authz
+
+import (
+	"time"
+
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+type Model interface {
+	GetID() int64
+	GetObjectType() string
+	GetStatus() string
+	SetStatus(string)
+	GetCursor() database.NullString
+	SetCursor(database.NullString)
+	GetRowsSynced() int64
+	SetRowsSynced(int64)
+	GetLastError() database.NullString
+	SetLastError(database.NullString)
+	GetLastSyncedAt() database.NullTime
+	SetLastSyncedAt(database.NullTime)
+	GetLastFullSyncAt() database.NullTime
+	SetLastFullSyncAt(database.NullTime)
+	GetCreatedAt() time.Time
+	GetUpdatedAt() time.Time
+	ToObjectSyncSpec() *ObjectSyncSpec
+}
+
+type ObjectSync struct {
+	ID             int64               `mysql:"id" postgres:"id"`
+	ObjectType     string              `mysql:"objectType" postgres:"object_type"`
+	Status         string              `mysql:"status" postgres:"status"`
+	Cursor         database.NullString `mysql:"syncCursor" postgres:"sync_cursor"`
+	RowsSynced     int64               `mysql:"rowsSynced" postgres:"rows_synced"`
+	LastError      database.NullString `mysql:"lastError" postgres:"last_error"`
+	LastSyncedAt   database.NullTime   `mysql:"lastSyncedAt" postgres:"last_synced_at"`
+	LastFullSyncAt database.NullTime   `mysql:"lastFullSyncAt" postgres:"last_full_sync_at"`
+	CreatedAt      time.Time           `mysql:"createdAt" postgres:"created_at"`
+	UpdatedAt      time.Time           `mysql:"updatedAt" postgres:"updated_at"`
+}
+
+func (objectSync ObjectSync) GetID() int64 {
+	return objectSync.ID
+}
+
+func (objectSync ObjectSync) GetObjectType() string {
+	return objectSync.ObjectType
+}
+
+func (objectSync ObjectSync) GetStatus() string {
+	return objectSync.Status
+}
+
+func (objectSync *ObjectSync) SetStatus(newStatus string) {
+	objectSync.Status = newStatus
+}
+
+func (objectSync ObjectSync) GetCursor() database.NullString {
+	return objectSync.Cursor
+}
+
+func (objectSync *ObjectSync) SetCursor(newCursor database.NullString) {
+	objectSync.Cursor = newCursor
+}
+
+func (objectSync ObjectSync) GetRowsSynced() int64 {
+	return objectSync.RowsSynced
+}
+
+func (objectSync *ObjectSync) SetRowsSynced(newRowsSynced int64) {
+	objectSync.RowsSynced = newRowsSynced
+}
+
+func (objectSync ObjectSync) GetLastError() database.NullString {
+	return objectSync.LastError
+}
+
+func (objectSync *ObjectSync) SetLastError(newLastError database.NullString) {
+	objectSync.LastError = newLastError
+}
+
+func (objectSync ObjectSync) GetLastSyncedAt() database.NullTime {
+	return objectSync.LastSyncedAt
+}
+
+func (objectSync *ObjectSync) SetLastSyncedAt(newLastSyncedAt database.NullTime) {
+	objectSync.LastSyncedAt = newLastSyncedAt
+}
+
+func (objectSync ObjectSync) GetLastFullSyncAt() database.NullTime {
+	return objectSync.LastFullSyncAt
+}
+
+func (objectSync *ObjectSync) SetLastFullSyncAt(newLastFullSyncAt database.NullTime) {
+	objectSync.LastFullSyncAt = newLastFullSyncAt
+}
+
+func (objectSync ObjectSync) GetCreatedAt() time.Time {
+	return objectSync.CreatedAt
+}
+
+func (objectSync ObjectSync) GetUpdatedAt() time.Time {
+	return objectSync.UpdatedAt
+}
+
+func (objectSync ObjectSync) ToObjectSyncSpec() *ObjectSyncSpec {
+	return &ObjectSyncSpec{
+		ObjectType:     objectSync.ObjectType,
+		Status:         objectSync.Status,
+		Cursor:         objectSync.Cursor,
+		RowsSynced:     objectSync.RowsSynced,
+		LastError:      objectSync.LastError,
+		LastSyncedAt:   objectSync.LastSyncedAt,
+		LastFullSyncAt: objectSync.LastFullSyncAt,
+		CreatedAt:      objectSync.CreatedAt,
+	}
+}
+
+// SyncedWarrant is a warrant created by a sync for a foreign key of the given
+// source row. Only synced warrants are revoked when the foreign key changes.
+type SyncedWarrant struct {
+	ID                int64  `mysql:"id" postgres:"id"`
+	ObjectType        string `mysql:"objectType" postgres:"object_type"`
+	ObjectId          string `mysql:"objectId" postgres:"object_id"`
+	ForeignKey        string `mysql:"foreignKey" postgres:"foreign_key"`
+	WarrantObjectType string `mysql:"warrantObjectType" postgres:"warrant_object_type"`
+	WarrantObjectId   string `mysql:"warrantObjectId" postgres:"warrant_object_id"`
+	Relation          string `mysql:"relation" postgres:"relation"`
+	SubjectType       string `mysql:"subjectType" postgres:"subject_type"`
+	SubjectId         string `mysql:"subjectId" postgres:"subject_id"`
+}
+
+func (syncedWarrant SyncedWarrant) ToWarrantSpec() warrant.WarrantSpec {
+	return warrant.WarrantSpec{
+		ObjectType: syncedWarrant.WarrantObjectType,
+		ObjectId:   syncedWarrant.WarrantObjectId,
+		Relation:   syncedWarrant.Relation,
+		Subject: &warrant.SubjectSpec{
+			ObjectType: syncedWarrant.SubjectType,
+			ObjectId:   syncedWarrant.SubjectId,
+		},
+	}
+}
@@ -0,0 +1,351 @@
+package authz
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/warrant-dev/warrant/pkg/database"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+type MySQLRepository struct {
+	database.SQLRepository
+}
+
+func NewMySQLRepository(db *database.MySQL) MySQLRepository {
+	return MySQLRepository{
+		database.NewSQLRepository(&db.SQL),
+	}
+}
+
+func (repo MySQLRepository) GetByObjectType(ctx context.Context, objectType string) (Model, error) {
+	var objectSync ObjectSync
+	err := repo.DB.GetContext(
+		ctx,
+		&objectSync,
+		`
+			SELECT id, objectType, status, syncCursor, rowsSynced, lastError, lastSyncedAt, lastFullSyncAt, createdAt, updatedAt
+			FROM objectSync
+			WHERE
+				objectType = ?
+		`,
+		objectType,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, service.NewRecordNotFoundError("ObjectSync", objectType)
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to get object sync for object type %s from mysql", objectType))
+		}
+	}
+
+	return &objectSync, nil
+}
+
+func (repo MySQLRepository) List(ctx context.Context) ([]Model, error) {
+	models := make([]Model, 0)
+	objectSyncs := make([]ObjectSync, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&objectSyncs,
+		`
+			SELECT id, objectType, status, syncCursor, rowsSynced, lastError, lastSyncedAt, lastFullSyncAt, createdAt, updatedAt
+			FROM objectSync
+			ORDER BY objectType ASC
+		`,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, "Unable to list object syncs from mysql")
+		}
+	}
+
+	for i := range objectSyncs {
+		models = append(models, &objectSyncs[i])
+	}
+
+	return models, nil
+}
+
+func (repo MySQLRepository) Upsert(ctx context.Context, model Model) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO objectSync (
+				objectType,
+				status,
+				syncCursor,
+				rowsSynced,
+				lastError,
+				lastSyncedAt,
+				lastFullSyncAt
+			) VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE
+				status = ?,
+				syncCursor = ?,
+				rowsSynced = ?,
+				lastError = ?,
+				lastSyncedAt = ?,
+				lastFullSyncAt = ?
+		`,
+		model.GetObjectType(),
+		model.GetStatus(),
+		model.GetCursor(),
+		model.GetRowsSynced(),
+		model.GetLastError(),
+		model.GetLastSyncedAt(),
+		model.GetLastFullSyncAt(),
+		model.GetStatus(),
+		model.GetCursor(),
+		model.GetRowsSynced(),
+		model.GetLastError(),
+		model.GetLastSyncedAt(),
+		model.GetLastFullSyncAt(),
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to upsert object sync for object type %s", model.GetObjectType()))
+	}
+
+	return nil
+}
+
+// Claim marks the sync of the given object type as in progress unless another
+// sync of it is in progress and was renewed within timeout. It returns false if
+// the sync could not be claimed.
+func (repo MySQLRepository) Claim(ctx context.Context, objectType string, timeout time.Duration) (bool, error) {
+	result, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT IGNORE INTO objectSync (
+				objectType,
+				status
+			) VALUES (?, ?)
+		`,
+		objectType,
+		StatusSyncing,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to claim object sync for object type %s", objectType))
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to claim object sync for object type %s", objectType))
+	}
+
+	if rows == 1 {
+		return true, nil
+	}
+
+	result, err = repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE objectSync
+			SET
+				status = ?,
+				updatedAt = CURRENT_TIMESTAMP(6)
+			WHERE
+				objectType = ? AND
+				(status <> ? OR updatedAt < CURRENT_TIMESTAMP(6) - INTERVAL ? SECOND)
+		`,
+		StatusSyncing,
+		objectType,
+		StatusSyncing,
+		int64(timeout.Seconds()),
+	)
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to claim object sync for object type %s", objectType))
+	}
+
+	rows, err = result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to claim object sync for object type %s", objectType))
+	}
+
+	return rows == 1, nil
+}
+
+// RenewClaim keeps an in progress sync of the given object type from being claimed by another sync
+func (repo MySQLRepository) RenewClaim(ctx context.Context, objectType string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE objectSync
+			SET updatedAt = CURRENT_TIMESTAMP(6)
+			WHERE
+				objectType = ? AND
+				status = ?
+		`,
+		objectType,
+		StatusSyncing,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to renew object sync claim for object type %s", objectType))
+	}
+
+	return nil
+}
+
+func (repo MySQLRepository) ListSyncedObjectIds(ctx context.Context, objectType string) ([]string, error) {
+	objectIds := make([]string, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&objectIds,
+		`
+			SELECT objectId
+			FROM objectSyncObject
+			WHERE
+				objectType = ?
+			ORDER BY objectId ASC
+		`,
+		objectType,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return objectIds, nil
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to list synced objects of object type %s from mysql", objectType))
+		}
+	}
+
+	return objectIds, nil
+}
+
+func (repo MySQLRepository) CreateSyncedObject(ctx context.Context, objectType string, objectId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT IGNORE INTO objectSyncObject (
+				objectType,
+				objectId
+			) VALUES (?, ?)
+		`,
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to create synced object %s:%s", objectType, objectId))
+	}
+
+	return nil
+}
+
+func (repo MySQLRepository) DeleteSyncedObject(ctx context.Context, objectType string, objectId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM objectSyncWarrant
+			WHERE
+				objectType = ? AND
+				objectId = ?
+		`,
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete synced warrants of object %s:%s", objectType, objectId))
+	}
+
+	_, err = repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM objectSyncObject
+			WHERE
+				objectType = ? AND
+				objectId = ?
+		`,
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete synced object %s:%s", objectType, objectId))
+	}
+
+	return nil
+}
+
+func (repo MySQLRepository) ListSyncedWarrants(ctx context.Context, objectType string, objectId string, foreignKey string) ([]SyncedWarrant, error) {
+	syncedWarrants := make([]SyncedWarrant, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&syncedWarrants,
+		`
+			SELECT id, objectType, objectId, foreignKey, warrantObjectType, warrantObjectId, relation, subjectType, subjectId
+			FROM objectSyncWarrant
+			WHERE
+				objectType = ? AND
+				objectId = ? AND
+				foreignKey = ?
+			ORDER BY id ASC
+		`,
+		objectType,
+		objectId,
+		foreignKey,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return syncedWarrants, nil
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to list synced warrants of object %s:%s from mysql", objectType, objectId))
+		}
+	}
+
+	return syncedWarrants, nil
+}
+
+func (repo MySQLRepository) CreateSyncedWarrant(ctx context.Context, syncedWarrant SyncedWarrant) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO objectSyncWarrant (
+				objectType,
+				objectId,
+				foreignKey,
+				warrantObjectType,
+				warrantObjectId,
+				relation,
+				subjectType,
+				subjectId
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+		syncedWarrant.ObjectType,
+		syncedWarrant.ObjectId,
+		syncedWarrant.ForeignKey,
+		syncedWarrant.WarrantObjectType,
+		syncedWarrant.WarrantObjectId,
+		syncedWarrant.Relation,
+		syncedWarrant.SubjectType,
+		syncedWarrant.SubjectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to create synced warrant for object %s:%s", syncedWarrant.ObjectType, syncedWarrant.ObjectId))
+	}
+
+	return nil
+}
+
+func (repo MySQLRepository) DeleteSyncedWarrant(ctx context.Context, id int64) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM objectSyncWarrant
+			WHERE
+				id = ?
+		`,
+		id,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete synced warrant %d", id))
+	}
+
+	return nil
+}
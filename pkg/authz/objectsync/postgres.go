@@ -0,0 +1,353 @@
+package authz
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/warrant-dev/warrant/pkg/database"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+type PostgresRepository struct {
+	database.SQLRepository
+}
+
+func NewPostgresRepository(db *database.Postgres) PostgresRepository {
+	return PostgresRepository{
+		database.NewSQLRepository(&db.SQL),
+	}
+}
+
+func (repo PostgresRepository) GetByObjectType(ctx context.Context, objectType string) (Model, error) {
+	var objectSync ObjectSync
+	err := repo.DB.GetContext(
+		ctx,
+		&objectSync,
+		`
+			SELECT id, object_type, status, sync_cursor, rows_synced, last_error, last_synced_at, last_full_sync_at, created_at, updated_at
+			FROM object_sync
+			WHERE
+				object_type = ?
+		`,
+		objectType,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, service.NewRecordNotFoundError("ObjectSync", objectType)
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to get object sync for object type %s from postgres", objectType))
+		}
+	}
+
+	return &objectSync, nil
+}
+
+func (repo PostgresRepository) List(ctx context.Context) ([]Model, error) {
+	models := make([]Model, 0)
+	objectSyncs := make([]ObjectSync, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&objectSyncs,
+		`
+			SELECT id, object_type, status, sync_cursor, rows_synced, last_error, last_synced_at, last_full_sync_at, created_at, updated_at
+			FROM object_sync
+			ORDER BY object_type ASC
+		`,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, "Unable to list object syncs from postgres")
+		}
+	}
+
+	for i := range objectSyncs {
+		models = append(models, &objectSyncs[i])
+	}
+
+	return models, nil
+}
+
+func (repo PostgresRepository) Upsert(ctx context.Context, model Model) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO object_sync (
+				object_type,
+				status,
+				sync_cursor,
+				rows_synced,
+				last_error,
+				last_synced_at,
+				last_full_sync_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (object_type) DO UPDATE SET
+				status = ?,
+				sync_cursor = ?,
+				rows_synced = ?,
+				last_error = ?,
+				last_synced_at = ?,
+				last_full_sync_at = ?
+		`,
+		model.GetObjectType(),
+		model.GetStatus(),
+		model.GetCursor(),
+		model.GetRowsSynced(),
+		model.GetLastError(),
+		model.GetLastSyncedAt(),
+		model.GetLastFullSyncAt(),
+		model.GetStatus(),
+		model.GetCursor(),
+		model.GetRowsSynced(),
+		model.GetLastError(),
+		model.GetLastSyncedAt(),
+		model.GetLastFullSyncAt(),
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to upsert object sync for object type %s", model.GetObjectType()))
+	}
+
+	return nil
+}
+
+// Claim marks the sync of the given object type as in progress unless another
+// sync of it is in progress and was renewed within timeout. It returns false if
+// the sync could not be claimed.
+func (repo PostgresRepository) Claim(ctx context.Context, objectType string, timeout time.Duration) (bool, error) {
+	result, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO object_sync (
+				object_type,
+				status
+			) VALUES (?, ?)
+			ON CONFLICT (object_type) DO NOTHING
+		`,
+		objectType,
+		StatusSyncing,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to claim object sync for object type %s", objectType))
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to claim object sync for object type %s", objectType))
+	}
+
+	if rows == 1 {
+		return true, nil
+	}
+
+	result, err = repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE object_sync
+			SET
+				status = ?,
+				updated_at = CURRENT_TIMESTAMP(6)
+			WHERE
+				object_type = ? AND
+				(status <> ? OR updated_at < CURRENT_TIMESTAMP(6) - make_interval(secs => ?))
+		`,
+		StatusSyncing,
+		objectType,
+		StatusSyncing,
+		int64(timeout.Seconds()),
+	)
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to claim object sync for object type %s", objectType))
+	}
+
+	rows, err = result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to claim object sync for object type %s", objectType))
+	}
+
+	return rows == 1, nil
+}
+
+// RenewClaim keeps an in progress sync of the given object type from being claimed by another sync
+func (repo PostgresRepository) RenewClaim(ctx context.Context, objectType string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE object_sync
+			SET updated_at = CURRENT_TIMESTAMP(6)
+			WHERE
+				object_type = ? AND
+				status = ?
+		`,
+		objectType,
+		StatusSyncing,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to renew object sync claim for object type %s", objectType))
+	}
+
+	return nil
+}
+
+func (repo PostgresRepository) ListSyncedObjectIds(ctx context.Context, objectType string) ([]string, error) {
+	objectIds := make([]string, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&objectIds,
+		`
+			SELECT object_id
+			FROM object_sync_object
+			WHERE
+				object_type = ?
+			ORDER BY object_id ASC
+		`,
+		objectType,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return objectIds, nil
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to list synced objects of object type %s from postgres", objectType))
+		}
+	}
+
+	return objectIds, nil
+}
+
+func (repo PostgresRepository) CreateSyncedObject(ctx context.Context, objectType string, objectId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO object_sync_object (
+				object_type,
+				object_id
+			) VALUES (?, ?)
+			ON CONFLICT (object_type, object_id) DO NOTHING
+		`,
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to create synced object %s:%s", objectType, objectId))
+	}
+
+	return nil
+}
+
+func (repo PostgresRepository) DeleteSyncedObject(ctx context.Context, objectType string, objectId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM object_sync_warrant
+			WHERE
+				object_type = ? AND
+				object_id = ?
+		`,
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete synced warrants of object %s:%s", objectType, objectId))
+	}
+
+	_, err = repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM object_sync_object
+			WHERE
+				object_type = ? AND
+				object_id = ?
+		`,
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete synced object %s:%s", objectType, objectId))
+	}
+
+	return nil
+}
+
+func (repo PostgresRepository) ListSyncedWarrants(ctx context.Context, objectType string, objectId string, foreignKey string) ([]SyncedWarrant, error) {
+	syncedWarrants := make([]SyncedWarrant, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&syncedWarrants,
+		`
+			SELECT id, object_type, object_id, foreign_key, warrant_object_type, warrant_object_id, relation, subject_type, subject_id
+			FROM object_sync_warrant
+			WHERE
+				object_type = ? AND
+				object_id = ? AND
+				foreign_key = ?
+			ORDER BY id ASC
+		`,
+		objectType,
+		objectId,
+		foreignKey,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return syncedWarrants, nil
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to list synced warrants of object %s:%s from postgres", objectType, objectId))
+		}
+	}
+
+	return syncedWarrants, nil
+}
+
+func (repo PostgresRepository) CreateSyncedWarrant(ctx context.Context, syncedWarrant SyncedWarrant) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO object_sync_warrant (
+				object_type,
+				object_id,
+				foreign_key,
+				warrant_object_type,
+				warrant_object_id,
+				relation,
+				subject_type,
+				subject_id
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`,
+		syncedWarrant.ObjectType,
+		syncedWarrant.ObjectId,
+		syncedWarrant.ForeignKey,
+		syncedWarrant.WarrantObjectType,
+		syncedWarrant.WarrantObjectId,
+		syncedWarrant.Relation,
+		syncedWarrant.SubjectType,
+		syncedWarrant.SubjectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to create synced warrant for object %s:%s", syncedWarrant.ObjectType, syncedWarrant.ObjectId))
+	}
+
+	return nil
+}
+
+func (repo PostgresRepository) DeleteSyncedWarrant(ctx context.Context, id int64) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM object_sync_warrant
+			WHERE
+				id = ?
+		`,
+		id,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete synced warrant %d", id))
+	}
+
+	return nil
+}
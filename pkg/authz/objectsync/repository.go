@@ -0,0 +1,44 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+type ObjectSyncRepository interface {
+	GetByObjectType(ctx context.Context, objectType string) (Model, error)
+	List(ctx context.Context) ([]Model, error)
+	Upsert(ctx context.Context, objectSync Model) error
+	Claim(ctx context.Context, objectType string, timeout time.Duration) (bool, error)
+	RenewClaim(ctx context.Context, objectType string) error
+	ListSyncedObjectIds(ctx context.Context, objectType string) ([]string, error)
+	CreateSyncedObject(ctx context.Context, objectType string, objectId string) error
+	DeleteSyncedObject(ctx context.Context, objectType string, objectId string) error
+	ListSyncedWarrants(ctx context.Context, objectType string, objectId string, foreignKey string) ([]SyncedWarrant, error)
+	CreateSyncedWarrant(ctx context.Context, syncedWarrant SyncedWarrant) error
+	DeleteSyncedWarrant(ctx context.Context, id int64) error
+}
+
+func NewRepository(db database.Database) (ObjectSyncRepository, error) {
+	switch db.Type() {
+	case database.TypeMySQL:
+		mysql, ok := db.(*database.MySQL)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s database config", database.TypeMySQL)
+		}
+
+		return NewMySQLRepository(mysql), nil
+	case database.TypePostgres:
+		postgres, ok := db.(*database.Postgres)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s database config", database.TypePostgres)
+		}
+
+		return NewPostgresRepository(postgres), nil
+	default:
+		return nil, fmt.Errorf("unsupported database type %s specified", db.Type())
+	}
+}
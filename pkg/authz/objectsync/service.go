@@ -0,0 +1,471 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	object "github.com/warrant-dev/warrant/pkg/authz/object"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	tenant "github.com/warrant-dev/warrant/pkg/authz/tenant"
+	user "github.com/warrant-dev/warrant/pkg/authz/user"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/config"
+	"github.com/warrant-dev/warrant/pkg/database"
+	"github.com/warrant-dev/warrant/pkg/middleware"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+const (
+	listBatchSize    = 1000
+	syncClaimTimeout = 5 * time.Minute // NOTE: a sync not renewed within this long (e.g. its replica died) can be claimed by another
+)
+
+type ObjectSyncService struct {
+	service.BaseService
+	repo          ObjectSyncRepository
+	objectTypeSvc objecttype.ObjectTypeService
+	objectSvc     object.ObjectService
+	userSvc       user.UserService
+	tenantSvc     tenant.TenantService
+	warrantSvc    warrant.WarrantService
+	sources       *sourceConnections
+}
+
+func NewService(env service.Env, repo ObjectSyncRepository, sourceConfigs map[string]config.DatastoreConfig, objectTypeSvc objecttype.ObjectTypeService, objectSvc object.ObjectService, userSvc user.UserService, tenantSvc tenant.TenantService, warrantSvc warrant.WarrantService) ObjectSyncService {
+	return ObjectSyncService{
+		BaseService:   service.NewBaseService(env),
+		repo:          repo,
+		objectTypeSvc: objectTypeSvc,
+		objectSvc:     objectSvc,
+		userSvc:       userSvc,
+		tenantSvc:     tenantSvc,
+		warrantSvc:    warrantSvc,
+		sources:       newSourceConnections(sourceConfigs),
+	}
+}
+
+// Sync reads the source table of the given object type and creates an object
+// for each row and a warrant for each non-null foreign key of each row. A full
+// sync reads every row and deletes the objects it created that are no longer
+// present in the source. Objects and warrants created through the API are
+// never deleted by a sync. An incremental sync only reads rows updated since the last sync and is only
+// possible if the source has an updatedAtColumn.
+func (svc ObjectSyncService) Sync(ctx context.Context, objectType string, full bool) (*ObjectSyncSpec, error) {
+	objectTypeSpec, err := svc.objectTypeSvc.GetByTypeId(ctx, objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	if objectTypeSpec.Source == nil {
+		return nil, service.NewInvalidRequestError(fmt.Sprintf("Object type %s does not have a source", objectType))
+	}
+
+	claimed, err := svc.repo.Claim(ctx, objectType, syncClaimTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if !claimed {
+		return nil, service.NewInvalidRequestError(fmt.Sprintf("A sync of object type %s is already in progress", objectType))
+	}
+
+	objectSync, err := svc.repo.GetByObjectType(ctx, objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	if objectTypeSpec.Source.UpdatedAtColumn == "" || !objectSync.GetCursor().Valid {
+		full = true
+	}
+
+	cursor := ""
+	if !full {
+		cursor = objectSync.GetCursor().String
+	}
+
+	log.Debug().Msgf("Syncing object type %s from source table %s (full: %t)", objectType, objectTypeSpec.Source.Table, full)
+	claim := &syncClaim{objectType: objectType, renewedAt: time.Now()}
+	rowsSynced, newCursor, syncErr := svc.syncRows(ctx, objectTypeSpec, claim, cursor, full)
+	now := database.NullTime{}
+	now.Time = time.Now().UTC()
+	now.Valid = true
+	if syncErr != nil {
+		log.Err(syncErr).Msgf("Error syncing object type %s", objectType)
+		errMsg := syncErr.Error()
+		objectSync.SetStatus(StatusFailed)
+		objectSync.SetLastError(database.StringToNullString(&errMsg))
+	} else {
+		objectSync.SetStatus(StatusSynced)
+		objectSync.SetLastError(database.StringToNullString(nil))
+		objectSync.SetRowsSynced(rowsSynced)
+		objectSync.SetLastSyncedAt(now)
+		if full {
+			objectSync.SetLastFullSyncAt(now)
+		}
+
+		if newCursor != "" {
+			objectSync.SetCursor(database.StringToNullString(&newCursor))
+		}
+	}
+
+	err = svc.repo.Upsert(ctx, objectSync)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.GetByObjectType(ctx, objectType)
+}
+
+func (svc ObjectSyncService) GetByObjectType(ctx context.Context, objectType string) (*ObjectSyncSpec, error) {
+	objectSync, err := svc.repo.GetByObjectType(ctx, objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	return objectSync.ToObjectSyncSpec(), nil
+}
+
+func (svc ObjectSyncService) List(ctx context.Context) ([]ObjectSyncSpec, error) {
+	objectSyncSpecs := make([]ObjectSyncSpec, 0)
+	objectSyncs, err := svc.repo.List(ctx)
+	if err != nil {
+		return objectSyncSpecs, err
+	}
+
+	for _, objectSync := range objectSyncs {
+		objectSyncSpecs = append(objectSyncSpecs, *objectSync.ToObjectSyncSpec())
+	}
+
+	return objectSyncSpecs, nil
+}
+
+// StartPolling syncs every source-backed object type each pollInterval until
+// ctx is cancelled. Object types with an updatedAtColumn are synced incrementally.
+func (svc ObjectSyncService) StartPolling(ctx context.Context, pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, err := svc.SyncAll(ctx, false)
+				if err != nil {
+					log.Err(err).Msg("Error syncing object types")
+				}
+			}
+		}
+	}()
+}
+
+// SyncAll syncs every source-backed object type (see Sync) and returns the
+// resulting syncs. Object types already being synced elsewhere are skipped.
+func (svc ObjectSyncService) SyncAll(ctx context.Context, full bool) ([]ObjectSyncSpec, error) {
+	objectSyncSpecs := make([]ObjectSyncSpec, 0)
+	listParams := middleware.ListParams{
+		Limit:     listBatchSize,
+		SortBy:    objecttype.ObjectTypeListParamParser{}.GetDefaultSortBy(),
+		SortOrder: middleware.SortOrderAsc,
+	}
+	for {
+		objectTypeSpecs, err := svc.objectTypeSvc.List(ctx, listParams)
+		if err != nil {
+			return objectSyncSpecs, err
+		}
+
+		for _, objectTypeSpec := range objectTypeSpecs {
+			if objectTypeSpec.Source == nil {
+				continue
+			}
+
+			objectSyncSpec, err := svc.Sync(ctx, objectTypeSpec.Type, full)
+			if err != nil {
+				log.Err(err).Msgf("Error syncing object type %s", objectTypeSpec.Type)
+				continue
+			}
+
+			objectSyncSpecs = append(objectSyncSpecs, *objectSyncSpec)
+		}
+
+		if len(objectTypeSpecs) < listBatchSize {
+			return objectSyncSpecs, nil
+		}
+
+		listParams.AfterId = objectTypeSpecs[len(objectTypeSpecs)-1].Type
+	}
+}
+
+func (svc ObjectSyncService) syncRows(ctx context.Context, objectTypeSpec *objecttype.ObjectTypeSpec, claim *syncClaim, cursor string, full bool) (int64, string, error) {
+	db, err := svc.sources.get(ctx, *objectTypeSpec.Source)
+	if err != nil {
+		return 0, "", err
+	}
+
+	var rowsSynced int64
+	newCursor := cursor
+	sourceObjectIds := make(map[string]bool)
+	err = readRows(ctx, db, *objectTypeSpec.Source, cursor, func(row SourceRow) error {
+		err := svc.renewClaim(ctx, claim)
+		if err != nil {
+			return err
+		}
+
+		err = svc.syncRow(ctx, objectTypeSpec, row)
+		if err != nil {
+			return err
+		}
+
+		rowsSynced++
+		sourceObjectIds[row.ObjectId] = true
+		if row.UpdatedAt != nil {
+			newCursor = *row.UpdatedAt
+		}
+
+		return nil
+	})
+	if err != nil {
+		return rowsSynced, "", err
+	}
+
+	if full {
+		err = svc.deleteUnsyncedObjects(ctx, objectTypeSpec.Type, claim, sourceObjectIds)
+		if err != nil {
+			return rowsSynced, "", err
+		}
+	}
+
+	return rowsSynced, newCursor, nil
+}
+
+func (svc ObjectSyncService) syncRow(ctx context.Context, objectTypeSpec *objecttype.ObjectTypeSpec, row SourceRow) error {
+	err := objectTypeSpec.ValidateObjectId(row.ObjectId)
+	if err != nil {
+		return err
+	}
+
+	objectSpec := object.ObjectSpec{
+		ObjectType: objectTypeSpec.Type,
+		ObjectId:   row.ObjectId,
+	}
+	err = service.ValidateStruct(&objectSpec)
+	if err != nil {
+		return err
+	}
+
+	return svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		_, err := svc.objectSvc.GetByObjectId(txCtx, objectTypeSpec.Type, row.ObjectId)
+		if err != nil {
+			switch err.(type) {
+			case *service.RecordNotFoundError:
+				err = svc.createObject(txCtx, objectSpec)
+				if err != nil {
+					return err
+				}
+
+				err = svc.repo.CreateSyncedObject(txCtx, objectTypeSpec.Type, row.ObjectId)
+				if err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+		}
+
+		for _, foreignKey := range objectTypeSpec.Source.ForeignKeys {
+			err = svc.syncForeignKey(txCtx, objectTypeSpec.Type, row.ObjectId, foreignKey, row.ForeignKeys[foreignKey.Column])
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// syncForeignKey makes the warrant for a row's foreign key match the column
+// value. If the foreign key's type is the subject, the warrant is
+// <objectType>:<row id>#<relation>@<type>:<value>. If the source's own object
+// type is the subject, the warrant is <type>:<value>#<relation>@<objectType>:<row id>.
+// Warrants previously created by the sync for the foreign key (e.g. for a
+// previous column value) are revoked. Warrants created through the API are left as is.
+func (svc ObjectSyncService) syncForeignKey(ctx context.Context, objectType string, objectId string, foreignKey objecttype.ForeignKeySpec, value *string) error {
+	var desiredWarrant *warrant.WarrantSpec
+	switch foreignKey.Subject {
+	case foreignKey.Type:
+		if value != nil {
+			desiredWarrant = &warrant.WarrantSpec{
+				ObjectType: objectType,
+				ObjectId:   objectId,
+				Relation:   foreignKey.Relation,
+				Subject: &warrant.SubjectSpec{
+					ObjectType: foreignKey.Type,
+					ObjectId:   *value,
+				},
+			}
+		}
+	case objectType:
+		if value != nil {
+			desiredWarrant = &warrant.WarrantSpec{
+				ObjectType: foreignKey.Type,
+				ObjectId:   *value,
+				Relation:   foreignKey.Relation,
+				Subject: &warrant.SubjectSpec{
+					ObjectType: objectType,
+					ObjectId:   objectId,
+				},
+			}
+		}
+	default:
+		return fmt.Errorf("subject of foreign key %s must be either %s or %s", foreignKey.Column, foreignKey.Type, objectType)
+	}
+
+	syncedWarrants, err := svc.repo.ListSyncedWarrants(ctx, objectType, objectId, foreignKey.Column)
+	if err != nil {
+		return err
+	}
+
+	desiredWarrantSynced := false
+	for _, syncedWarrant := range syncedWarrants {
+		syncedWarrantSpec := syncedWarrant.ToWarrantSpec()
+		if desiredWarrant != nil && syncedWarrantSpec.String() == desiredWarrant.String() {
+			desiredWarrantSynced = true
+			continue
+		}
+
+		err = svc.warrantSvc.Delete(ctx, syncedWarrantSpec)
+		if err != nil {
+			if _, ok := err.(*service.RecordNotFoundError); !ok {
+				return err
+			}
+		}
+
+		err = svc.repo.DeleteSyncedWarrant(ctx, syncedWarrant.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if desiredWarrant == nil {
+		return nil
+	}
+
+	_, err = svc.warrantSvc.Get(ctx, desiredWarrant.ObjectType, desiredWarrant.ObjectId, desiredWarrant.Relation, desiredWarrant.Subject.ObjectType, desiredWarrant.Subject.ObjectId, "", nil)
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(*service.RecordNotFoundError); !ok {
+		return err
+	}
+
+	_, err = svc.warrantSvc.Create(ctx, *desiredWarrant)
+	if err != nil {
+		return err
+	}
+
+	if desiredWarrantSynced {
+		return nil
+	}
+
+	return svc.repo.CreateSyncedWarrant(ctx, SyncedWarrant{
+		ObjectType:        objectType,
+		ObjectId:          objectId,
+		ForeignKey:        foreignKey.Column,
+		WarrantObjectType: desiredWarrant.ObjectType,
+		WarrantObjectId:   desiredWarrant.ObjectId,
+		Relation:          desiredWarrant.Relation,
+		SubjectType:       desiredWarrant.Subject.ObjectType,
+		SubjectId:         desiredWarrant.Subject.ObjectId,
+	})
+}
+
+// deleteUnsyncedObjects deletes the objects created by previous syncs of the
+// given object type that are not in sourceObjectIds, along with their warrants
+func (svc ObjectSyncService) deleteUnsyncedObjects(ctx context.Context, objectType string, claim *syncClaim, sourceObjectIds map[string]bool) error {
+	syncedObjectIds, err := svc.repo.ListSyncedObjectIds(ctx, objectType)
+	if err != nil {
+		return err
+	}
+
+	for _, objectId := range syncedObjectIds {
+		if sourceObjectIds[objectId] {
+			continue
+		}
+
+		err = svc.renewClaim(ctx, claim)
+		if err != nil {
+			return err
+		}
+
+		log.Debug().Msgf("Deleting object %s:%s no longer present in source", objectType, objectId)
+		err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+			err := svc.deleteObject(txCtx, objectType, objectId)
+			if err != nil {
+				if _, ok := err.(*service.RecordNotFoundError); !ok {
+					return err
+				}
+			}
+
+			return svc.repo.DeleteSyncedObject(txCtx, objectType, objectId)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// syncClaim is a sync's claim on its object type (see ObjectSyncRepository.Claim)
+type syncClaim struct {
+	objectType string
+	renewedAt  time.Time
+}
+
+// renewClaim renews the given claim if a quarter of syncClaimTimeout has passed
+// since it was last renewed, so that long syncs aren't claimed by another replica
+func (svc ObjectSyncService) renewClaim(ctx context.Context, claim *syncClaim) error {
+	if time.Since(claim.renewedAt) < syncClaimTimeout/4 {
+		return nil
+	}
+
+	err := svc.repo.RenewClaim(ctx, claim.objectType)
+	if err != nil {
+		return err
+	}
+
+	claim.renewedAt = time.Now()
+	return nil
+}
+
+// createObject creates the given object. Users and tenants are created through
+// their own services so they have a user or tenant in addition to the object.
+func (svc ObjectSyncService) createObject(ctx context.Context, objectSpec object.ObjectSpec) error {
+	var err error
+	switch objectSpec.ObjectType {
+	case objecttype.ObjectTypeUser:
+		_, err = svc.userSvc.Create(ctx, user.UserSpec{UserId: objectSpec.ObjectId})
+	case objecttype.ObjectTypeTenant:
+		_, err = svc.tenantSvc.Create(ctx, tenant.TenantSpec{TenantId: objectSpec.ObjectId})
+	default:
+		_, err = svc.objectSvc.Create(ctx, objectSpec)
+	}
+
+	return err
+}
+
+// deleteObject deletes the given object and its warrants. Users and tenants are
+// deleted through their own services so no user or tenant is left without an object.
+func (svc ObjectSyncService) deleteObject(ctx context.Context, objectType string, objectId string) error {
+	switch objectType {
+	case objecttype.ObjectTypeUser:
+		return svc.userSvc.DeleteByUserId(ctx, objectId)
+	case objecttype.ObjectTypeTenant:
+		return svc.tenantSvc.DeleteByTenantId(ctx, objectId)
+	default:
+		return svc.objectSvc.DeleteByObjectTypeAndId(ctx, objectType, objectId)
+	}
+}
@@ -0,0 +1,192 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	"github.com/warrant-dev/warrant/pkg/config"
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+const (
+	primaryKeySeparator = "|"
+	cursorTimeFormat    = "2006-01-02 15:04:05.999999"
+)
+
+// SourceRow is a row read from an object type's source table
+type SourceRow struct {
+	ObjectId    string
+	ForeignKeys map[string]*string // NOTE: map key = column name, nil if the column is NULL
+	UpdatedAt   *string
+}
+
+// sourceConnections lazily opens and caches a connection to each configured source database
+type sourceConnections struct {
+	configs map[string]config.DatastoreConfig
+	dbs     map[string]*sqlx.DB
+	mutex   sync.Mutex
+}
+
+func newSourceConnections(configs map[string]config.DatastoreConfig) *sourceConnections {
+	return &sourceConnections{
+		configs: configs,
+		dbs:     make(map[string]*sqlx.DB),
+	}
+}
+
+func (conns *sourceConnections) get(ctx context.Context, source objecttype.Source) (*sqlx.DB, error) {
+	conns.mutex.Lock()
+	defer conns.mutex.Unlock()
+
+	key := fmt.Sprintf("%s:%s", source.DatabaseType, source.DatabaseName)
+	if db, ok := conns.dbs[key]; ok {
+		return db, nil
+	}
+
+	sourceConfig, ok := conns.configs[source.DatabaseName]
+	if !ok {
+		return nil, fmt.Errorf("no source database configured for dbName %s", source.DatabaseName)
+	}
+
+	var db *sqlx.DB
+	var err error
+	switch source.DatabaseType {
+	case database.TypeMySQL:
+		if sourceConfig.MySQL == nil {
+			return nil, fmt.Errorf("no mysql config provided for source database %s", source.DatabaseName)
+		}
+
+		db, err = sqlx.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:3306)/%s?parseTime=true", sourceConfig.MySQL.Username, sourceConfig.MySQL.Password, sourceConfig.MySQL.Hostname, sourceConfig.MySQL.Database))
+	case database.TypePostgres:
+		if sourceConfig.Postgres == nil {
+			return nil, fmt.Errorf("no postgres config provided for source database %s", source.DatabaseName)
+		}
+
+		usernamePassword := url.UserPassword(sourceConfig.Postgres.Username, sourceConfig.Postgres.Password).String()
+		db, err = sqlx.Open("postgres", fmt.Sprintf("postgres://%s@%s/%s?sslmode=%s", usernamePassword, sourceConfig.Postgres.Hostname, sourceConfig.Postgres.Database, sourceConfig.Postgres.SSLMode))
+	default:
+		return nil, fmt.Errorf("unsupported source database type %s", source.DatabaseType)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("Unable to establish connection to source database %s", source.DatabaseName))
+	}
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("Unable to ping source database %s", source.DatabaseName))
+	}
+
+	conns.dbs[key] = db
+	return db, nil
+}
+
+// readRows calls rowCallback for each row of the source table, in order of the
+// source's updatedAtColumn if set. If cursor is non-empty, only rows updated at
+// or after the cursor are read.
+func readRows(ctx context.Context, db *sqlx.DB, source objecttype.Source, cursor string, rowCallback func(SourceRow) error) error {
+	columns := make([]string, 0)
+	columns = append(columns, source.PrimaryKey...)
+	for _, foreignKey := range source.ForeignKeys {
+		columns = append(columns, foreignKey.Column)
+	}
+
+	if source.UpdatedAtColumn != "" {
+		columns = append(columns, source.UpdatedAtColumn)
+	}
+
+	quotedColumns := make([]string, 0)
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, quoteIdentifier(source.DatabaseType, column))
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(quotedColumns, ", "), quoteIdentifier(source.DatabaseType, source.Table))
+	replacements := []interface{}{}
+	if source.UpdatedAtColumn != "" {
+		updatedAtColumn := quoteIdentifier(source.DatabaseType, source.UpdatedAtColumn)
+		if cursor != "" {
+			query = fmt.Sprintf("%s WHERE %s >= ?", query, updatedAtColumn)
+			replacements = append(replacements, cursor)
+		}
+
+		query = fmt.Sprintf("%s ORDER BY %s ASC", query, updatedAtColumn)
+	}
+
+	rows, err := db.QueryxContext(ctx, db.Rebind(query), replacements...)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to read rows from source table %s", source.Table))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("Unable to scan row from source table %s", source.Table))
+		}
+
+		primaryKeyValues := make([]string, 0)
+		for i := range source.PrimaryKey {
+			value := valueToString(values[i])
+			if value == nil {
+				return fmt.Errorf("primary key column %s of source table %s is NULL", source.PrimaryKey[i], source.Table)
+			}
+
+			primaryKeyValues = append(primaryKeyValues, *value)
+		}
+
+		row := SourceRow{
+			ObjectId:    strings.Join(primaryKeyValues, primaryKeySeparator),
+			ForeignKeys: make(map[string]*string),
+		}
+		for i, foreignKey := range source.ForeignKeys {
+			row.ForeignKeys[foreignKey.Column] = valueToString(values[len(source.PrimaryKey)+i])
+		}
+
+		if source.UpdatedAtColumn != "" {
+			row.UpdatedAt = valueToString(values[len(values)-1])
+		}
+
+		err = rowCallback(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func quoteIdentifier(databaseType string, identifier string) string {
+	quote := `"`
+	if databaseType == database.TypeMySQL {
+		quote = "`"
+	}
+
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func valueToString(value interface{}) *string {
+	var str string
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		str = string(v)
+	case time.Time:
+		str = v.UTC().Format(cursorTimeFormat)
+	default:
+		str = fmt.Sprint(v)
+	}
+
+	return &str
+}
@@ -0,0 +1,28 @@
+package authz
+
+import (
+	"time"
+
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+const (
+	StatusSyncing = "syncing"
+	StatusSynced  = "synced"
+	StatusFailed  = "failed"
+)
+
+type ObjectSyncSpec struct {
+	ObjectType     string              `json:"objectType"`
+	Status         string              `json:"status"`
+	Cursor         database.NullString `json:"cursor"`
+	RowsSynced     int64               `json:"rowsSynced"`
+	LastError      database.NullString `json:"lastError"`
+	LastSyncedAt   database.NullTime   `json:"lastSyncedAt"`
+	LastFullSyncAt database.NullTime   `json:"lastFullSyncAt"`
+	CreatedAt      time.Time           `json:"createdAt"`
+}
+
+type RunObjectSyncSpec struct {
+	Full bool `json:"full"` // NOTE: if true, all rows are synced and objects no longer in the source are deleted
+}
@@ -0,0 +1,182 @@
+package authz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	AttributeSourceObject  = "object"
+	AttributeSourceSubject = "subject"
+	AttributeSourceContext = "context"
+
+	ComparatorEq  = "eq"
+	ComparatorNeq = "neq"
+	ComparatorLt  = "lt"
+	ComparatorLte = "lte"
+	ComparatorGt  = "gt"
+	ComparatorGte = "gte"
+)
+
+// AttributeCondition type represents a comparison between an attribute of the object,
+// an attribute of the subject, or a context value and either another such attribute or
+// a literal value (e.g. object.classification lte subject.clearance)
+type AttributeCondition struct {
+	Attribute  string      `json:"attribute" validate:"required,valid_attribute"`
+	Comparator string      `json:"comparator" validate:"required,oneof=eq neq lt lte gt gte"`
+	CompareTo  string      `json:"compareTo,omitempty" validate:"omitempty,valid_attribute"` // NOTE: if empty, Value is used instead
+	Value      interface{} `json:"value,omitempty"`
+}
+
+// Sources returns the attribute sources (object, subject, and/or context) referenced by the condition.
+func (condition AttributeCondition) Sources() []string {
+	sources := []string{strings.SplitN(condition.Attribute, ".", 2)[0]}
+	if condition.CompareTo != "" {
+		sources = append(sources, strings.SplitN(condition.CompareTo, ".", 2)[0])
+	}
+
+	return sources
+}
+
+// UsesObjectAttributes returns true if the condition references an attribute
+// of the object or the subject, as opposed to only context values.
+func (condition AttributeCondition) UsesObjectAttributes() bool {
+	for _, source := range condition.Sources() {
+		if source == AttributeSourceObject || source == AttributeSourceSubject {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Evaluate resolves the attributes referenced by the condition from the given
+// attributes (keyed by source) and compares them. A condition referencing a
+// missing attribute never matches.
+func (condition AttributeCondition) Evaluate(attributes map[string]map[string]interface{}) bool {
+	left, found := resolveAttribute(condition.Attribute, attributes)
+	if !found {
+		return false
+	}
+
+	right := condition.Value
+	if condition.CompareTo != "" {
+		right, found = resolveAttribute(condition.CompareTo, attributes)
+		if !found {
+			return false
+		}
+	}
+
+	return compare(left, right, condition.Comparator)
+}
+
+func (condition AttributeCondition) String() string {
+	if condition.CompareTo != "" {
+		return fmt.Sprintf("%s %s %s", condition.Attribute, condition.Comparator, condition.CompareTo)
+	}
+
+	return fmt.Sprintf("%s %s %v", condition.Attribute, condition.Comparator, condition.Value)
+}
+
+func resolveAttribute(attribute string, attributes map[string]map[string]interface{}) (interface{}, bool) {
+	path := strings.Split(attribute, ".")
+	var value interface{} = attributes[path[0]]
+	for _, key := range path[1:] {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case map[string]string:
+			value = v[key]
+		default:
+			return nil, false
+		}
+
+		if value == nil {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+func compare(left interface{}, right interface{}, comparator string) bool {
+	leftNum, leftIsNum := toNumber(left)
+	rightNum, rightIsNum := toNumber(right)
+	_, leftIsStr := left.(string)
+	_, rightIsStr := right.(string)
+
+	// Compare numerically if both sides are or can be converted to numbers
+	// (e.g. a context value compared to a numeric attribute or another context value)
+	if leftIsNum && rightIsNum {
+		switch comparator {
+		case ComparatorEq:
+			return leftNum == rightNum
+		case ComparatorNeq:
+			return leftNum != rightNum
+		case ComparatorLt:
+			return leftNum < rightNum
+		case ComparatorLte:
+			return leftNum <= rightNum
+		case ComparatorGt:
+			return leftNum > rightNum
+		case ComparatorGte:
+			return leftNum >= rightNum
+		default:
+			return false
+		}
+	}
+
+	if leftIsStr && rightIsStr {
+		leftStr := left.(string)
+		rightStr := right.(string)
+		switch comparator {
+		case ComparatorEq:
+			return leftStr == rightStr
+		case ComparatorNeq:
+			return leftStr != rightStr
+		case ComparatorLt:
+			return leftStr < rightStr
+		case ComparatorLte:
+			return leftStr <= rightStr
+		case ComparatorGt:
+			return leftStr > rightStr
+		case ComparatorGte:
+			return leftStr >= rightStr
+		default:
+			return false
+		}
+	}
+
+	// Values of any other type (e.g. booleans) can only be compared for equality
+	leftStr := fmt.Sprint(left)
+	rightStr := fmt.Sprint(right)
+	switch comparator {
+	case ComparatorEq:
+		return leftStr == rightStr
+	case ComparatorNeq:
+		return leftStr != rightStr
+	default:
+		return false
+	}
+}
+
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		num, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		return num, true
+	default:
+		return 0, false
+	}
+}
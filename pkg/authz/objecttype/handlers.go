@@ -79,6 +79,7 @@ func ListHandler(svc ObjectTypeService, w http.ResponseWriter, r *http.Request)
 		return err
 	}
 
+	middleware.SetCursorHeaders(w, listParams, objectTypeSpecs)
 	service.SendJSONResponse(w, objectTypeSpecs)
 	return nil
 }
@@ -3,6 +3,8 @@ package authz
 import (
 	"fmt"
 	"time"
+
+	"github.com/warrant-dev/warrant/pkg/middleware"
 )
 
 type ObjectTypeListParamParser struct{}
@@ -34,3 +36,12 @@ func (parser ObjectTypeListParamParser) ParseValue(val string, sortBy string) (i
 		return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
 	}
 }
+
+func (spec ObjectTypeSpec) ToCursor(sortBy string) middleware.Cursor {
+	switch sortBy {
+	case "createdAt":
+		return middleware.NewCursor(spec.Type, spec.CreatedAt)
+	default:
+		return middleware.NewCursor(spec.Type, nil)
+	}
+}
@@ -48,6 +48,11 @@ func (repo MySQLRepository) Create(ctx context.Context, model Model) (int64, err
 		return 0, err
 	}
 
+	err = repo.recordHistory(ctx, model.GetTypeId())
+	if err != nil {
+		return 0, err
+	}
+
 	return newObjectTypeId, nil
 }
 
@@ -103,6 +108,33 @@ func (repo MySQLRepository) GetByTypeId(ctx context.Context, typeId string) (Mod
 	return &objectType, nil
 }
 
+func (repo MySQLRepository) LockByTypeId(ctx context.Context, typeId string) error {
+	var id int64
+	err := repo.DB.GetContext(
+		ctx,
+		&id,
+		`
+			SELECT id
+			FROM objectType
+			WHERE
+				typeId = ? AND
+				deletedAt IS NULL
+			FOR UPDATE
+		`,
+		typeId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return service.NewRecordNotFoundError("ObjectType", typeId)
+		default:
+			return errors.Wrap(err, fmt.Sprintf("Unable to lock ObjectType with typeId %s in mysql", typeId))
+		}
+	}
+
+	return nil
+}
+
 func (repo MySQLRepository) List(ctx context.Context, listParams middleware.ListParams) ([]Model, error) {
 	models := make([]Model, 0)
 	objectTypes := make([]ObjectType, 0)
@@ -168,19 +200,20 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND typeId < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND typeId > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	if listParams.SortBy != "objectType" {
-		query = fmt.Sprintf("%s ORDER BY %s %s, typeId %s LIMIT ?", query, listParams.SortBy, listParams.SortOrder, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s, typeId %s LIMIT ?", query, listParams.SortBy, sortOrder, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY typeId %s LIMIT ?", query, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY typeId %s LIMIT ?", query, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -203,6 +236,8 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 		models = append(models, &objectTypes[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -224,6 +259,11 @@ func (repo MySQLRepository) UpdateByTypeId(ctx context.Context, typeId string, m
 		return errors.Wrap(err, fmt.Sprintf("Error updating object type %s", typeId))
 	}
 
+	err = repo.recordHistory(ctx, typeId)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -233,12 +273,11 @@ func (repo MySQLRepository) DeleteByTypeId(ctx context.Context, typeId string) e
 		`
 			UPDATE objectType
 			SET
-				deletedAt = ?
+				deletedAt = CURRENT_TIMESTAMP(6)
 			WHERE
 				typeId = ? AND
 				deletedAt IS NULL
 		`,
-		time.Now().UTC(),
 		typeId,
 	)
 	if err != nil {
@@ -250,5 +289,80 @@ func (repo MySQLRepository) DeleteByTypeId(ctx context.Context, typeId string) e
 		}
 	}
 
+	err = repo.recordHistory(ctx, typeId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo MySQLRepository) GetByTypeIdAsOf(ctx context.Context, typeId string, asOf time.Time) (Model, error) {
+	var objectType ObjectType
+	err := repo.DB.GetContext(
+		ctx,
+		&objectType,
+		`
+			SELECT id, typeId, definition, validFrom AS createdAt
+			FROM objectTypeHistory
+			WHERE
+				typeId = ? AND
+				validFrom <= ? AND
+				(validTo IS NULL OR validTo > ?)
+			ORDER BY validFrom DESC
+			LIMIT 1
+		`,
+		typeId,
+		asOf,
+		asOf,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return &objectType, service.NewRecordNotFoundError("ObjectType", typeId)
+		default:
+			return &objectType, errors.Wrap(err, fmt.Sprintf("Unable to get ObjectType with typeId %s as of %s from mysql", typeId, asOf))
+		}
+	}
+
+	return &objectType, nil
+}
+
+// recordHistory ends the open validity interval of the given object type and
+// starts a new one with its current definition unless it has been deleted. Both
+// happen at the object type's updatedAt, which is set by the statement that changed it.
+func (repo MySQLRepository) recordHistory(ctx context.Context, typeId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE objectTypeHistory
+			JOIN objectType ON objectType.typeId = objectTypeHistory.typeId
+			SET objectTypeHistory.validTo = objectType.updatedAt
+			WHERE
+				objectTypeHistory.typeId = ? AND
+				objectTypeHistory.validTo IS NULL
+		`,
+		typeId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to record history of object type %s in mysql", typeId))
+	}
+
+	_, err = repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO objectTypeHistory (typeId, definition, validFrom)
+			SELECT typeId, definition, updatedAt
+			FROM objectType
+			WHERE
+				typeId = ? AND
+				deletedAt IS NULL
+		`,
+		typeId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to record history of object type %s in mysql", typeId))
+	}
+
 	return nil
 }
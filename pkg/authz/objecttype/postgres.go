@@ -47,6 +47,11 @@ func (repo PostgresRepository) Create(ctx context.Context, model Model) (int64,
 		return 0, errors.Wrap(err, "Unable to create object type")
 	}
 
+	err = repo.recordHistory(ctx, model.GetTypeId())
+	if err != nil {
+		return 0, err
+	}
+
 	return newObjectTypeId, nil
 }
 
@@ -102,6 +107,33 @@ func (repo PostgresRepository) GetByTypeId(ctx context.Context, typeId string) (
 	return &objectType, nil
 }
 
+func (repo PostgresRepository) LockByTypeId(ctx context.Context, typeId string) error {
+	var id int64
+	err := repo.DB.GetContext(
+		ctx,
+		&id,
+		`
+			SELECT id
+			FROM object_type
+			WHERE
+				type_id = ? AND
+				deleted_at IS NULL
+			FOR UPDATE
+		`,
+		typeId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return service.NewRecordNotFoundError("ObjectType", typeId)
+		default:
+			return errors.Wrap(err, fmt.Sprintf("Unable to lock ObjectType with typeId %s in postgres", typeId))
+		}
+	}
+
+	return nil
+}
+
 func (repo PostgresRepository) List(ctx context.Context, listParams middleware.ListParams) ([]Model, error) {
 	models := make([]Model, 0)
 	objectTypes := make([]ObjectType, 0)
@@ -168,24 +200,25 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND type_id < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND type_id > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	nullSortClause := "NULLS LAST"
-	if listParams.SortOrder == middleware.SortOrderAsc {
+	if sortOrder == middleware.SortOrderAsc {
 		nullSortClause = "NULLS FIRST"
 	}
 
 	if listParams.SortBy != "objectType" {
-		query = fmt.Sprintf("%s ORDER BY %s %s %s, type_id %s LIMIT ?", query, sortBy, listParams.SortOrder, nullSortClause, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s %s, type_id %s LIMIT ?", query, sortBy, sortOrder, nullSortClause, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY type_id %s %s LIMIT ?", query, listParams.SortOrder, nullSortClause)
+		query = fmt.Sprintf("%s ORDER BY type_id %s %s LIMIT ?", query, sortOrder, nullSortClause)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -208,6 +241,8 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 		models = append(models, &objectTypes[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -229,6 +264,11 @@ func (repo PostgresRepository) UpdateByTypeId(ctx context.Context, typeId string
 		return errors.Wrap(err, fmt.Sprintf("Error updating object type %s", typeId))
 	}
 
+	err = repo.recordHistory(ctx, typeId)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -238,12 +278,11 @@ func (repo PostgresRepository) DeleteByTypeId(ctx context.Context, typeId string
 		`
 			UPDATE object_type
 			SET
-				deleted_at = ?
+				deleted_at = CURRENT_TIMESTAMP(6)
 			WHERE
 				type_id = ? AND
 				deleted_at IS NULL
 		`,
-		time.Now().UTC(),
 		typeId,
 	)
 	if err != nil {
@@ -255,5 +294,81 @@ func (repo PostgresRepository) DeleteByTypeId(ctx context.Context, typeId string
 		}
 	}
 
+	err = repo.recordHistory(ctx, typeId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (repo PostgresRepository) GetByTypeIdAsOf(ctx context.Context, typeId string, asOf time.Time) (Model, error) {
+	var objectType ObjectType
+	err := repo.DB.GetContext(
+		ctx,
+		&objectType,
+		`
+			SELECT id, type_id, definition, valid_from AS created_at
+			FROM object_type_history
+			WHERE
+				type_id = ? AND
+				valid_from <= ? AND
+				(valid_to IS NULL OR valid_to > ?)
+			ORDER BY valid_from DESC
+			LIMIT 1
+		`,
+		typeId,
+		asOf,
+		asOf,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return &objectType, service.NewRecordNotFoundError("ObjectType", typeId)
+		default:
+			return &objectType, errors.Wrap(err, fmt.Sprintf("Unable to get ObjectType with typeId %s as of %s from postgres", typeId, asOf))
+		}
+	}
+
+	return &objectType, nil
+}
+
+// recordHistory ends the open validity interval of the given object type and
+// starts a new one with its current definition unless it has been deleted. Both
+// happen at the object type's updated_at, which is set by the statement that changed it.
+func (repo PostgresRepository) recordHistory(ctx context.Context, typeId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE object_type_history
+			SET valid_to = object_type.updated_at
+			FROM object_type
+			WHERE
+				object_type.type_id = object_type_history.type_id AND
+				object_type_history.type_id = ? AND
+				object_type_history.valid_to IS NULL
+		`,
+		typeId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to record history of object type %s in postgres", typeId))
+	}
+
+	_, err = repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO object_type_history (type_id, definition, valid_from)
+			SELECT type_id, definition, updated_at
+			FROM object_type
+			WHERE
+				type_id = ? AND
+				deleted_at IS NULL
+		`,
+		typeId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to record history of object type %s in postgres", typeId))
+	}
+
 	return nil
 }
@@ -3,6 +3,7 @@ package authz
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/warrant-dev/warrant/pkg/database"
 	"github.com/warrant-dev/warrant/pkg/middleware"
@@ -12,6 +13,8 @@ type ObjectTypeRepository interface {
 	Create(ctx context.Context, objectType Model) (int64, error)
 	GetById(ctx context.Context, id int64) (Model, error)
 	GetByTypeId(ctx context.Context, typeId string) (Model, error)
+	GetByTypeIdAsOf(ctx context.Context, typeId string, asOf time.Time) (Model, error)
+	LockByTypeId(ctx context.Context, typeId string) error
 	List(ctx context.Context, listParams middleware.ListParams) ([]Model, error)
 	UpdateByTypeId(ctx context.Context, typeId string, objectType Model) error
 	DeleteByTypeId(ctx context.Context, typeId string) error
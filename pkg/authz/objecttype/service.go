@@ -2,7 +2,9 @@ package authz
 
 import (
 	"context"
+	"time"
 
+	"github.com/warrant-dev/warrant/pkg/change"
 	"github.com/warrant-dev/warrant/pkg/event"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
@@ -12,15 +14,17 @@ const ResourceTypeObjectType = "object-type"
 
 type ObjectTypeService struct {
 	service.BaseService
-	repo     ObjectTypeRepository
-	eventSvc event.EventService
+	repo      ObjectTypeRepository
+	eventSvc  event.EventService
+	changeSvc change.ChangeService
 }
 
-func NewService(env service.Env, repo ObjectTypeRepository, eventSvc event.EventService) ObjectTypeService {
+func NewService(env service.Env, repo ObjectTypeRepository, eventSvc event.EventService, changeSvc change.ChangeService) ObjectTypeService {
 	return ObjectTypeService{
 		BaseService: service.NewBaseService(env),
 		repo:        repo,
 		eventSvc:    eventSvc,
+		changeSvc:   changeSvc,
 	}
 }
 
@@ -35,32 +39,70 @@ func (svc ObjectTypeService) Create(ctx context.Context, objectTypeSpec ObjectTy
 		return nil, err
 	}
 
-	newObjectTypeId, err := svc.repo.Create(ctx, objectType)
+	var newObjectTypeSpec *ObjectTypeSpec
+	err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		newObjectTypeId, err := svc.repo.Create(txCtx, objectType)
+		if err != nil {
+			return err
+		}
+
+		newObjectType, err := svc.repo.GetById(txCtx, newObjectTypeId)
+		if err != nil {
+			return err
+		}
+
+		newObjectTypeSpec, err = newObjectType.ToObjectTypeSpec()
+		if err != nil {
+			return err
+		}
+
+		return svc.changeSvc.TrackChange(txCtx, change.ChangeTypeObjectTypeCreated, ResourceTypeObjectType, newObjectTypeSpec.Type, newObjectTypeSpec)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	newObjectType, err := svc.repo.GetById(ctx, newObjectTypeId)
+	svc.eventSvc.TrackResourceCreated(ctx, ResourceTypeObjectType, newObjectTypeSpec.Type, newObjectTypeSpec)
+	return newObjectTypeSpec, nil
+}
+
+func (svc ObjectTypeService) GetByTypeId(ctx context.Context, typeId string) (*ObjectTypeSpec, error) {
+	objectTypeRepository, err := NewRepository(svc.Env().DB())
 	if err != nil {
 		return nil, err
 	}
 
-	newObjectTypeSpec, err := newObjectType.ToObjectTypeSpec()
+	objectType, err := objectTypeRepository.GetByTypeId(ctx, typeId)
 	if err != nil {
 		return nil, err
 	}
 
-	svc.eventSvc.TrackResourceCreated(ctx, ResourceTypeObjectType, newObjectType.GetTypeId(), newObjectTypeSpec)
-	return newObjectTypeSpec, nil
+	return objectType.ToObjectTypeSpec()
 }
 
-func (svc ObjectTypeService) GetByTypeId(ctx context.Context, typeId string) (*ObjectTypeSpec, error) {
-	objectTypeRepository, err := NewRepository(svc.Env().DB())
+// ValidateObjectId returns an error if objectId is not a valid id for objects of
+// the given type. Object types that don't exist can't have path object ids.
+func (svc ObjectTypeService) ValidateObjectId(ctx context.Context, typeId string, objectId string) error {
+	objectTypeSpec, err := svc.GetByTypeId(ctx, typeId)
 	if err != nil {
-		return nil, err
+		if _, ok := err.(*service.RecordNotFoundError); !ok {
+			return err
+		}
+
+		objectTypeSpec = &ObjectTypeSpec{Type: typeId}
 	}
 
-	objectType, err := objectTypeRepository.GetByTypeId(ctx, typeId)
+	return objectTypeSpec.ValidateObjectId(objectId)
+}
+
+// GetByTypeIdAsOf returns the definition of the given object type as it was
+// at asOf, or its current definition if asOf is nil
+func (svc ObjectTypeService) GetByTypeIdAsOf(ctx context.Context, typeId string, asOf *time.Time) (*ObjectTypeSpec, error) {
+	if asOf == nil {
+		return svc.GetByTypeId(ctx, typeId)
+	}
+
+	objectType, err := svc.repo.GetByTypeIdAsOf(ctx, typeId, *asOf)
 	if err != nil {
 		return nil, err
 	}
@@ -68,6 +110,13 @@ func (svc ObjectTypeService) GetByTypeId(ctx context.Context, typeId string) (*O
 	return objectType.ToObjectTypeSpec()
 }
 
+// LockByTypeId locks the given object type until the end of the transaction
+// in ctx, serializing changes to warrants of the object type that must be
+// validated against each other (e.g. tenant parents, which can't form a cycle)
+func (svc ObjectTypeService) LockByTypeId(ctx context.Context, typeId string) error {
+	return svc.repo.LockByTypeId(ctx, typeId)
+}
+
 func (svc ObjectTypeService) List(ctx context.Context, listParams middleware.ListParams) ([]ObjectTypeSpec, error) {
 	objectTypeRepository, err := NewRepository(svc.Env().DB())
 	if err != nil {
@@ -108,13 +157,21 @@ func (svc ObjectTypeService) UpdateByTypeId(ctx context.Context, typeId string,
 		return nil, err
 	}
 
+	var updatedObjectTypeSpec *ObjectTypeSpec
 	currentObjectType.SetDefinition(updateTo.Definition)
-	err = objectTypeRepository.UpdateByTypeId(ctx, typeId, currentObjectType)
-	if err != nil {
-		return nil, err
-	}
+	err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		err := objectTypeRepository.UpdateByTypeId(txCtx, typeId, currentObjectType)
+		if err != nil {
+			return err
+		}
+
+		updatedObjectTypeSpec, err = svc.GetByTypeId(txCtx, typeId)
+		if err != nil {
+			return err
+		}
 
-	updatedObjectTypeSpec, err := svc.GetByTypeId(ctx, typeId)
+		return svc.changeSvc.TrackChange(txCtx, change.ChangeTypeObjectTypeUpdated, ResourceTypeObjectType, typeId, updatedObjectTypeSpec)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +186,14 @@ func (svc ObjectTypeService) DeleteByTypeId(ctx context.Context, typeId string)
 		return err
 	}
 
-	err = objectTypeRepository.DeleteByTypeId(ctx, typeId)
+	err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		err := objectTypeRepository.DeleteByTypeId(txCtx, typeId)
+		if err != nil {
+			return err
+		}
+
+		return svc.changeSvc.TrackChange(txCtx, change.ChangeTypeObjectTypeDeleted, ResourceTypeObjectType, typeId, nil)
+	})
 	if err != nil {
 		return err
 	}
@@ -2,6 +2,8 @@ package authz
 
 import (
 	"encoding/json"
+	"strings"
+	"time"
 
 	"github.com/warrant-dev/warrant/pkg/service"
 )
@@ -22,15 +24,38 @@ const (
 	RelationEditor  = "editor"
 	RelationViewer  = "viewer"
 
-	InheritIfAllOf  = "allOf"
-	InheritIfAnyOf  = "anyOf"
-	InheritIfNoneOf = "noneOf"
+	InheritIfAllOf     = "allOf"
+	InheritIfAnyOf     = "anyOf"
+	InheritIfNoneOf    = "noneOf"
+	InheritIfButNot    = "butNot"
+	InheritIfAtLeast   = "atLeast"
+	InheritIfCondition = "condition"
+
+	ObjectIdWildcard      = "*"
+	ObjectIdPathSeparator = "/"
 )
 
+// IsBuiltIn returns true if objectType is one of the object types created by
+// default, which are required by the user, tenant, role, permission, feature
+// and pricing tier endpoints
+func IsBuiltIn(objectType string) bool {
+	switch objectType {
+	case ObjectTypeFeature, ObjectTypePermission, ObjectTypePricingTier, ObjectTypeRole, ObjectTypeTenant, ObjectTypeUser:
+		return true
+	default:
+		return false
+	}
+}
+
 type ObjectTypeSpec struct {
-	Type      string                  `json:"type" validate:"required,valid_object_type"`
-	Source    *Source                 `json:"source,omitempty"`
-	Relations map[string]RelationRule `json:"relations" validate:"required,min=1,dive"` // NOTE: map key = name of relation
+	Type         string                  `json:"type" validate:"required,valid_object_type"`
+	Hierarchical bool                    `json:"hierarchical,omitempty"` // NOTE: if true, object ids are paths (e.g. bucket/folder/file)
+	Source       *Source                 `json:"source,omitempty"`
+	Relations    map[string]RelationRule `json:"relations" validate:"required,min=1,dive"` // NOTE: map key = name of relation
+
+	// NOTE: CreatedAt is required here for paginating object types.
+	// However, it is not part of the definition and we don't return it to the client.
+	CreatedAt time.Time `json:"-"`
 }
 
 func (spec ObjectTypeSpec) ToObjectType() (*ObjectType, error) {
@@ -45,27 +70,83 @@ func (spec ObjectTypeSpec) ToObjectType() (*ObjectType, error) {
 	}, nil
 }
 
+// ValidateObjectId returns an error if the given object id is not valid for this object type.
+// Only hierarchical object types can have path object ids, and a wildcard is
+// only allowed as the last segment of a path (e.g. bucket/folder/*).
+func (spec ObjectTypeSpec) ValidateObjectId(objectId string) error {
+	if !strings.Contains(objectId, ObjectIdPathSeparator) {
+		return nil
+	}
+
+	if !spec.Hierarchical {
+		return service.NewInvalidParameterError("objectId", "can only contain '/' if the object type is hierarchical")
+	}
+
+	segments := strings.Split(objectId, ObjectIdPathSeparator)
+	for i, segment := range segments {
+		if segment == "" {
+			return service.NewInvalidParameterError("objectId", "must not contain empty path segments")
+		}
+
+		if strings.Contains(segment, ObjectIdWildcard) && (segment != ObjectIdWildcard || i != len(segments)-1) {
+			return service.NewInvalidParameterError("objectId", "can only contain '*' as the last path segment")
+		}
+	}
+
+	return nil
+}
+
+// MatchingObjectIds returns the object ids of all warrants that apply to the
+// given object id. This includes the object id itself, the '*' wildcard and,
+// for hierarchical object types, a prefix wildcard for each ancestor path
+// (e.g. bucket/* and bucket/folder/* for bucket/folder/file).
+func (spec ObjectTypeSpec) MatchingObjectIds(objectId string) []string {
+	objectIds := []string{objectId, ObjectIdWildcard}
+	objectIds = append(objectIds, spec.PrefixWildcardObjectIds(objectId)...)
+	return objectIds
+}
+
+// PrefixWildcardObjectIds returns a prefix wildcard for each ancestor path of
+// the given object id if the object type is hierarchical.
+func (spec ObjectTypeSpec) PrefixWildcardObjectIds(objectId string) []string {
+	prefixWildcards := make([]string, 0)
+	if !spec.Hierarchical {
+		return prefixWildcards
+	}
+
+	segments := strings.Split(objectId, ObjectIdPathSeparator)
+	for i := 1; i < len(segments); i++ {
+		prefix := strings.Join(segments[:i], ObjectIdPathSeparator)
+		prefixWildcards = append(prefixWildcards, prefix+ObjectIdPathSeparator+ObjectIdWildcard)
+	}
+
+	return prefixWildcards
+}
+
 type Source struct {
-	DatabaseType string           `json:"dbType" validate:"required"`
-	DatabaseName string           `json:"dbName" validate:"required"`
-	Table        string           `json:"table" validate:"required"`
-	PrimaryKey   []string         `json:"primaryKey" validate:"min=1"`
-	ForeignKeys  []ForeignKeySpec `json:"foreignKeys,omitempty"`
+	DatabaseType    string           `json:"dbType" validate:"required,oneof=mysql postgres"`
+	DatabaseName    string           `json:"dbName" validate:"required"`
+	Table           string           `json:"table" validate:"required"`
+	PrimaryKey      []string         `json:"primaryKey" validate:"min=1"`
+	ForeignKeys     []ForeignKeySpec `json:"foreignKeys,omitempty"`
+	UpdatedAtColumn string           `json:"updatedAtColumn,omitempty"` // NOTE: if set, rows are synced incrementally by this column
 }
 
 type ForeignKeySpec struct {
 	Column   string `json:"column" validate:"required"`
 	Relation string `json:"relation" validate:"required,valid_relation"`
 	Type     string `json:"type" validate:"required,valid_object_type"`
-	Subject  string `json:"subject" validate:"required"`
+	Subject  string `json:"subject" validate:"required"` // NOTE: type of the subject of the warrant, either Type or the source's own object type
 }
 
 // RelationRule type represents the rule or set of rules that imply a particular relation if met
 type RelationRule struct {
-	InheritIf    string         `json:"inheritIf,omitempty" validate:"required_with=Rules OfType WithRelation,valid_inheritif"`
-	Rules        []RelationRule `json:"rules,omitempty" validate:"required_if_oneof=InheritIf anyOf allOf noneOf,omitempty,min=1,dive"` // Required if InheritIf is "anyOf", "allOf", or "noneOf", empty otherwise
-	OfType       string         `json:"ofType,omitempty" validate:"required_with=WithRelation,valid_relation"`
-	WithRelation string         `json:"withRelation,omitempty" validate:"required_with=OfType,valid_relation"`
+	InheritIf    string              `json:"inheritIf,omitempty" validate:"required_with=Rules OfType WithRelation Condition,valid_inheritif"`
+	Rules        []RelationRule      `json:"rules,omitempty" validate:"required_if_oneof=InheritIf anyOf allOf noneOf butNot atLeast,valid_rule_count=InheritIf Count,omitempty,min=1,dive"` // Required if InheritIf is "anyOf", "allOf", "noneOf", "butNot", or "atLeast", empty otherwise
+	Count        int                 `json:"count,omitempty" validate:"required_if_oneof=InheritIf atLeast,excluded_unless_oneof=InheritIf atLeast,min=0"`                                   // Required if InheritIf is "atLeast", empty otherwise
+	OfType       string              `json:"ofType,omitempty" validate:"required_with=WithRelation,valid_relation"`
+	WithRelation string              `json:"withRelation,omitempty" validate:"required_with=OfType,valid_relation"`
+	Condition    *AttributeCondition `json:"condition,omitempty" validate:"required_if=InheritIf condition"` // Required if InheritIf is "condition", empty otherwise
 }
 
 var UserObjectTypeSpec = ObjectTypeSpec{
@@ -52,6 +52,41 @@ func (svc PermissionService) Routes() []service.Route {
 			Method:  "DELETE",
 			Handler: service.NewRouteHandler(svc, DeleteHandler),
 		},
+
+		// role permissions
+		{
+			Pattern: "/v1/roles/{roleId}/permissions",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListForRoleHandler),
+		},
+		{
+			Pattern: "/v1/roles/{roleId}/permissions",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AssignToRoleHandler),
+		},
+		{
+			Pattern: "/v1/roles/{roleId}/permissions/{permissionId}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveFromRoleHandler),
+		},
+
+		// user permissions
+		{
+			Pattern:           "/v1/users/{userId}/permissions",
+			Method:            "GET",
+			Handler:           service.NewRouteHandler(svc, ListEffectiveForUserHandler),
+			EnableSessionAuth: true,
+		},
+		{
+			Pattern: "/v1/users/{userId}/permissions",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AssignToUserHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/permissions/{permissionId}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveFromUserHandler),
+		},
 	}
 }
 
@@ -94,6 +129,7 @@ func ListHandler(svc PermissionService, w http.ResponseWriter, r *http.Request)
 		return err
 	}
 
+	middleware.SetCursorHeaders(w, listParams, permissions)
 	service.SendJSONResponse(w, permissions)
 	return nil
 }
@@ -133,3 +169,122 @@ func DeleteHandler(svc PermissionService, w http.ResponseWriter, r *http.Request
 
 	return nil
 }
+
+func ListForRoleHandler(svc PermissionService, w http.ResponseWriter, r *http.Request) error {
+	roleId, err := url.QueryUnescape(mux.Vars(r)["roleId"])
+	if err != nil {
+		return service.NewInvalidParameterError("roleId", "")
+	}
+
+	assignedPermissions, err := svc.ListForRole(r.Context(), roleId, r.URL.Query().Get("tenantId"))
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedPermissions)
+	return nil
+}
+
+func AssignToRoleHandler(svc PermissionService, w http.ResponseWriter, r *http.Request) error {
+	var assignSpec AssignPermissionSpec
+	err := service.ParseJSONBody(r.Body, &assignSpec)
+	if err != nil {
+		return err
+	}
+
+	roleId, err := url.QueryUnescape(mux.Vars(r)["roleId"])
+	if err != nil {
+		return service.NewInvalidParameterError("roleId", "")
+	}
+
+	assignedPermission, err := svc.AssignToRole(r.Context(), roleId, assignSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedPermission)
+	return nil
+}
+
+func RemoveFromRoleHandler(svc PermissionService, w http.ResponseWriter, r *http.Request) error {
+	roleId, err := url.QueryUnescape(mux.Vars(r)["roleId"])
+	if err != nil {
+		return service.NewInvalidParameterError("roleId", "")
+	}
+
+	permissionId, err := url.QueryUnescape(mux.Vars(r)["permissionId"])
+	if err != nil {
+		return service.NewInvalidParameterError("permissionId", "")
+	}
+
+	err = svc.RemoveFromRole(r.Context(), roleId, permissionId, r.URL.Query().Get("tenantId"))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func ListEffectiveForUserHandler(svc PermissionService, w http.ResponseWriter, r *http.Request) error {
+	userId, err := url.QueryUnescape(mux.Vars(r)["userId"])
+	if err != nil {
+		return service.NewInvalidParameterError("userId", "")
+	}
+
+	tenantId, err := service.ScopeToSession(service.GetAuthInfoFromRequestContext(r.Context()), userId, r.URL.Query().Get("tenantId"))
+	if err != nil {
+		return err
+	}
+
+	effectivePermissions, err := svc.ListEffectiveForUser(r.Context(), userId, tenantId)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, effectivePermissions)
+	return nil
+}
+
+func AssignToUserHandler(svc PermissionService, w http.ResponseWriter, r *http.Request) error {
+	var assignSpec AssignPermissionSpec
+	err := service.ParseJSONBody(r.Body, &assignSpec)
+	if err != nil {
+		return err
+	}
+
+	userId, err := url.QueryUnescape(mux.Vars(r)["userId"])
+	if err != nil {
+		return service.NewInvalidParameterError("userId", "")
+	}
+
+	assignedPermission, err := svc.AssignToUser(r.Context(), userId, assignSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedPermission)
+	return nil
+}
+
+func RemoveFromUserHandler(svc PermissionService, w http.ResponseWriter, r *http.Request) error {
+	userId, err := url.QueryUnescape(mux.Vars(r)["userId"])
+	if err != nil {
+		return service.NewInvalidParameterError("userId", "")
+	}
+
+	permissionId, err := url.QueryUnescape(mux.Vars(r)["permissionId"])
+	if err != nil {
+		return service.NewInvalidParameterError("permissionId", "")
+	}
+
+	err = svc.RemoveFromUser(r.Context(), userId, permissionId, r.URL.Query().Get("tenantId"))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
@@ -3,6 +3,8 @@ package authz
 import (
 	"fmt"
 	"time"
+
+	"github.com/warrant-dev/warrant/pkg/middleware"
 )
 
 type PermissionListParamParser struct{}
@@ -40,3 +42,18 @@ func (parser PermissionListParamParser) ParseValue(val string, sortBy string) (i
 		return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
 	}
 }
+
+func (spec PermissionSpec) ToCursor(sortBy string) middleware.Cursor {
+	switch sortBy {
+	case "createdAt":
+		return middleware.NewCursor(spec.PermissionId, spec.CreatedAt)
+	case "name":
+		if !spec.Name.Valid {
+			return middleware.NewCursor(spec.PermissionId, middleware.NullValue{})
+		}
+
+		return middleware.NewCursor(spec.PermissionId, spec.Name.String)
+	default:
+		return middleware.NewCursor(spec.PermissionId, nil)
+	}
+}
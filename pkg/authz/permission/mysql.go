@@ -131,21 +131,9 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 
 	if listParams.AfterId != "" {
 		if listParams.AfterValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s > ? OR (permissionId > ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s < ? OR (permissionId < ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(listParams.SortBy, "permissionId", listParams.SortOrder, listParams.AfterValue, listParams.AfterId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND permissionId > ?", query)
@@ -159,37 +147,26 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 
 	if listParams.BeforeId != "" {
 		if listParams.BeforeValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s < ? OR (permissionId < ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s > ? OR (permissionId > ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(listParams.SortBy, "permissionId", listParams.SortOrder.Reverse(), listParams.BeforeValue, listParams.BeforeId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND permissionId < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND permissionId > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	if listParams.SortBy != "permissionId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s, permissionId %s LIMIT ?", query, listParams.SortBy, listParams.SortOrder, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s, permissionId %s LIMIT ?", query, listParams.SortBy, sortOrder, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY permissionId %s LIMIT ?", query, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY permissionId %s LIMIT ?", query, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -212,6 +189,8 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 		models = append(models, &permissions[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -2,9 +2,17 @@ package authz
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
+	check "github.com/warrant-dev/warrant/pkg/authz/check"
 	object "github.com/warrant-dev/warrant/pkg/authz/object"
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	role "github.com/warrant-dev/warrant/pkg/authz/role"
+	tenant "github.com/warrant-dev/warrant/pkg/authz/tenant"
+	user "github.com/warrant-dev/warrant/pkg/authz/user"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	wntContext "github.com/warrant-dev/warrant/pkg/context"
 	"github.com/warrant-dev/warrant/pkg/event"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
@@ -14,17 +22,27 @@ const ResourceTypePermission = "permission"
 
 type PermissionService struct {
 	service.BaseService
-	repo      PermissionRepository
-	eventSvc  event.EventService
-	objectSvc object.ObjectService
+	repo       PermissionRepository
+	eventSvc   event.EventService
+	objectSvc  object.ObjectService
+	roleSvc    role.RoleService
+	userSvc    user.UserService
+	tenantSvc  tenant.TenantService
+	warrantSvc warrant.WarrantService
+	checkSvc   check.CheckService
 }
 
-func NewService(env service.Env, repo PermissionRepository, eventSvc event.EventService, objectSvc object.ObjectService) PermissionService {
+func NewService(env service.Env, repo PermissionRepository, eventSvc event.EventService, objectSvc object.ObjectService, roleSvc role.RoleService, userSvc user.UserService, tenantSvc tenant.TenantService, warrantSvc warrant.WarrantService, checkSvc check.CheckService) PermissionService {
 	return PermissionService{
 		BaseService: service.NewBaseService(env),
 		repo:        repo,
 		eventSvc:    eventSvc,
 		objectSvc:   objectSvc,
+		roleSvc:     roleSvc,
+		userSvc:     userSvc,
+		tenantSvc:   tenantSvc,
+		warrantSvc:  warrantSvc,
+		checkSvc:    checkSvc,
 	}
 }
 
@@ -146,3 +164,357 @@ func (svc PermissionService) DeleteByPermissionId(ctx context.Context, permissio
 
 	return err
 }
+
+// ListForRole returns the permissions assigned to a role, sorted by
+// permissionId. If tenantId is set, only the permissions that apply within
+// the tenant are returned.
+func (svc PermissionService) ListForRole(ctx context.Context, roleId string, tenantId string) ([]AssignedPermissionSpec, error) {
+	_, err := svc.roleSvc.GetByRoleId(ctx, roleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.listAssigned(ctx, warrant.SubjectSpec{ObjectType: objecttype.ObjectTypeRole, ObjectId: roleId}, tenantId)
+}
+
+// AssignToRole grants a permission to the members of a role, only within the
+// given tenant if the assignment has a tenantId
+func (svc PermissionService) AssignToRole(ctx context.Context, roleId string, assignSpec AssignPermissionSpec) (*AssignedPermissionSpec, error) {
+	_, err := svc.roleSvc.GetByRoleId(ctx, roleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.assign(ctx, warrant.SubjectSpec{ObjectType: objecttype.ObjectTypeRole, ObjectId: roleId}, assignSpec)
+}
+
+// RemoveFromRole removes a permission's assignment to a role, either within
+// the given tenant or, if tenantId is empty, the assignment that applies in all tenants
+func (svc PermissionService) RemoveFromRole(ctx context.Context, roleId string, permissionId string, tenantId string) error {
+	return svc.warrantSvc.Delete(ctx, permissionWarrantSpec(permissionId, warrant.SubjectSpec{ObjectType: objecttype.ObjectTypeRole, ObjectId: roleId}, tenantId))
+}
+
+// AssignToUser grants a permission directly to a user, only within the given
+// tenant if the assignment has a tenantId
+func (svc PermissionService) AssignToUser(ctx context.Context, userId string, assignSpec AssignPermissionSpec) (*AssignedPermissionSpec, error) {
+	_, err := svc.userSvc.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.assign(ctx, warrant.SubjectSpec{ObjectType: objecttype.ObjectTypeUser, ObjectId: userId}, assignSpec)
+}
+
+// RemoveFromUser removes a permission's direct assignment to a user, either
+// within the given tenant or, if tenantId is empty, the assignment that applies in all tenants
+func (svc PermissionService) RemoveFromUser(ctx context.Context, userId string, permissionId string, tenantId string) error {
+	return svc.warrantSvc.Delete(ctx, permissionWarrantSpec(permissionId, warrant.SubjectSpec{ObjectType: objecttype.ObjectTypeUser, ObjectId: userId}, tenantId))
+}
+
+// ListEffectiveForUser returns the permissions a user has within the given
+// tenant (or outside of any tenant if tenantId is empty), sorted by
+// permissionId. Only the permissions granted to subjects reachable from the
+// user (its roles, direct grants, and the objects those lead to) are
+// candidates, and each one is confirmed by the CheckService through the
+// rules of the permission and role object types.
+func (svc PermissionService) ListEffectiveForUser(ctx context.Context, userId string, tenantId string) ([]EffectivePermissionSpec, error) {
+	_, err := svc.userSvc.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if tenantId != "" {
+		_, err = svc.tenantSvc.GetByTenantId(ctx, tenantId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// NOTE: listing permissions doesn't grant or deny any access, so the checks aren't tracked as access events
+	checkCtx := event.WithoutTracking(ctx)
+	tenantContext := check.TenantContext(tenantId)
+	grants, err := svc.reachableGrants(ctx, userId, tenantContext)
+	if err != nil {
+		return nil, err
+	}
+
+	grantsByPermissionId := make(map[string][]*warrant.WarrantSpec)
+	for _, grant := range grants {
+		grantsByPermissionId[grant.ObjectId] = append(grantsByPermissionId[grant.ObjectId], grant)
+	}
+
+	// NOTE: a grant of the wildcard permission makes every permission a candidate
+	candidatePermissionSpecs := make([]PermissionSpec, 0)
+	if _, ok := grantsByPermissionId[objecttype.ObjectIdWildcard]; ok {
+		candidatePermissionSpecs, err = svc.listAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for permissionId := range grantsByPermissionId {
+			// NOTE: warrants don't require their object to exist, so grants of permissions that were never created are skipped
+			permissionSpec, err := svc.GetByPermissionId(ctx, permissionId)
+			if err != nil {
+				if _, ok := err.(*service.RecordNotFoundError); ok {
+					continue
+				}
+
+				return nil, err
+			}
+
+			candidatePermissionSpecs = append(candidatePermissionSpecs, *permissionSpec)
+		}
+	}
+
+	// NOTE: the user's membership in each source is checked once
+	userSubject := &warrant.SubjectSpec{ObjectType: objecttype.ObjectTypeUser, ObjectId: userId}
+	isMember := make(map[string]bool)
+	checkMember := func(objectType string, objectId string, relation string) (bool, error) {
+		warrantSpec := warrant.WarrantSpec{
+			ObjectType: objectType,
+			ObjectId:   objectId,
+			Relation:   relation,
+			Subject:    userSubject,
+			Context:    tenantContext,
+		}
+		if match, checked := isMember[warrantSpec.String()]; checked {
+			return match, nil
+		}
+
+		match, _, err := svc.checkSvc.Check(checkCtx, nil, check.CheckSpec{WarrantSpec: warrantSpec})
+		if err != nil {
+			return false, err
+		}
+
+		isMember[warrantSpec.String()] = match
+		return match, nil
+	}
+
+	effectivePermissionSpecs := make([]EffectivePermissionSpec, 0)
+	for _, permissionSpec := range candidatePermissionSpecs {
+		match, err := checkMember(objecttype.ObjectTypePermission, permissionSpec.PermissionId, objecttype.RelationMember)
+		if err != nil {
+			return nil, err
+		}
+
+		if !match {
+			continue
+		}
+
+		sources := make([]warrant.SubjectSpec, 0)
+		for _, grant := range append(grantsByPermissionId[permissionSpec.PermissionId], grantsByPermissionId[objecttype.ObjectIdWildcard]...) {
+			source := *grant.Subject
+			var isSource bool
+			switch {
+			case source.Relation != "":
+				isSource, err = checkMember(source.ObjectType, source.ObjectId, source.Relation)
+			case source.ObjectType == objecttype.ObjectTypeUser:
+				isSource = source.ObjectId == userId || source.ObjectId == objecttype.ObjectIdWildcard
+			case source.ObjectType == objecttype.ObjectTypeRole || source.ObjectType == objecttype.ObjectTypePermission:
+				isSource, err = checkMember(source.ObjectType, source.ObjectId, objecttype.RelationMember)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if isSource {
+				sources = append(sources, source)
+			}
+		}
+
+		// NOTE: grants are found in the order they're reached, so sources are sorted to keep the response stable
+		sort.Slice(sources, func(i, j int) bool {
+			return sources[i].String() < sources[j].String()
+		})
+		effectivePermissionSpecs = append(effectivePermissionSpecs, EffectivePermissionSpec{
+			PermissionId: permissionSpec.PermissionId,
+			Name:         permissionSpec.Name,
+			Description:  permissionSpec.Description,
+			Sources:      sources,
+		})
+	}
+
+	sort.Slice(effectivePermissionSpecs, func(i, j int) bool {
+		return effectivePermissionSpecs[i].PermissionId < effectivePermissionSpecs[j].PermissionId
+	})
+	return effectivePermissionSpecs, nil
+}
+
+// reachableGrants returns the permission member warrants whose subjects are
+// reachable from the user, walking from each subject to the objects of the
+// warrants it's the subject of. Only warrants without a context or with the
+// given tenant context are followed. The walk may reach subjects the user
+// isn't actually a member of, so its results are candidates to be checked.
+func (svc PermissionService) reachableGrants(ctx context.Context, userId string, tenantContext wntContext.ContextSetSpec) ([]*warrant.WarrantSpec, error) {
+	grants := make([]*warrant.WarrantSpec, 0)
+	visited := map[string]bool{fmt.Sprintf("%s:%s", objecttype.ObjectTypeUser, userId): true}
+	frontier := map[string][]string{objecttype.ObjectTypeUser: {userId}}
+	for len(frontier) > 0 {
+		next := make(map[string][]string)
+		for objectType, objectIds := range frontier {
+			var warrantSpecs []*warrant.WarrantSpec
+			for start := 0; start < len(objectIds); start += warrant.ListAllBatchSize {
+				end := start + warrant.ListAllBatchSize
+				if end > len(objectIds) {
+					end = len(objectIds)
+				}
+
+				// NOTE: warrants on a subject wildcard apply to every object of its type
+				filterOptions := &warrant.FilterOptions{
+					Subject:    &warrant.SubjectSpec{ObjectType: objectType},
+					SubjectIds: append([]string{objecttype.ObjectIdWildcard}, objectIds[start:end]...),
+				}
+				if visited[fmt.Sprintf("%s:%s", objectType, objecttype.ObjectIdWildcard)] {
+					filterOptions.SubjectIds = nil
+				}
+
+				batch, err := svc.warrantSvc.ListAll(ctx, filterOptions)
+				if err != nil {
+					return nil, err
+				}
+
+				warrantSpecs = append(warrantSpecs, batch...)
+			}
+
+			for _, warrantSpec := range warrantSpecs {
+				if len(warrantSpec.Context) != 0 && warrantSpec.Context.ToHash() != tenantContext.ToHash() {
+					continue
+				}
+
+				if warrantSpec.ObjectType == objecttype.ObjectTypePermission && warrantSpec.Relation == objecttype.RelationMember {
+					grants = append(grants, warrantSpec)
+				}
+
+				key := fmt.Sprintf("%s:%s", warrantSpec.ObjectType, warrantSpec.ObjectId)
+				if !visited[key] {
+					visited[key] = true
+					next[warrantSpec.ObjectType] = append(next[warrantSpec.ObjectType], warrantSpec.ObjectId)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	// NOTE: a warrant may be listed more than once if the subject wildcard of its type was reached
+	uniqueGrants := make([]*warrant.WarrantSpec, 0, len(grants))
+	seen := make(map[string]bool)
+	for _, grant := range grants {
+		if !seen[grant.String()] {
+			seen[grant.String()] = true
+			uniqueGrants = append(uniqueGrants, grant)
+		}
+	}
+
+	return uniqueGrants, nil
+}
+
+// listAll returns every permission, listed in batches of warrant.ListAllBatchSize
+func (svc PermissionService) listAll(ctx context.Context) ([]PermissionSpec, error) {
+	permissionSpecs := make([]PermissionSpec, 0)
+	listParams := middleware.ListParams{
+		Page:      1,
+		Limit:     warrant.ListAllBatchSize,
+		SortBy:    PermissionListParamParser{}.GetDefaultSortBy(),
+		SortOrder: middleware.SortOrderAsc,
+	}
+	for {
+		batch, err := svc.List(ctx, listParams)
+		if err != nil {
+			return nil, err
+		}
+
+		permissionSpecs = append(permissionSpecs, batch...)
+		if len(batch) < listParams.Limit {
+			return permissionSpecs, nil
+		}
+
+		cursor := batch[len(batch)-1].ToCursor(listParams.SortBy)
+		listParams.AfterId = cursor.ID
+		listParams.AfterValue = cursor.Value
+	}
+}
+
+func (svc PermissionService) listAssigned(ctx context.Context, subject warrant.SubjectSpec, tenantId string) ([]AssignedPermissionSpec, error) {
+	warrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+		ObjectType: objecttype.ObjectTypePermission,
+		Relation:   objecttype.RelationMember,
+		Subject:    &subject,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	assignedPermissionSpecs := make([]AssignedPermissionSpec, 0)
+	for _, warrantSpec := range warrantSpecs {
+		assignmentTenantId, isAssignment := role.AssignmentTenantId(warrantSpec)
+		if !isAssignment || (tenantId != "" && assignmentTenantId != "" && assignmentTenantId != tenantId) {
+			continue
+		}
+
+		// NOTE: warrants don't require their object to exist, so assignments of permissions that were never created are skipped
+		permissionSpec, err := svc.GetByPermissionId(ctx, warrantSpec.ObjectId)
+		if err != nil {
+			if _, ok := err.(*service.RecordNotFoundError); ok {
+				continue
+			}
+
+			return nil, err
+		}
+
+		assignedPermissionSpecs = append(assignedPermissionSpecs, AssignedPermissionSpec{
+			PermissionId: permissionSpec.PermissionId,
+			Name:         permissionSpec.Name,
+			Description:  permissionSpec.Description,
+			TenantId:     assignmentTenantId,
+		})
+	}
+
+	sort.Slice(assignedPermissionSpecs, func(i, j int) bool {
+		if assignedPermissionSpecs[i].PermissionId != assignedPermissionSpecs[j].PermissionId {
+			return assignedPermissionSpecs[i].PermissionId < assignedPermissionSpecs[j].PermissionId
+		}
+
+		return assignedPermissionSpecs[i].TenantId < assignedPermissionSpecs[j].TenantId
+	})
+	return assignedPermissionSpecs, nil
+}
+
+func (svc PermissionService) assign(ctx context.Context, subject warrant.SubjectSpec, assignSpec AssignPermissionSpec) (*AssignedPermissionSpec, error) {
+	permissionSpec, err := svc.GetByPermissionId(ctx, assignSpec.PermissionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignSpec.TenantId != "" {
+		_, err = svc.tenantSvc.GetByTenantId(ctx, assignSpec.TenantId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = svc.warrantSvc.Create(ctx, permissionWarrantSpec(assignSpec.PermissionId, subject, assignSpec.TenantId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AssignedPermissionSpec{
+		PermissionId: permissionSpec.PermissionId,
+		Name:         permissionSpec.Name,
+		Description:  permissionSpec.Description,
+		TenantId:     assignSpec.TenantId,
+	}, nil
+}
+
+// permissionWarrantSpec returns the warrant that assigns a permission to a
+// subject. Members of a role subject inherit the permission through the
+// permission object type's rules.
+func permissionWarrantSpec(permissionId string, subject warrant.SubjectSpec, tenantId string) warrant.WarrantSpec {
+	return warrant.WarrantSpec{
+		ObjectType: objecttype.ObjectTypePermission,
+		ObjectId:   permissionId,
+		Relation:   objecttype.RelationMember,
+		Subject:    &subject,
+		Context:    check.TenantContext(tenantId),
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	object "github.com/warrant-dev/warrant/pkg/authz/object"
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
 	context "github.com/warrant-dev/warrant/pkg/context"
 	"github.com/warrant-dev/warrant/pkg/database"
 )
@@ -37,3 +38,29 @@ type UpdatePermissionSpec struct {
 	Name        database.NullString `json:"name"`
 	Description database.NullString `json:"description"`
 }
+
+// AssignPermissionSpec type for assigning a permission to a role or user,
+// optionally only within a tenant
+type AssignPermissionSpec struct {
+	PermissionId string `json:"permissionId" validate:"required"`
+	TenantId     string `json:"tenantId,omitempty"`
+}
+
+// AssignedPermissionSpec type for a permission assigned to a role or user.
+// The permission only applies within the tenant if TenantId is set.
+type AssignedPermissionSpec struct {
+	PermissionId string              `json:"permissionId"`
+	Name         database.NullString `json:"name"`
+	Description  database.NullString `json:"description"`
+	TenantId     string              `json:"tenantId,omitempty"`
+}
+
+// EffectivePermissionSpec type for a permission a user has, either directly
+// or through the roles and permissions it inherits from. Sources are the
+// subjects the permission is assigned to that the user is, or is a member of.
+type EffectivePermissionSpec struct {
+	PermissionId string                `json:"permissionId"`
+	Name         database.NullString   `json:"name"`
+	Description  database.NullString   `json:"description"`
+	Sources      []warrant.SubjectSpec `json:"sources"`
+}
@@ -5,6 +5,8 @@ import (
 	"net/url"
 
 	"github.com/gorilla/mux"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
 )
@@ -52,6 +54,48 @@ func (svc PricingTierService) Routes() []service.Route {
 			Method:  "DELETE",
 			Handler: service.NewRouteHandler(svc, DeleteHandler),
 		},
+
+		// assignments
+		{
+			Pattern: "/v1/tenants/{tenantId}/pricing-tiers",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListForSubjectHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/pricing-tiers",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AssignToSubjectHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/pricing-tiers",
+			Method:  "PUT",
+			Handler: service.NewRouteHandler(svc, SetForSubjectHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/pricing-tiers/{pricingTierId}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveFromSubjectHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/pricing-tiers",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListForSubjectHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/pricing-tiers",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AssignToSubjectHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/pricing-tiers",
+			Method:  "PUT",
+			Handler: service.NewRouteHandler(svc, SetForSubjectHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/pricing-tiers/{pricingTierId}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveFromSubjectHandler),
+		},
 	}
 }
 
@@ -94,6 +138,7 @@ func ListHandler(svc PricingTierService, w http.ResponseWriter, r *http.Request)
 		return err
 	}
 
+	middleware.SetCursorHeaders(w, listParams, pricingTiers)
 	service.SendJSONResponse(w, pricingTiers)
 	return nil
 }
@@ -133,3 +178,101 @@ func DeleteHandler(svc PricingTierService, w http.ResponseWriter, r *http.Reques
 
 	return nil
 }
+
+func ListForSubjectHandler(svc PricingTierService, w http.ResponseWriter, r *http.Request) error {
+	subject, err := SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	assignedPricingTiers, err := svc.ListForSubject(r.Context(), *subject)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedPricingTiers)
+	return nil
+}
+
+func AssignToSubjectHandler(svc PricingTierService, w http.ResponseWriter, r *http.Request) error {
+	var assignSpec AssignPricingTierSpec
+	err := service.ParseJSONBody(r.Body, &assignSpec)
+	if err != nil {
+		return err
+	}
+
+	subject, err := SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	assignedPricingTier, err := svc.AssignToSubject(r.Context(), *subject, assignSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedPricingTier)
+	return nil
+}
+
+func SetForSubjectHandler(svc PricingTierService, w http.ResponseWriter, r *http.Request) error {
+	var assignSpec AssignPricingTierSpec
+	err := service.ParseJSONBody(r.Body, &assignSpec)
+	if err != nil {
+		return err
+	}
+
+	subject, err := SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	assignedPricingTier, err := svc.SetForSubject(r.Context(), *subject, assignSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedPricingTier)
+	return nil
+}
+
+func RemoveFromSubjectHandler(svc PricingTierService, w http.ResponseWriter, r *http.Request) error {
+	subject, err := SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	pricingTierId, err := url.QueryUnescape(mux.Vars(r)["pricingTierId"])
+	if err != nil {
+		return service.NewInvalidParameterError("pricingTierId", "")
+	}
+
+	err = svc.RemoveFromSubject(r.Context(), *subject, pricingTierId)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// SubjectFromRequest returns the tenant or user an entitlement route is scoped to
+func SubjectFromRequest(r *http.Request) (*warrant.SubjectSpec, error) {
+	vars := mux.Vars(r)
+	if tenantIdParam, ok := vars["tenantId"]; ok {
+		tenantId, err := url.QueryUnescape(tenantIdParam)
+		if err != nil {
+			return nil, service.NewInvalidParameterError("tenantId", "")
+		}
+
+		return &warrant.SubjectSpec{ObjectType: objecttype.ObjectTypeTenant, ObjectId: tenantId}, nil
+	}
+
+	userId, err := url.QueryUnescape(vars["userId"])
+	if err != nil {
+		return nil, service.NewInvalidParameterError("userId", "")
+	}
+
+	return &warrant.SubjectSpec{ObjectType: objecttype.ObjectTypeUser, ObjectId: userId}, nil
+}
@@ -3,6 +3,8 @@ package authz
 import (
 	"fmt"
 	"time"
+
+	"github.com/warrant-dev/warrant/pkg/middleware"
 )
 
 type PricingTierListParamParser struct{}
@@ -41,3 +43,18 @@ func (parser PricingTierListParamParser) ParseValue(val string, sortBy string) (
 		return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
 	}
 }
+
+func (spec PricingTierSpec) ToCursor(sortBy string) middleware.Cursor {
+	switch sortBy {
+	case "createdAt":
+		return middleware.NewCursor(spec.PricingTierId, spec.CreatedAt)
+	case "name":
+		if !spec.Name.Valid {
+			return middleware.NewCursor(spec.PricingTierId, middleware.NullValue{})
+		}
+
+		return middleware.NewCursor(spec.PricingTierId, spec.Name.String)
+	default:
+		return middleware.NewCursor(spec.PricingTierId, nil)
+	}
+}
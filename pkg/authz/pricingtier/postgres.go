@@ -131,21 +131,9 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 	sortBy := regexp.MustCompile("([A-Z])").ReplaceAllString(listParams.SortBy, `_$1`)
 	if listParams.AfterId != "" {
 		if listParams.AfterValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s > ? OR (pricing_tier_id > ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s < ? OR (pricing_tier_id < ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(sortBy, "pricing_tier_id", listParams.SortOrder, listParams.AfterValue, listParams.AfterId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND pricing_tier_id > ?", query)
@@ -159,42 +147,31 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 
 	if listParams.BeforeId != "" {
 		if listParams.BeforeValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s < ? OR (pricing_tier_id < ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s > ? OR (pricing_tier_id > ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(sortBy, "pricing_tier_id", listParams.SortOrder.Reverse(), listParams.BeforeValue, listParams.BeforeId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND pricing_tier_id < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND pricing_tier_id > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	nullSortClause := "NULLS LAST"
-	if listParams.SortOrder == middleware.SortOrderAsc {
+	if sortOrder == middleware.SortOrderAsc {
 		nullSortClause = "NULLS FIRST"
 	}
 
 	if listParams.SortBy != "pricingTierId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s %s, pricing_tier_id %s LIMIT ?", query, sortBy, listParams.SortOrder, nullSortClause, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s %s, pricing_tier_id %s LIMIT ?", query, sortBy, sortOrder, nullSortClause, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY pricing_tier_id %s %s LIMIT ?", query, listParams.SortOrder, nullSortClause)
+		query = fmt.Sprintf("%s ORDER BY pricing_tier_id %s %s LIMIT ?", query, sortOrder, nullSortClause)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -217,6 +194,8 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 		models = append(models, &pricingTiers[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -2,9 +2,13 @@ package authz
 
 import (
 	"context"
+	"sort"
 
 	object "github.com/warrant-dev/warrant/pkg/authz/object"
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	tenant "github.com/warrant-dev/warrant/pkg/authz/tenant"
+	user "github.com/warrant-dev/warrant/pkg/authz/user"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
 	"github.com/warrant-dev/warrant/pkg/event"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
@@ -14,17 +18,23 @@ const ResourceTypePricingTier = "pricing-tier"
 
 type PricingTierService struct {
 	service.BaseService
-	repo      PricingTierRepository
-	eventSvc  event.EventService
-	objectSvc object.ObjectService
+	repo       PricingTierRepository
+	eventSvc   event.EventService
+	objectSvc  object.ObjectService
+	userSvc    user.UserService
+	tenantSvc  tenant.TenantService
+	warrantSvc warrant.WarrantService
 }
 
-func NewService(env service.Env, repo PricingTierRepository, eventSvc event.EventService, objectSvc object.ObjectService) PricingTierService {
+func NewService(env service.Env, repo PricingTierRepository, eventSvc event.EventService, objectSvc object.ObjectService, userSvc user.UserService, tenantSvc tenant.TenantService, warrantSvc warrant.WarrantService) PricingTierService {
 	return PricingTierService{
 		BaseService: service.NewBaseService(env),
 		repo:        repo,
 		eventSvc:    eventSvc,
 		objectSvc:   objectSvc,
+		userSvc:     userSvc,
+		tenantSvc:   tenantSvc,
+		warrantSvc:  warrantSvc,
 	}
 }
 
@@ -146,3 +156,162 @@ func (svc PricingTierService) DeleteByPricingTierId(ctx context.Context, pricing
 
 	return err
 }
+
+// ListForSubject returns the pricing tiers assigned to a tenant or user, sorted by pricingTierId
+func (svc PricingTierService) ListForSubject(ctx context.Context, subject warrant.SubjectSpec) ([]AssignedPricingTierSpec, error) {
+	err := svc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	warrantSpecs, err := svc.listAssignments(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	assignedPricingTierSpecs := make([]AssignedPricingTierSpec, 0)
+	for _, warrantSpec := range warrantSpecs {
+		// NOTE: warrants don't require their object to exist, so assignments of pricing tiers that were never created are skipped
+		pricingTierSpec, err := svc.GetByPricingTierId(ctx, warrantSpec.ObjectId)
+		if err != nil {
+			if _, ok := err.(*service.RecordNotFoundError); ok {
+				continue
+			}
+
+			return nil, err
+		}
+
+		assignedPricingTierSpecs = append(assignedPricingTierSpecs, *toAssignedPricingTierSpec(pricingTierSpec))
+	}
+
+	sort.Slice(assignedPricingTierSpecs, func(i, j int) bool {
+		return assignedPricingTierSpecs[i].PricingTierId < assignedPricingTierSpecs[j].PricingTierId
+	})
+	return assignedPricingTierSpecs, nil
+}
+
+// AssignToSubject assigns a pricing tier to a tenant or user, in addition to
+// the pricing tiers it already has
+func (svc PricingTierService) AssignToSubject(ctx context.Context, subject warrant.SubjectSpec, assignSpec AssignPricingTierSpec) (*AssignedPricingTierSpec, error) {
+	err := svc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	pricingTierSpec, err := svc.GetByPricingTierId(ctx, assignSpec.PricingTierId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.warrantSvc.Create(ctx, pricingTierWarrantSpec(assignSpec.PricingTierId, subject))
+	if err != nil {
+		return nil, err
+	}
+
+	return toAssignedPricingTierSpec(pricingTierSpec), nil
+}
+
+// SetForSubject makes a pricing tier the only one assigned to a tenant or
+// user, removing its other pricing tiers in the same transaction
+func (svc PricingTierService) SetForSubject(ctx context.Context, subject warrant.SubjectSpec, assignSpec AssignPricingTierSpec) (*AssignedPricingTierSpec, error) {
+	err := svc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	pricingTierSpec, err := svc.GetByPricingTierId(ctx, assignSpec.PricingTierId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		warrantSpecs, err := svc.listAssignments(txCtx, subject)
+		if err != nil {
+			return err
+		}
+
+		assigned := false
+		for _, warrantSpec := range warrantSpecs {
+			if warrantSpec.ObjectId == assignSpec.PricingTierId {
+				assigned = true
+				continue
+			}
+
+			err = svc.warrantSvc.Delete(txCtx, *warrantSpec)
+			if err != nil {
+				return err
+			}
+		}
+
+		if assigned {
+			return nil
+		}
+
+		_, err = svc.warrantSvc.Create(txCtx, pricingTierWarrantSpec(assignSpec.PricingTierId, subject))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toAssignedPricingTierSpec(pricingTierSpec), nil
+}
+
+// RemoveFromSubject removes a pricing tier's assignment to a tenant or user
+func (svc PricingTierService) RemoveFromSubject(ctx context.Context, subject warrant.SubjectSpec, pricingTierId string) error {
+	return svc.warrantSvc.Delete(ctx, pricingTierWarrantSpec(pricingTierId, subject))
+}
+
+// ValidateSubject checks that the tenant or user pricing tiers and features
+// are assigned to exists
+func (svc PricingTierService) ValidateSubject(ctx context.Context, subject warrant.SubjectSpec) error {
+	var err error
+	switch subject.ObjectType {
+	case objecttype.ObjectTypeTenant:
+		_, err = svc.tenantSvc.GetByTenantId(ctx, subject.ObjectId)
+	case objecttype.ObjectTypeUser:
+		_, err = svc.userSvc.GetByUserId(ctx, subject.ObjectId)
+	default:
+		err = service.NewInvalidParameterError("subject", "must be a tenant or user")
+	}
+
+	return err
+}
+
+// listAssignments returns the warrants that directly assign pricing tiers to the subject
+func (svc PricingTierService) listAssignments(ctx context.Context, subject warrant.SubjectSpec) ([]*warrant.WarrantSpec, error) {
+	warrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+		ObjectType: objecttype.ObjectTypePricingTier,
+		Relation:   objecttype.RelationMember,
+		Subject:    &subject,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := make([]*warrant.WarrantSpec, 0)
+	for _, warrantSpec := range warrantSpecs {
+		if warrantSpec.Subject.Relation == "" && len(warrantSpec.Context) == 0 {
+			assignments = append(assignments, warrantSpec)
+		}
+	}
+
+	return assignments, nil
+}
+
+func pricingTierWarrantSpec(pricingTierId string, subject warrant.SubjectSpec) warrant.WarrantSpec {
+	return warrant.WarrantSpec{
+		ObjectType: objecttype.ObjectTypePricingTier,
+		ObjectId:   pricingTierId,
+		Relation:   objecttype.RelationMember,
+		Subject:    &subject,
+	}
+}
+
+func toAssignedPricingTierSpec(pricingTierSpec *PricingTierSpec) *AssignedPricingTierSpec {
+	return &AssignedPricingTierSpec{
+		PricingTierId: pricingTierSpec.PricingTierId,
+		Name:          pricingTierSpec.Name,
+		Description:   pricingTierSpec.Description,
+	}
+}
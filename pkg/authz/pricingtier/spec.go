@@ -37,3 +37,14 @@ type UpdatePricingTierSpec struct {
 	Name        database.NullString `json:"name"`
 	Description database.NullString `json:"description"`
 }
+
+type AssignPricingTierSpec struct {
+	PricingTierId string `json:"pricingTierId" validate:"required"`
+}
+
+// AssignedPricingTierSpec type for a pricing tier assigned to a tenant or user
+type AssignedPricingTierSpec struct {
+	PricingTierId string              `json:"pricingTierId"`
+	Name          database.NullString `json:"name"`
+	Description   database.NullString `json:"description"`
+}
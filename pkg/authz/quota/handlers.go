@@ -0,0 +1,317 @@
+package authz
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	pricingtier "github.com/warrant-dev/warrant/pkg/authz/pricingtier"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+// GetRoutes registers all route handlers for this module
+func (svc QuotaService) Routes() []service.Route {
+	return []service.Route{
+		// limits
+		{
+			Pattern: "/v1/pricing-tiers/{pricingTierId}/limits",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListLimitsHandler),
+		},
+		{
+			Pattern: "/v1/pricing-tiers/{pricingTierId}/limits/{limitName}",
+			Method:  "PUT",
+			Handler: service.NewRouteHandler(svc, SetLimitHandler),
+		},
+		{
+			Pattern: "/v1/pricing-tiers/{pricingTierId}/limits/{limitName}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, DeleteLimitHandler),
+		},
+		{
+			Pattern: "/v1/features/{featureId}/limits",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListLimitsHandler),
+		},
+		{
+			Pattern: "/v1/features/{featureId}/limits/{limitName}",
+			Method:  "PUT",
+			Handler: service.NewRouteHandler(svc, SetLimitHandler),
+		},
+		{
+			Pattern: "/v1/features/{featureId}/limits/{limitName}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, DeleteLimitHandler),
+		},
+
+		// tenant and user limit overrides
+		{
+			Pattern: "/v1/tenants/{tenantId}/limits",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListQuotasHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/limits/{limitName}",
+			Method:  "PUT",
+			Handler: service.NewRouteHandler(svc, SetLimitHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/limits/{limitName}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, DeleteLimitHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/limits",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListQuotasHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/limits/{limitName}",
+			Method:  "PUT",
+			Handler: service.NewRouteHandler(svc, SetLimitHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/limits/{limitName}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, DeleteLimitHandler),
+		},
+
+		// usage
+		{
+			Pattern: "/v1/tenants/{tenantId}/usage/{limitName}",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, GetQuotaHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/usage/{limitName}",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, IncrementUsageHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/usage/{limitName}/consume",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, ConsumeHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/usage/{limitName}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, ResetUsageHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/usage/{limitName}",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, GetQuotaHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/usage/{limitName}",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, IncrementUsageHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/usage/{limitName}/consume",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, ConsumeHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/usage/{limitName}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, ResetUsageHandler),
+		},
+	}
+}
+
+func ListLimitsHandler(svc QuotaService, w http.ResponseWriter, r *http.Request) error {
+	object, err := objectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	limits, err := svc.ListLimits(r.Context(), *object)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, limits)
+	return nil
+}
+
+func SetLimitHandler(svc QuotaService, w http.ResponseWriter, r *http.Request) error {
+	var limitSpec SetLimitSpec
+	err := service.ParseJSONBody(r.Body, &limitSpec)
+	if err != nil {
+		return err
+	}
+
+	object, err := objectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	limitName, err := url.QueryUnescape(mux.Vars(r)["limitName"])
+	if err != nil {
+		return service.NewInvalidParameterError("limitName", "")
+	}
+
+	updatedLimit, err := svc.SetLimit(r.Context(), *object, limitName, limitSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, updatedLimit)
+	return nil
+}
+
+func DeleteLimitHandler(svc QuotaService, w http.ResponseWriter, r *http.Request) error {
+	object, err := objectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	limitName, err := url.QueryUnescape(mux.Vars(r)["limitName"])
+	if err != nil {
+		return service.NewInvalidParameterError("limitName", "")
+	}
+
+	err = svc.DeleteLimit(r.Context(), *object, limitName)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func ListQuotasHandler(svc QuotaService, w http.ResponseWriter, r *http.Request) error {
+	subject, err := pricingtier.SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	quotas, err := svc.ListQuotas(r.Context(), *subject)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, quotas)
+	return nil
+}
+
+func GetQuotaHandler(svc QuotaService, w http.ResponseWriter, r *http.Request) error {
+	subject, err := pricingtier.SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	limitName, err := url.QueryUnescape(mux.Vars(r)["limitName"])
+	if err != nil {
+		return service.NewInvalidParameterError("limitName", "")
+	}
+
+	quota, err := svc.GetQuota(r.Context(), *subject, limitName)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, quota)
+	return nil
+}
+
+func IncrementUsageHandler(svc QuotaService, w http.ResponseWriter, r *http.Request) error {
+	var amountSpec UsageAmountSpec
+	err := service.ParseJSONBody(r.Body, &amountSpec)
+	if err != nil {
+		return err
+	}
+
+	subject, err := pricingtier.SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	limitName, err := url.QueryUnescape(mux.Vars(r)["limitName"])
+	if err != nil {
+		return service.NewInvalidParameterError("limitName", "")
+	}
+
+	quota, err := svc.IncrementUsage(r.Context(), *subject, limitName, amountSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, quota)
+	return nil
+}
+
+func ConsumeHandler(svc QuotaService, w http.ResponseWriter, r *http.Request) error {
+	var amountSpec UsageAmountSpec
+	err := service.ParseJSONBody(r.Body, &amountSpec)
+	if err != nil {
+		return err
+	}
+
+	subject, err := pricingtier.SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	limitName, err := url.QueryUnescape(mux.Vars(r)["limitName"])
+	if err != nil {
+		return service.NewInvalidParameterError("limitName", "")
+	}
+
+	consumeResult, err := svc.Consume(r.Context(), *subject, limitName, amountSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, consumeResult)
+	return nil
+}
+
+func ResetUsageHandler(svc QuotaService, w http.ResponseWriter, r *http.Request) error {
+	subject, err := pricingtier.SubjectFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	limitName, err := url.QueryUnescape(mux.Vars(r)["limitName"])
+	if err != nil {
+		return service.NewInvalidParameterError("limitName", "")
+	}
+
+	err = svc.ResetUsage(r.Context(), *subject, limitName)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// objectFromRequest returns the pricing tier, feature, tenant, or user a limit route is scoped to
+func objectFromRequest(r *http.Request) (*warrant.SubjectSpec, error) {
+	vars := mux.Vars(r)
+	if pricingTierIdParam, ok := vars["pricingTierId"]; ok {
+		pricingTierId, err := url.QueryUnescape(pricingTierIdParam)
+		if err != nil {
+			return nil, service.NewInvalidParameterError("pricingTierId", "")
+		}
+
+		return &warrant.SubjectSpec{ObjectType: objecttype.ObjectTypePricingTier, ObjectId: pricingTierId}, nil
+	}
+
+	if featureIdParam, ok := vars["featureId"]; ok {
+		featureId, err := url.QueryUnescape(featureIdParam)
+		if err != nil {
+			return nil, service.NewInvalidParameterError("featureId", "")
+		}
+
+		return &warrant.SubjectSpec{ObjectType: objecttype.ObjectTypeFeature, ObjectId: featureId}, nil
+	}
+
+	return pricingtier.SubjectFromRequest(r)
+}
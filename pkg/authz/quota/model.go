@@ -0,0 +1,110 @@
+package authz
+
+import (
+	"time"
+)
+
+type LimitModel interface {
+	GetID() int64
+	GetObjectType() string
+	GetObjectId() string
+	GetName() string
+	GetValue() int64
+	GetCreatedAt() time.Time
+	GetUpdatedAt() time.Time
+	ToLimitSpec() *LimitSpec
+}
+
+type Limit struct {
+	ID         int64     `mysql:"id" postgres:"id"`
+	ObjectType string    `mysql:"objectType" postgres:"object_type"`
+	ObjectId   string    `mysql:"objectId" postgres:"object_id"`
+	Name       string    `mysql:"name" postgres:"name"`
+	Value      int64     `mysql:"limitValue" postgres:"limit_value"`
+	CreatedAt  time.Time `mysql:"createdAt" postgres:"created_at"`
+	UpdatedAt  time.Time `mysql:"updatedAt" postgres:"updated_at"`
+}
+
+func (limit Limit) GetID() int64 {
+	return limit.ID
+}
+
+func (limit Limit) GetObjectType() string {
+	return limit.ObjectType
+}
+
+func (limit Limit) GetObjectId() string {
+	return limit.ObjectId
+}
+
+func (limit Limit) GetName() string {
+	return limit.Name
+}
+
+func (limit Limit) GetValue() int64 {
+	return limit.Value
+}
+
+func (limit Limit) GetCreatedAt() time.Time {
+	return limit.CreatedAt
+}
+
+func (limit Limit) GetUpdatedAt() time.Time {
+	return limit.UpdatedAt
+}
+
+func (limit Limit) ToLimitSpec() *LimitSpec {
+	return &LimitSpec{
+		Name:      limit.Name,
+		Value:     limit.Value,
+		CreatedAt: limit.CreatedAt,
+	}
+}
+
+type UsageModel interface {
+	GetID() int64
+	GetObjectType() string
+	GetObjectId() string
+	GetName() string
+	GetUsed() int64
+	GetCreatedAt() time.Time
+	GetUpdatedAt() time.Time
+}
+
+type Usage struct {
+	ID         int64     `mysql:"id" postgres:"id"`
+	ObjectType string    `mysql:"objectType" postgres:"object_type"`
+	ObjectId   string    `mysql:"objectId" postgres:"object_id"`
+	Name       string    `mysql:"name" postgres:"name"`
+	Used       int64     `mysql:"used" postgres:"used"`
+	CreatedAt  time.Time `mysql:"createdAt" postgres:"created_at"`
+	UpdatedAt  time.Time `mysql:"updatedAt" postgres:"updated_at"`
+}
+
+func (usage Usage) GetID() int64 {
+	return usage.ID
+}
+
+func (usage Usage) GetObjectType() string {
+	return usage.ObjectType
+}
+
+func (usage Usage) GetObjectId() string {
+	return usage.ObjectId
+}
+
+func (usage Usage) GetName() string {
+	return usage.Name
+}
+
+func (usage Usage) GetUsed() int64 {
+	return usage.Used
+}
+
+func (usage Usage) GetCreatedAt() time.Time {
+	return usage.CreatedAt
+}
+
+func (usage Usage) GetUpdatedAt() time.Time {
+	return usage.UpdatedAt
+}
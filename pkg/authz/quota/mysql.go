@@ -0,0 +1,416 @@
+package authz
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/warrant-dev/warrant/pkg/database"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+type MySQLRepository struct {
+	database.SQLRepository
+}
+
+func NewMySQLRepository(db *database.MySQL) MySQLRepository {
+	return MySQLRepository{
+		database.NewSQLRepository(&db.SQL),
+	}
+}
+
+func (repo MySQLRepository) UpsertLimit(ctx context.Context, model LimitModel) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO quotaLimit (
+				objectType,
+				objectId,
+				name,
+				limitValue
+			) VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE
+				limitValue = ?
+		`,
+		model.GetObjectType(),
+		model.GetObjectId(),
+		model.GetName(),
+		model.GetValue(),
+		model.GetValue(),
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to upsert limit %s for %s:%s", model.GetName(), model.GetObjectType(), model.GetObjectId()))
+	}
+
+	return nil
+}
+
+func (repo MySQLRepository) ListLimits(ctx context.Context, filterOptions *LimitFilterOptions) ([]LimitModel, error) {
+	models := make([]LimitModel, 0)
+	limits := make([]Limit, 0)
+	query := `
+		SELECT id, objectType, objectId, name, limitValue, createdAt, updatedAt
+		FROM quotaLimit
+		WHERE
+			1 = 1
+	`
+	replacements := []interface{}{}
+
+	if filterOptions.ObjectType != "" {
+		query = fmt.Sprintf("%s AND objectType = ?", query)
+		replacements = append(replacements, filterOptions.ObjectType)
+	}
+
+	if filterOptions.ObjectId != "" {
+		query = fmt.Sprintf("%s AND objectId = ?", query)
+		replacements = append(replacements, filterOptions.ObjectId)
+	}
+
+	if filterOptions.Name != "" {
+		query = fmt.Sprintf("%s AND name = ?", query)
+		replacements = append(replacements, filterOptions.Name)
+	}
+
+	query = fmt.Sprintf("%s ORDER BY name ASC, objectType ASC, objectId ASC", query)
+	err := repo.DB.SelectContext(
+		ctx,
+		&limits,
+		query,
+		replacements...,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, "Unable to list limits from mysql")
+		}
+	}
+
+	for i := range limits {
+		models = append(models, &limits[i])
+	}
+
+	return models, nil
+}
+
+func (repo MySQLRepository) DeleteLimit(ctx context.Context, objectType string, objectId string, name string) error {
+	result, err := repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM quotaLimit
+			WHERE
+				objectType = ? AND
+				objectId = ? AND
+				name = ?
+		`,
+		objectType,
+		objectId,
+		name,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete limit %s for %s:%s from mysql", name, objectType, objectId))
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete limit %s for %s:%s from mysql", name, objectType, objectId))
+	}
+
+	if rows == 0 {
+		return service.NewRecordNotFoundError("Limit", name)
+	}
+
+	return nil
+}
+
+func (repo MySQLRepository) GetUsage(ctx context.Context, objectType string, objectId string, name string) (UsageModel, error) {
+	var usage Usage
+	err := repo.DB.GetContext(
+		ctx,
+		&usage,
+		`
+			SELECT id, objectType, objectId, name, used, createdAt, updatedAt
+			FROM quotaUsage
+			WHERE
+				objectType = ? AND
+				objectId = ? AND
+				name = ?
+		`,
+		objectType,
+		objectId,
+		name,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, service.NewRecordNotFoundError("Usage", name)
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to get usage %s for %s:%s from mysql", name, objectType, objectId))
+		}
+	}
+
+	return &usage, nil
+}
+
+func (repo MySQLRepository) ListUsage(ctx context.Context, objectType string, objectId string) ([]UsageModel, error) {
+	models := make([]UsageModel, 0)
+	usages := make([]Usage, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&usages,
+		`
+			SELECT id, objectType, objectId, name, used, createdAt, updatedAt
+			FROM quotaUsage
+			WHERE
+				objectType = ? AND
+				objectId = ?
+			ORDER BY name ASC
+		`,
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to list usage for %s:%s from mysql", objectType, objectId))
+		}
+	}
+
+	for i := range usages {
+		models = append(models, &usages[i])
+	}
+
+	return models, nil
+}
+
+func (repo MySQLRepository) IncrementUsage(ctx context.Context, objectType string, objectId string, name string, amount int64) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO quotaUsage (
+				objectType,
+				objectId,
+				name,
+				used
+			) VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE
+				used = quotaUsage.used + ?
+		`,
+		objectType,
+		objectId,
+		name,
+		amount,
+		amount,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to increment usage %s for %s:%s", name, objectType, objectId))
+	}
+
+	return nil
+}
+
+// IncrementUsageWithinLimit increments usage by amount only if the result
+// doesn't exceed limit, returning whether it was incremented. The check and
+// increment are a single statement, so concurrent increments can't overshoot the limit.
+func (repo MySQLRepository) IncrementUsageWithinLimit(ctx context.Context, objectType string, objectId string, name string, amount int64, limit int64) (bool, error) {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO quotaUsage (
+				objectType,
+				objectId,
+				name,
+				used
+			) VALUES (?, ?, ?, 0)
+			ON DUPLICATE KEY UPDATE
+				used = used
+		`,
+		objectType,
+		objectId,
+		name,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to create usage %s for %s:%s", name, objectType, objectId))
+	}
+
+	result, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE quotaUsage
+			SET used = used + ?
+			WHERE
+				objectType = ? AND
+				objectId = ? AND
+				name = ? AND
+				used + ? <= ?
+		`,
+		amount,
+		objectType,
+		objectId,
+		name,
+		amount,
+		limit,
+	)
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to increment usage %s for %s:%s", name, objectType, objectId))
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("Unable to increment usage %s for %s:%s", name, objectType, objectId))
+	}
+
+	return rows == 1, nil
+}
+
+func (repo MySQLRepository) DeleteUsage(ctx context.Context, objectType string, objectId string, name string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM quotaUsage
+			WHERE
+				objectType = ? AND
+				objectId = ? AND
+				name = ?
+		`,
+		objectType,
+		objectId,
+		name,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete usage %s for %s:%s from mysql", name, objectType, objectId))
+	}
+
+	return nil
+}
+
+func (repo MySQLRepository) ListLimitsAfterId(ctx context.Context, afterId int64, limit int) ([]LimitModel, error) {
+	models := make([]LimitModel, 0)
+	limits := make([]Limit, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&limits,
+		`
+			SELECT id, objectType, objectId, name, limitValue, createdAt, updatedAt
+			FROM quotaLimit
+			WHERE
+				id > ?
+			ORDER BY id ASC
+			LIMIT ?
+		`,
+		afterId,
+		limit,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, "Unable to list limits from mysql")
+		}
+	}
+
+	for i := range limits {
+		models = append(models, &limits[i])
+	}
+
+	return models, nil
+}
+
+func (repo MySQLRepository) ListUsageAfterId(ctx context.Context, afterId int64, limit int) ([]UsageModel, error) {
+	models := make([]UsageModel, 0)
+	usages := make([]Usage, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&usages,
+		`
+			SELECT id, objectType, objectId, name, used, createdAt, updatedAt
+			FROM quotaUsage
+			WHERE
+				id > ?
+			ORDER BY id ASC
+			LIMIT ?
+		`,
+		afterId,
+		limit,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, "Unable to list usage from mysql")
+		}
+	}
+
+	for i := range usages {
+		models = append(models, &usages[i])
+	}
+
+	return models, nil
+}
+
+func (repo MySQLRepository) SetUsage(ctx context.Context, objectType string, objectId string, name string, used int64) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO quotaUsage (
+				objectType,
+				objectId,
+				name,
+				used
+			) VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE
+				used = ?
+		`,
+		objectType,
+		objectId,
+		name,
+		used,
+		used,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to set usage %s for %s:%s", name, objectType, objectId))
+	}
+
+	return nil
+}
+
+// DeleteAllForObject deletes the limits and usage of the given object
+func (repo MySQLRepository) DeleteAllForObject(ctx context.Context, objectType string, objectId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM quotaLimit
+			WHERE
+				objectType = ? AND
+				objectId = ?
+		`,
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete limits for %s:%s from mysql", objectType, objectId))
+	}
+
+	_, err = repo.DB.ExecContext(
+		ctx,
+		`
+			DELETE FROM quotaUsage
+			WHERE
+				objectType = ? AND
+				objectId = ?
+		`,
+		objectType,
+		objectId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete usage for %s:%s from mysql", objectType, objectId))
+	}
+
+	return nil
+}
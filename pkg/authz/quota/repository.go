@@ -0,0 +1,51 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+type QuotaRepository interface {
+	UpsertLimit(ctx context.Context, limit LimitModel) error
+	ListLimits(ctx context.Context, filterOptions *LimitFilterOptions) ([]LimitModel, error)
+	DeleteLimit(ctx context.Context, objectType string, objectId string, name string) error
+	GetUsage(ctx context.Context, objectType string, objectId string, name string) (UsageModel, error)
+	ListUsage(ctx context.Context, objectType string, objectId string) ([]UsageModel, error)
+	IncrementUsage(ctx context.Context, objectType string, objectId string, name string, amount int64) error
+	IncrementUsageWithinLimit(ctx context.Context, objectType string, objectId string, name string, amount int64, limit int64) (bool, error)
+	DeleteUsage(ctx context.Context, objectType string, objectId string, name string) error
+	ListLimitsAfterId(ctx context.Context, afterId int64, limit int) ([]LimitModel, error)
+	ListUsageAfterId(ctx context.Context, afterId int64, limit int) ([]UsageModel, error)
+	SetUsage(ctx context.Context, objectType string, objectId string, name string, used int64) error
+	DeleteAllForObject(ctx context.Context, objectType string, objectId string) error
+}
+
+// LimitFilterOptions type for filtering limits. Only the fields that are set are filtered on.
+type LimitFilterOptions struct {
+	ObjectType string
+	ObjectId   string
+	Name       string
+}
+
+func NewRepository(db database.Database) (QuotaRepository, error) {
+	switch db.Type() {
+	case database.TypeMySQL:
+		mysql, ok := db.(*database.MySQL)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s database config", database.TypeMySQL)
+		}
+
+		return NewMySQLRepository(mysql), nil
+	case database.TypePostgres:
+		postgres, ok := db.(*database.Postgres)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s database config", database.TypePostgres)
+		}
+
+		return NewPostgresRepository(postgres), nil
+	default:
+		return nil, fmt.Errorf("unsupported database type %s specified", db.Type())
+	}
+}
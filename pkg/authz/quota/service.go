@@ -0,0 +1,516 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	check "github.com/warrant-dev/warrant/pkg/authz/check"
+	feature "github.com/warrant-dev/warrant/pkg/authz/feature"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	pricingtier "github.com/warrant-dev/warrant/pkg/authz/pricingtier"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/event"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+const ResourceTypeLimit = "limit"
+
+const MaxLimitNameLength = 64
+
+var limitNameRegExp = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+type QuotaService struct {
+	service.BaseService
+	repo           QuotaRepository
+	eventSvc       event.EventService
+	pricingTierSvc pricingtier.PricingTierService
+	featureSvc     feature.FeatureService
+	checkSvc       check.CheckService
+}
+
+func NewService(env service.Env, repo QuotaRepository, eventSvc event.EventService, pricingTierSvc pricingtier.PricingTierService, featureSvc feature.FeatureService, checkSvc check.CheckService) QuotaService {
+	return QuotaService{
+		BaseService:    service.NewBaseService(env),
+		repo:           repo,
+		eventSvc:       eventSvc,
+		pricingTierSvc: pricingTierSvc,
+		featureSvc:     featureSvc,
+		checkSvc:       checkSvc,
+	}
+}
+
+// ListLimits returns the limits defined on a pricing tier or feature, or
+// overridden for a tenant or user, sorted by name
+func (svc QuotaService) ListLimits(ctx context.Context, object warrant.SubjectSpec) ([]LimitSpec, error) {
+	err := svc.validateObject(ctx, object)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := svc.repo.ListLimits(ctx, &LimitFilterOptions{
+		ObjectType: object.ObjectType,
+		ObjectId:   object.ObjectId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limitSpecs := make([]LimitSpec, 0)
+	for _, limit := range limits {
+		limitSpecs = append(limitSpecs, *limit.ToLimitSpec())
+	}
+
+	return limitSpecs, nil
+}
+
+// SetLimit creates or updates a limit on a pricing tier or feature. A limit
+// set on a tenant or user overrides the limits of its pricing tiers and features.
+func (svc QuotaService) SetLimit(ctx context.Context, object warrant.SubjectSpec, name string, limitSpec SetLimitSpec) (*LimitSpec, error) {
+	err := validateLimitName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.validateObject(ctx, object)
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedLimitSpec *LimitSpec
+	err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		err := svc.repo.UpsertLimit(txCtx, &Limit{
+			ObjectType: object.ObjectType,
+			ObjectId:   object.ObjectId,
+			Name:       name,
+			Value:      *limitSpec.Value,
+		})
+		if err != nil {
+			return err
+		}
+
+		limits, err := svc.repo.ListLimits(txCtx, &LimitFilterOptions{
+			ObjectType: object.ObjectType,
+			ObjectId:   object.ObjectId,
+			Name:       name,
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(limits) != 1 {
+			return service.NewInternalError(fmt.Sprintf("Unable to set limit %s", name))
+		}
+
+		updatedLimitSpec = limits[0].ToLimitSpec()
+		svc.eventSvc.TrackResourceUpdated(txCtx, ResourceTypeLimit, limitResourceId(object, name), updatedLimitSpec)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedLimitSpec, nil
+}
+
+func (svc QuotaService) DeleteLimit(ctx context.Context, object warrant.SubjectSpec, name string) error {
+	err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		err := svc.repo.DeleteLimit(txCtx, object.ObjectType, object.ObjectId, name)
+		if err != nil {
+			return err
+		}
+
+		svc.eventSvc.TrackResourceDeleted(txCtx, ResourceTypeLimit, limitResourceId(object, name), nil)
+		return nil
+	})
+
+	return err
+}
+
+// ListQuotas returns the effective limits of a tenant or user, along with how
+// much of each has been used, sorted by name. Usage recorded against names
+// the tenant or user has no limit for is included with a limit of 0.
+func (svc QuotaService) ListQuotas(ctx context.Context, subject warrant.SubjectSpec) ([]QuotaSpec, error) {
+	err := svc.pricingTierSvc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveLimits, err := svc.effectiveLimits(ctx, subject, "")
+	if err != nil {
+		return nil, err
+	}
+
+	usages, err := svc.repo.ListUsage(ctx, subject.ObjectType, subject.ObjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	usedByName := make(map[string]int64)
+	for _, usage := range usages {
+		usedByName[usage.GetName()] = usage.GetUsed()
+		if _, ok := effectiveLimits[usage.GetName()]; !ok {
+			effectiveLimits[usage.GetName()] = nil
+		}
+	}
+
+	quotaSpecs := make([]QuotaSpec, 0, len(effectiveLimits))
+	for name, limit := range effectiveLimits {
+		quotaSpecs = append(quotaSpecs, *toQuotaSpec(name, limit, usedByName[name]))
+	}
+
+	sort.Slice(quotaSpecs, func(i, j int) bool {
+		return quotaSpecs[i].Name < quotaSpecs[j].Name
+	})
+	return quotaSpecs, nil
+}
+
+// GetQuota returns the effective limit of a tenant or user with the given name
+// and how much of it has been used
+func (svc QuotaService) GetQuota(ctx context.Context, subject warrant.SubjectSpec, name string) (*QuotaSpec, error) {
+	err := svc.pricingTierSvc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveLimits, err := svc.effectiveLimits(ctx, subject, name)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := svc.getUsed(ctx, subject, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return toQuotaSpec(name, effectiveLimits[name], used), nil
+}
+
+// IncrementUsage records usage of a quota regardless of the tenant or user's
+// limit, e.g. for usage that has already happened and is billed as overage
+func (svc QuotaService) IncrementUsage(ctx context.Context, subject warrant.SubjectSpec, name string, amountSpec UsageAmountSpec) (*QuotaSpec, error) {
+	err := validateLimitName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.pricingTierSvc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveLimits, err := svc.effectiveLimits(ctx, subject, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var used int64
+	err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		err := svc.repo.IncrementUsage(txCtx, subject.ObjectType, subject.ObjectId, name, amountSpec.Amount)
+		if err != nil {
+			return err
+		}
+
+		used, err = svc.getUsed(txCtx, subject, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toQuotaSpec(name, effectiveLimits[name], used), nil
+}
+
+// Consume increments usage of a quota by the given amount only if the tenant
+// or user's usage stays within its limit. The check and increment are atomic,
+// so concurrent consumers can't exceed the limit.
+func (svc QuotaService) Consume(ctx context.Context, subject warrant.SubjectSpec, name string, amountSpec UsageAmountSpec) (*ConsumeResultSpec, error) {
+	err := validateLimitName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.pricingTierSvc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveLimits, err := svc.effectiveLimits(ctx, subject, name)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := effectiveLimits[name]
+	var allowed bool
+	var used int64
+	err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		// NOTE: a tenant or user without a limit can't consume any of the quota
+		if limit != nil {
+			allowed, err = svc.repo.IncrementUsageWithinLimit(txCtx, subject.ObjectType, subject.ObjectId, name, amountSpec.Amount, limit.GetValue())
+			if err != nil {
+				return err
+			}
+		}
+
+		used, err = svc.getUsed(txCtx, subject, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConsumeResultSpec{
+		QuotaSpec: *toQuotaSpec(name, limit, used),
+		Amount:    amountSpec.Amount,
+		Allowed:   allowed,
+	}, nil
+}
+
+// ResetUsage sets a tenant or user's usage of a quota back to 0, e.g. at the
+// start of a billing period
+func (svc QuotaService) ResetUsage(ctx context.Context, subject warrant.SubjectSpec, name string) error {
+	return svc.repo.DeleteUsage(ctx, subject.ObjectType, subject.ObjectId, name)
+}
+
+// GetLimit returns the limit with the given name defined on a pricing tier or
+// feature, or overridden for a tenant or user
+func (svc QuotaService) GetLimit(ctx context.Context, object warrant.SubjectSpec, name string) (*LimitSpec, error) {
+	limits, err := svc.repo.ListLimits(ctx, &LimitFilterOptions{
+		ObjectType: object.ObjectType,
+		ObjectId:   object.ObjectId,
+		Name:       name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(limits) == 0 {
+		return nil, service.NewRecordNotFoundError("Limit", name)
+	}
+
+	return limits[0].ToLimitSpec(), nil
+}
+
+// GetUsage returns how much of the quota with the given name a tenant or user
+// has used, or a RecordNotFoundError if it has no usage recorded
+func (svc QuotaService) GetUsage(ctx context.Context, subject warrant.SubjectSpec, name string) (*ObjectUsageSpec, error) {
+	usage, err := svc.repo.GetUsage(ctx, subject.ObjectType, subject.ObjectId, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return toObjectUsageSpec(usage), nil
+}
+
+// SetUsage sets how much of a quota a tenant or user has used, e.g. when
+// importing a snapshot
+func (svc QuotaService) SetUsage(ctx context.Context, subject warrant.SubjectSpec, name string, used int64) error {
+	err := validateLimitName(name)
+	if err != nil {
+		return err
+	}
+
+	err = svc.pricingTierSvc.ValidateSubject(ctx, subject)
+	if err != nil {
+		return err
+	}
+
+	return svc.repo.SetUsage(ctx, subject.ObjectType, subject.ObjectId, name, used)
+}
+
+// ForEachLimit calls fn with every limit, reading batchSize limits at a time
+func (svc QuotaService) ForEachLimit(ctx context.Context, batchSize int, fn func(limitSpec ObjectLimitSpec) error) error {
+	var afterId int64
+	for {
+		limits, err := svc.repo.ListLimitsAfterId(ctx, afterId, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, limit := range limits {
+			err = fn(ObjectLimitSpec{
+				ObjectType: limit.GetObjectType(),
+				ObjectId:   limit.GetObjectId(),
+				Name:       limit.GetName(),
+				Value:      limit.GetValue(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(limits) < batchSize {
+			return nil
+		}
+
+		afterId = limits[len(limits)-1].GetID()
+	}
+}
+
+// ForEachUsage calls fn with the usage of every quota, reading batchSize usages at a time
+func (svc QuotaService) ForEachUsage(ctx context.Context, batchSize int, fn func(usageSpec ObjectUsageSpec) error) error {
+	var afterId int64
+	for {
+		usages, err := svc.repo.ListUsageAfterId(ctx, afterId, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, usage := range usages {
+			err = fn(*toObjectUsageSpec(usage))
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(usages) < batchSize {
+			return nil
+		}
+
+		afterId = usages[len(usages)-1].GetID()
+	}
+}
+
+// effectiveLimits returns the limits that apply to a tenant or user by name,
+// or only the limit with the given name if name is not empty. A limit
+// overridden for the tenant or user takes precedence. Otherwise the highest
+// limit of the pricing tiers and features the tenant or user has applies.
+func (svc QuotaService) effectiveLimits(ctx context.Context, subject warrant.SubjectSpec, name string) (map[string]LimitModel, error) {
+	effectiveLimits := make(map[string]LimitModel)
+	overrides, err := svc.repo.ListLimits(ctx, &LimitFilterOptions{
+		ObjectType: subject.ObjectType,
+		ObjectId:   subject.ObjectId,
+		Name:       name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, override := range overrides {
+		effectiveLimits[override.GetName()] = override
+	}
+
+	// NOTE: limits are grouped by name, so only the pricing tiers and features
+	// that define a limit the tenant or user hasn't overridden are checked
+	limitsByName := make(map[string][]LimitModel)
+	for _, objectType := range []string{objecttype.ObjectTypePricingTier, objecttype.ObjectTypeFeature} {
+		limits, err := svc.repo.ListLimits(ctx, &LimitFilterOptions{
+			ObjectType: objectType,
+			Name:       name,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, limit := range limits {
+			if _, overridden := effectiveLimits[limit.GetName()]; !overridden {
+				limitsByName[limit.GetName()] = append(limitsByName[limit.GetName()], limit)
+			}
+		}
+	}
+
+	// NOTE: resolving limits doesn't grant or deny any access, so the checks aren't tracked as access events
+	checkCtx := event.WithoutTracking(ctx)
+	isMember := make(map[string]bool)
+	for limitName, limits := range limitsByName {
+		// NOTE: the highest limit applies, so limits are checked from highest to lowest until the subject has one
+		sort.SliceStable(limits, func(i, j int) bool {
+			return limits[i].GetValue() > limits[j].GetValue()
+		})
+		for _, limit := range limits {
+			// NOTE: the subject's membership in each pricing tier and feature is checked once
+			warrantSpec := warrant.WarrantSpec{
+				ObjectType: limit.GetObjectType(),
+				ObjectId:   limit.GetObjectId(),
+				Relation:   objecttype.RelationMember,
+				Subject:    &subject,
+			}
+			match, checked := isMember[warrantSpec.String()]
+			if !checked {
+				match, _, err = svc.checkSvc.Check(checkCtx, nil, check.CheckSpec{WarrantSpec: warrantSpec})
+				if err != nil {
+					return nil, err
+				}
+
+				isMember[warrantSpec.String()] = match
+			}
+
+			if match {
+				effectiveLimits[limitName] = limit
+				break
+			}
+		}
+	}
+
+	return effectiveLimits, nil
+}
+
+func (svc QuotaService) getUsed(ctx context.Context, subject warrant.SubjectSpec, name string) (int64, error) {
+	usage, err := svc.repo.GetUsage(ctx, subject.ObjectType, subject.ObjectId, name)
+	if err != nil {
+		if _, ok := err.(*service.RecordNotFoundError); ok {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return usage.GetUsed(), nil
+}
+
+// validateObject checks that the pricing tier, feature, tenant, or user a limit is set on exists
+func (svc QuotaService) validateObject(ctx context.Context, object warrant.SubjectSpec) error {
+	var err error
+	switch object.ObjectType {
+	case objecttype.ObjectTypePricingTier:
+		_, err = svc.pricingTierSvc.GetByPricingTierId(ctx, object.ObjectId)
+	case objecttype.ObjectTypeFeature:
+		_, err = svc.featureSvc.GetByFeatureId(ctx, object.ObjectId)
+	default:
+		err = svc.pricingTierSvc.ValidateSubject(ctx, object)
+	}
+
+	return err
+}
+
+func validateLimitName(name string) error {
+	if name == "" || len(name) > MaxLimitNameLength || !limitNameRegExp.MatchString(name) {
+		return service.NewInvalidParameterError("limitName", fmt.Sprintf("must be provided, can only contain alphanumeric characters and/or '-' and '_', and must be at most %d characters", MaxLimitNameLength))
+	}
+
+	return nil
+}
+
+func limitResourceId(object warrant.SubjectSpec, name string) string {
+	return fmt.Sprintf("%s:%s#%s", object.ObjectType, object.ObjectId, name)
+}
+
+func toObjectUsageSpec(usage UsageModel) *ObjectUsageSpec {
+	return &ObjectUsageSpec{
+		ObjectType: usage.GetObjectType(),
+		ObjectId:   usage.GetObjectId(),
+		Name:       usage.GetName(),
+		Used:       usage.GetUsed(),
+	}
+}
+
+func toQuotaSpec(name string, limit LimitModel, used int64) *QuotaSpec {
+	quotaSpec := QuotaSpec{
+		Name: name,
+		Used: used,
+	}
+	if limit != nil {
+		quotaSpec.Limit = limit.GetValue()
+		quotaSpec.Source = &warrant.SubjectSpec{
+			ObjectType: limit.GetObjectType(),
+			ObjectId:   limit.GetObjectId(),
+		}
+	}
+
+	if quotaSpec.Limit > quotaSpec.Used {
+		quotaSpec.Remaining = quotaSpec.Limit - quotaSpec.Used
+	}
+
+	return &quotaSpec
+}
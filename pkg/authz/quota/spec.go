@@ -0,0 +1,59 @@
+package authz
+
+import (
+	"time"
+
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+)
+
+// LimitSpec type for a numeric limit, such as a number of seats, defined on a
+// pricing tier or feature, or overridden for a tenant or user
+type LimitSpec struct {
+	Name      string    `json:"name"`
+	Value     int64     `json:"value"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type SetLimitSpec struct {
+	Value *int64 `json:"value" validate:"required,min=0"`
+}
+
+// QuotaSpec type for the effective limit of a tenant or user and how much of
+// it has been used. Source is the object the limit is defined on, or null if
+// the tenant or user has no limit with the given name.
+type QuotaSpec struct {
+	Name      string               `json:"name"`
+	Limit     int64                `json:"limit"`
+	Used      int64                `json:"used"`
+	Remaining int64                `json:"remaining"`
+	Source    *warrant.SubjectSpec `json:"source"`
+}
+
+// ObjectLimitSpec type for a limit along with the pricing tier, feature,
+// tenant, or user it's defined on, e.g. for snapshots
+type ObjectLimitSpec struct {
+	ObjectType string `json:"objectType" validate:"required,valid_object_type"`
+	ObjectId   string `json:"objectId" validate:"required,valid_object_id"`
+	Name       string `json:"name" validate:"required"`
+	Value      int64  `json:"value" validate:"min=0"`
+}
+
+// ObjectUsageSpec type for a tenant or user's usage of a quota, e.g. for snapshots
+type ObjectUsageSpec struct {
+	ObjectType string `json:"objectType" validate:"required,valid_object_type"`
+	ObjectId   string `json:"objectId" validate:"required,valid_object_id"`
+	Name       string `json:"name" validate:"required"`
+	Used       int64  `json:"used" validate:"min=0"`
+}
+
+type UsageAmountSpec struct {
+	Amount int64 `json:"amount" validate:"required,min=1"`
+}
+
+// ConsumeResultSpec type for the result of consuming an amount of a quota.
+// Usage is only incremented if Allowed is true.
+type ConsumeResultSpec struct {
+	QuotaSpec
+	Amount  int64 `json:"amount"`
+	Allowed bool  `json:"allowed"`
+}
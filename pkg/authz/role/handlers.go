@@ -34,6 +34,13 @@ func (svc RoleService) Routes() []service.Route {
 			Handler: service.NewRouteHandler(svc, GetHandler),
 		},
 
+		// restore
+		{
+			Pattern: "/v1/roles/{roleId}/restore",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, RestoreHandler),
+		},
+
 		// update
 		{
 			Pattern: "/v1/roles/{roleId}",
@@ -52,6 +59,24 @@ func (svc RoleService) Routes() []service.Route {
 			Method:  "DELETE",
 			Handler: service.NewRouteHandler(svc, DeleteHandler),
 		},
+
+		// user roles
+		{
+			Pattern:           "/v1/users/{userId}/roles",
+			Method:            "GET",
+			Handler:           service.NewRouteHandler(svc, ListForUserHandler),
+			EnableSessionAuth: true,
+		},
+		{
+			Pattern: "/v1/users/{userId}/roles",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AssignToUserHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/roles/{roleId}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveFromUserHandler),
+		},
 	}
 }
 
@@ -94,6 +119,7 @@ func ListHandler(svc RoleService, w http.ResponseWriter, r *http.Request) error
 		return err
 	}
 
+	middleware.SetCursorHeaders(w, listParams, roles)
 	service.SendJSONResponse(w, roles)
 	return nil
 }
@@ -133,3 +159,80 @@ func DeleteHandler(svc RoleService, w http.ResponseWriter, r *http.Request) erro
 
 	return nil
 }
+
+func RestoreHandler(svc RoleService, w http.ResponseWriter, r *http.Request) error {
+	roleId := mux.Vars(r)["roleId"]
+	if roleId == "" {
+		return service.NewMissingRequiredParameterError("roleId")
+	}
+
+	restoredRole, err := svc.RestoreByRoleId(r.Context(), roleId)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, restoredRole)
+	return nil
+}
+
+func ListForUserHandler(svc RoleService, w http.ResponseWriter, r *http.Request) error {
+	userId, err := url.QueryUnescape(mux.Vars(r)["userId"])
+	if err != nil {
+		return service.NewInvalidParameterError("userId", "")
+	}
+
+	tenantId, err := service.ScopeToSession(service.GetAuthInfoFromRequestContext(r.Context()), userId, r.URL.Query().Get("tenantId"))
+	if err != nil {
+		return err
+	}
+
+	assignedRoles, err := svc.ListForUser(r.Context(), userId, tenantId)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedRoles)
+	return nil
+}
+
+func AssignToUserHandler(svc RoleService, w http.ResponseWriter, r *http.Request) error {
+	var assignSpec AssignRoleSpec
+	err := service.ParseJSONBody(r.Body, &assignSpec)
+	if err != nil {
+		return err
+	}
+
+	userId, err := url.QueryUnescape(mux.Vars(r)["userId"])
+	if err != nil {
+		return service.NewInvalidParameterError("userId", "")
+	}
+
+	assignedRole, err := svc.AssignToUser(r.Context(), userId, assignSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, assignedRole)
+	return nil
+}
+
+func RemoveFromUserHandler(svc RoleService, w http.ResponseWriter, r *http.Request) error {
+	userId, err := url.QueryUnescape(mux.Vars(r)["userId"])
+	if err != nil {
+		return service.NewInvalidParameterError("userId", "")
+	}
+
+	roleId, err := url.QueryUnescape(mux.Vars(r)["roleId"])
+	if err != nil {
+		return service.NewInvalidParameterError("roleId", "")
+	}
+
+	err = svc.RemoveFromUser(r.Context(), userId, roleId, r.URL.Query().Get("tenantId"))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
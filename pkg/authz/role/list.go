@@ -3,6 +3,8 @@ package authz
 import (
 	"fmt"
 	"time"
+
+	"github.com/warrant-dev/warrant/pkg/middleware"
 )
 
 type RoleListParamParser struct{}
@@ -41,3 +43,18 @@ func (parser RoleListParamParser) ParseValue(val string, sortBy string) (interfa
 		return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
 	}
 }
+
+func (spec RoleSpec) ToCursor(sortBy string) middleware.Cursor {
+	switch sortBy {
+	case "createdAt":
+		return middleware.NewCursor(spec.RoleId, spec.CreatedAt)
+	case "name":
+		if !spec.Name.Valid {
+			return middleware.NewCursor(spec.RoleId, middleware.NullValue{})
+		}
+
+		return middleware.NewCursor(spec.RoleId, spec.Name.String)
+	default:
+		return middleware.NewCursor(spec.RoleId, nil)
+	}
+}
@@ -17,18 +17,20 @@ type Model interface {
 	GetCreatedAt() time.Time
 	GetUpdatedAt() time.Time
 	GetDeletedAt() database.NullTime
+	GetDeletionBatchId() string
 	ToRoleSpec() *RoleSpec
 }
 
 type Role struct {
-	ID          int64               `mysql:"id" postgres:"id"`
-	ObjectId    int64               `mysql:"objectId" postgres:"object_id"`
-	RoleId      string              `mysql:"roleId" postgres:"role_id"`
-	Name        database.NullString `mysql:"name" postgres:"name"`
-	Description database.NullString `mysql:"description" postgres:"description"`
-	CreatedAt   time.Time           `mysql:"createdAt" postgres:"created_at"`
-	UpdatedAt   time.Time           `mysql:"updatedAt" postgres:"updated_at"`
-	DeletedAt   database.NullTime   `mysql:"deletedAt" postgres:"deleted_at"`
+	ID              int64               `mysql:"id" postgres:"id"`
+	ObjectId        int64               `mysql:"objectId" postgres:"object_id"`
+	RoleId          string              `mysql:"roleId" postgres:"role_id"`
+	Name            database.NullString `mysql:"name" postgres:"name"`
+	Description     database.NullString `mysql:"description" postgres:"description"`
+	CreatedAt       time.Time           `mysql:"createdAt" postgres:"created_at"`
+	UpdatedAt       time.Time           `mysql:"updatedAt" postgres:"updated_at"`
+	DeletedAt       database.NullTime   `mysql:"deletedAt" postgres:"deleted_at"`
+	DeletionBatchId database.NullString `mysql:"deletionBatchId" postgres:"deletion_batch_id"`
 }
 
 func (role Role) GetID() int64 {
@@ -71,6 +73,10 @@ func (role Role) GetDeletedAt() database.NullTime {
 	return role.DeletedAt
 }
 
+func (role Role) GetDeletionBatchId() string {
+	return role.DeletionBatchId.String
+}
+
 func (role Role) ToRoleSpec() *RoleSpec {
 	return &RoleSpec{
 		RoleId:      role.RoleId,
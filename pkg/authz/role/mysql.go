@@ -37,7 +37,8 @@ func (repo MySQLRepository) Create(ctx context.Context, role Model) (int64, erro
 				name = ?,
 				description = ?,
 				createdAt = CURRENT_TIMESTAMP(6),
-				deletedAt = NULL
+				deletedAt = NULL,
+				deletionBatchId = NULL
 		`,
 		role.GetObjectId(),
 		role.GetRoleId(),
@@ -131,21 +132,9 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 
 	if listParams.AfterId != "" {
 		if listParams.AfterValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s > ? OR (roleId > ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s < ? OR (roleId < ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(listParams.SortBy, "roleId", listParams.SortOrder, listParams.AfterValue, listParams.AfterId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND roleId > ?", query)
@@ -159,37 +148,26 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 
 	if listParams.BeforeId != "" {
 		if listParams.BeforeValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s < ? OR (roleId < ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s > ? OR (roleId > ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(listParams.SortBy, "roleId", listParams.SortOrder.Reverse(), listParams.BeforeValue, listParams.BeforeId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND roleId < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND roleId > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	if listParams.SortBy != "roleId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s, roleId %s LIMIT ?", query, listParams.SortBy, listParams.SortOrder, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s, roleId %s LIMIT ?", query, listParams.SortBy, sortOrder, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY roleId %s LIMIT ?", query, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY roleId %s LIMIT ?", query, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -212,6 +190,8 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 		models = append(models, &roles[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -238,18 +218,20 @@ func (repo MySQLRepository) UpdateByRoleId(ctx context.Context, roleId string, m
 	return nil
 }
 
-func (repo MySQLRepository) DeleteByRoleId(ctx context.Context, roleId string) error {
+func (repo MySQLRepository) DeleteByRoleId(ctx context.Context, roleId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE role
 			SET
-				deletedAt = ?
+				deletedAt = ?,
+				deletionBatchId = ?
 			WHERE
 				roleId = ? AND
 				deletedAt IS NULL
 		`,
 		time.Now().UTC(),
+		deletionBatchId,
 		roleId,
 	)
 	if err != nil {
@@ -263,3 +245,53 @@ func (repo MySQLRepository) DeleteByRoleId(ctx context.Context, roleId string) e
 
 	return nil
 }
+
+func (repo MySQLRepository) GetDeletedByRoleId(ctx context.Context, roleId string) (Model, error) {
+	var role Role
+	err := repo.DB.GetContext(
+		ctx,
+		&role,
+		`
+			SELECT id, objectId, roleId, name, description, createdAt, updatedAt, deletedAt, deletionBatchId
+			FROM role
+			WHERE
+				roleId = ? AND
+				deletedAt IS NOT NULL AND
+				deletionBatchId IS NOT NULL
+		`,
+		roleId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, service.NewRecordNotFoundError("Role", roleId)
+		default:
+			return nil, service.NewInternalError(fmt.Sprintf("Unable to get role %s from mysql", roleId))
+		}
+	}
+
+	return &role, nil
+}
+
+func (repo MySQLRepository) RestoreByRoleId(ctx context.Context, roleId string, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE role
+			SET
+				deletedAt = NULL,
+				deletionBatchId = NULL
+			WHERE
+				roleId = ? AND
+				deletionBatchId = ? AND
+				deletedAt IS NOT NULL
+		`,
+		roleId,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error restoring role %s", roleId))
+	}
+
+	return nil
+}
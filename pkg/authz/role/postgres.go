@@ -40,7 +40,8 @@ func (repo PostgresRepository) Create(ctx context.Context, model Model) (int64,
 				name = ?,
 				description = ?,
 				created_at = CURRENT_TIMESTAMP(6),
-				deleted_at = NULL
+				deleted_at = NULL,
+				deletion_batch_id = NULL
 			RETURNING id
 		`,
 		model.GetObjectId(),
@@ -131,21 +132,9 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 	sortBy := regexp.MustCompile("([A-Z])").ReplaceAllString(listParams.SortBy, `_$1`)
 	if listParams.AfterId != "" {
 		if listParams.AfterValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s > ? OR (role_id > ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s < ? OR (role_id < ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(sortBy, "role_id", listParams.SortOrder, listParams.AfterValue, listParams.AfterId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND role_id > ?", query)
@@ -159,42 +148,31 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 
 	if listParams.BeforeId != "" {
 		if listParams.BeforeValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s < ? OR (role_id < ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s > ? OR (role_id > ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(sortBy, "role_id", listParams.SortOrder.Reverse(), listParams.BeforeValue, listParams.BeforeId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND role_id < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND role_id > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	nullSortClause := "NULLS LAST"
-	if listParams.SortOrder == middleware.SortOrderAsc {
+	if sortOrder == middleware.SortOrderAsc {
 		nullSortClause = "NULLS FIRST"
 	}
 
 	if listParams.SortBy != "roleId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s %s, role_id %s LIMIT ?", query, sortBy, listParams.SortOrder, nullSortClause, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s %s, role_id %s LIMIT ?", query, sortBy, sortOrder, nullSortClause, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY role_id %s %s LIMIT ?", query, listParams.SortOrder, nullSortClause)
+		query = fmt.Sprintf("%s ORDER BY role_id %s %s LIMIT ?", query, sortOrder, nullSortClause)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -217,6 +195,8 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 		models = append(models, &roles[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -243,18 +223,20 @@ func (repo PostgresRepository) UpdateByRoleId(ctx context.Context, roleId string
 	return nil
 }
 
-func (repo PostgresRepository) DeleteByRoleId(ctx context.Context, roleId string) error {
+func (repo PostgresRepository) DeleteByRoleId(ctx context.Context, roleId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE role
 			SET
-				deleted_at = ?
+				deleted_at = ?,
+				deletion_batch_id = ?
 			WHERE
 				role_id = ? AND
 				deleted_at IS NULL
 		`,
 		time.Now().UTC(),
+		deletionBatchId,
 		roleId,
 	)
 	if err != nil {
@@ -268,3 +250,53 @@ func (repo PostgresRepository) DeleteByRoleId(ctx context.Context, roleId string
 
 	return nil
 }
+
+func (repo PostgresRepository) GetDeletedByRoleId(ctx context.Context, roleId string) (Model, error) {
+	var role Role
+	err := repo.DB.GetContext(
+		ctx,
+		&role,
+		`
+			SELECT id, object_id, role_id, name, description, created_at, updated_at, deleted_at, deletion_batch_id
+			FROM role
+			WHERE
+				role_id = ? AND
+				deleted_at IS NOT NULL AND
+				deletion_batch_id IS NOT NULL
+		`,
+		roleId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, service.NewRecordNotFoundError("Role", roleId)
+		default:
+			return nil, service.NewInternalError(fmt.Sprintf("Unable to get role %s from mysql", roleId))
+		}
+	}
+
+	return &role, nil
+}
+
+func (repo PostgresRepository) RestoreByRoleId(ctx context.Context, roleId string, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE role
+			SET
+				deleted_at = NULL,
+				deletion_batch_id = NULL
+			WHERE
+				role_id = ? AND
+				deletion_batch_id = ? AND
+				deleted_at IS NOT NULL
+		`,
+		roleId,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error restoring role %s", roleId))
+	}
+
+	return nil
+}
@@ -14,7 +14,9 @@ type RoleRepository interface {
 	GetByRoleId(ctx context.Context, roleId string) (Model, error)
 	List(ctx context.Context, listParams middleware.ListParams) ([]Model, error)
 	UpdateByRoleId(ctx context.Context, roleId string, role Model) error
-	DeleteByRoleId(ctx context.Context, roleId string) error
+	DeleteByRoleId(ctx context.Context, roleId string, deletionBatchId string) error
+	GetDeletedByRoleId(ctx context.Context, roleId string) (Model, error)
+	RestoreByRoleId(ctx context.Context, roleId string, deletionBatchId string) error
 }
 
 func NewRepository(db database.Database) (RoleRepository, error) {
@@ -2,9 +2,15 @@ package authz
 
 import (
 	"context"
+	"sort"
 
+	"github.com/google/uuid"
+	check "github.com/warrant-dev/warrant/pkg/authz/check"
 	object "github.com/warrant-dev/warrant/pkg/authz/object"
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	tenant "github.com/warrant-dev/warrant/pkg/authz/tenant"
+	user "github.com/warrant-dev/warrant/pkg/authz/user"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
 	"github.com/warrant-dev/warrant/pkg/event"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
@@ -14,17 +20,23 @@ const ResourceTypeRole = "role"
 
 type RoleService struct {
 	service.BaseService
-	repo      RoleRepository
-	eventSvc  event.EventService
-	objectSvc object.ObjectService
+	repo       RoleRepository
+	eventSvc   event.EventService
+	objectSvc  object.ObjectService
+	userSvc    user.UserService
+	tenantSvc  tenant.TenantService
+	warrantSvc warrant.WarrantService
 }
 
-func NewService(env service.Env, repo RoleRepository, eventSvc event.EventService, objectSvc object.ObjectService) RoleService {
+func NewService(env service.Env, repo RoleRepository, eventSvc event.EventService, objectSvc object.ObjectService, userSvc user.UserService, tenantSvc tenant.TenantService, warrantSvc warrant.WarrantService) RoleService {
 	return RoleService{
 		BaseService: service.NewBaseService(env),
 		repo:        repo,
 		eventSvc:    eventSvc,
 		objectSvc:   objectSvc,
+		userSvc:     userSvc,
+		tenantSvc:   tenantSvc,
+		warrantSvc:  warrantSvc,
 	}
 }
 
@@ -130,12 +142,15 @@ func (svc RoleService) DeleteByRoleId(ctx context.Context, roleId string) error
 			return err
 		}
 
-		err = roleRepository.DeleteByRoleId(txCtx, roleId)
+		// NOTE: the role, its object, and its warrants are tagged with the
+		// same deletion batch id so they can be restored together
+		deletionBatchId := uuid.New().String()
+		err = roleRepository.DeleteByRoleId(txCtx, roleId, deletionBatchId)
 		if err != nil {
 			return err
 		}
 
-		err = svc.objectSvc.DeleteByObjectTypeAndId(txCtx, objecttype.ObjectTypeRole, roleId)
+		err = svc.objectSvc.DeleteByObjectTypeAndIdInBatch(txCtx, objecttype.ObjectTypeRole, roleId, deletionBatchId)
 		if err != nil {
 			return err
 		}
@@ -146,3 +161,173 @@ func (svc RoleService) DeleteByRoleId(ctx context.Context, roleId string) error
 
 	return err
 }
+
+// RestoreByRoleId restores a deleted role along with its object and the
+// warrants that were deleted with it. Warrants deleted separately beforehand
+// are not restored.
+func (svc RoleService) RestoreByRoleId(ctx context.Context, roleId string) (*RoleSpec, error) {
+	var restoredRole Model
+	err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		roleRepository, err := NewRepository(svc.Env().DB())
+		if err != nil {
+			return err
+		}
+
+		deletedRole, err := roleRepository.GetDeletedByRoleId(txCtx, roleId)
+		if err != nil {
+			return err
+		}
+
+		err = svc.objectSvc.ValidateRestorable(objecttype.ObjectTypeRole, roleId, deletedRole.GetDeletedAt())
+		if err != nil {
+			return err
+		}
+
+		deletionBatchId := deletedRole.GetDeletionBatchId()
+		err = roleRepository.RestoreByRoleId(txCtx, roleId, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		err = svc.objectSvc.RestoreByObjectTypeAndId(txCtx, objecttype.ObjectTypeRole, roleId, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		restoredRole, err = roleRepository.GetByRoleId(txCtx, roleId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restoredRoleSpec := restoredRole.ToRoleSpec()
+	svc.eventSvc.TrackResourceRestored(ctx, ResourceTypeRole, roleId, restoredRoleSpec)
+	return restoredRoleSpec, nil
+}
+
+// ListForUser returns the roles assigned to a user, sorted by roleId. If
+// tenantId is set, only the roles that apply within the tenant are returned.
+func (svc RoleService) ListForUser(ctx context.Context, userId string, tenantId string) ([]AssignedRoleSpec, error) {
+	_, err := svc.userSvc.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	warrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+		ObjectType: objecttype.ObjectTypeRole,
+		Relation:   objecttype.RelationMember,
+		Subject: &warrant.SubjectSpec{
+			ObjectType: objecttype.ObjectTypeUser,
+			ObjectId:   userId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	assignedRoleSpecs := make([]AssignedRoleSpec, 0)
+	for _, warrantSpec := range warrantSpecs {
+		assignmentTenantId, isAssignment := AssignmentTenantId(warrantSpec)
+		if !isAssignment || (tenantId != "" && assignmentTenantId != "" && assignmentTenantId != tenantId) {
+			continue
+		}
+
+		// NOTE: warrants don't require their object to exist, so assignments of roles that were never created are skipped
+		roleSpec, err := svc.GetByRoleId(ctx, warrantSpec.ObjectId)
+		if err != nil {
+			if _, ok := err.(*service.RecordNotFoundError); ok {
+				continue
+			}
+
+			return nil, err
+		}
+
+		assignedRoleSpecs = append(assignedRoleSpecs, AssignedRoleSpec{
+			RoleId:      roleSpec.RoleId,
+			Name:        roleSpec.Name,
+			Description: roleSpec.Description,
+			TenantId:    assignmentTenantId,
+		})
+	}
+
+	sort.Slice(assignedRoleSpecs, func(i, j int) bool {
+		if assignedRoleSpecs[i].RoleId != assignedRoleSpecs[j].RoleId {
+			return assignedRoleSpecs[i].RoleId < assignedRoleSpecs[j].RoleId
+		}
+
+		return assignedRoleSpecs[i].TenantId < assignedRoleSpecs[j].TenantId
+	})
+	return assignedRoleSpecs, nil
+}
+
+// AssignToUser makes a user a member of a role, only within the given tenant
+// if the assignment has a tenantId
+func (svc RoleService) AssignToUser(ctx context.Context, userId string, assignSpec AssignRoleSpec) (*AssignedRoleSpec, error) {
+	_, err := svc.userSvc.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	roleSpec, err := svc.GetByRoleId(ctx, assignSpec.RoleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignSpec.TenantId != "" {
+		_, err = svc.tenantSvc.GetByTenantId(ctx, assignSpec.TenantId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = svc.warrantSvc.Create(ctx, userRoleWarrantSpec(userId, assignSpec.RoleId, assignSpec.TenantId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AssignedRoleSpec{
+		RoleId:      roleSpec.RoleId,
+		Name:        roleSpec.Name,
+		Description: roleSpec.Description,
+		TenantId:    assignSpec.TenantId,
+	}, nil
+}
+
+// RemoveFromUser removes a user's assignment to a role, either within the
+// given tenant or, if tenantId is empty, the assignment that applies in all tenants
+func (svc RoleService) RemoveFromUser(ctx context.Context, userId string, roleId string, tenantId string) error {
+	return svc.warrantSvc.Delete(ctx, userRoleWarrantSpec(userId, roleId, tenantId))
+}
+
+func userRoleWarrantSpec(userId string, roleId string, tenantId string) warrant.WarrantSpec {
+	return warrant.WarrantSpec{
+		ObjectType: objecttype.ObjectTypeRole,
+		ObjectId:   roleId,
+		Relation:   objecttype.RelationMember,
+		Subject: &warrant.SubjectSpec{
+			ObjectType: objecttype.ObjectTypeUser,
+			ObjectId:   userId,
+		},
+		Context: check.TenantContext(tenantId),
+	}
+}
+
+// AssignmentTenantId returns the tenant a warrant assigning a role or
+// permission is scoped to, or an empty string if it applies in all tenants.
+// Warrants with usersets or any other context aren't assignments.
+func AssignmentTenantId(warrantSpec *warrant.WarrantSpec) (tenantId string, isAssignment bool) {
+	if warrantSpec.Subject.Relation != "" {
+		return "", false
+	}
+
+	switch len(warrantSpec.Context) {
+	case 0:
+		return "", true
+	case 1:
+		tenantId, isAssignment = warrantSpec.Context[check.ContextTenant]
+		return tenantId, isAssignment
+	default:
+		return "", false
+	}
+}
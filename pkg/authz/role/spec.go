@@ -37,3 +37,18 @@ type UpdateRoleSpec struct {
 	Name        database.NullString `json:"name"`
 	Description database.NullString `json:"description"`
 }
+
+// AssignRoleSpec type for assigning a role to a user, optionally only within a tenant
+type AssignRoleSpec struct {
+	RoleId   string `json:"roleId" validate:"required"`
+	TenantId string `json:"tenantId,omitempty"`
+}
+
+// AssignedRoleSpec type for a role assigned to a user. The role only applies
+// within the tenant if TenantId is set.
+type AssignedRoleSpec struct {
+	RoleId      string              `json:"roleId"`
+	Name        database.NullString `json:"name"`
+	Description database.NullString `json:"description"`
+	TenantId    string              `json:"tenantId,omitempty"`
+}
@@ -0,0 +1,309 @@
+package authz
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// maxNDJSONLineSize is the max size of a single record in an NDJSON snapshot
+const maxNDJSONLineSize = 16 * 1024 * 1024
+
+// snapshotWriter writes the records of a snapshot as they are exported
+type snapshotWriter interface {
+	WriteHeader(header HeaderSpec) error
+	BeginSection(recordType string) error
+	Write(recordType string, record interface{}) error
+	Close() error
+}
+
+// snapshotReader reads the records of a snapshot one at a time. Next returns
+// io.EOF after the last record.
+type snapshotReader interface {
+	Header() (*HeaderSpec, error)
+	Next() (*RecordSpec, error)
+}
+
+func newSnapshotWriter(format string, w io.Writer) (snapshotWriter, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("invalid snapshot format %s", format)
+	}
+}
+
+func newSnapshotReader(format string, r io.Reader) (snapshotReader, error) {
+	switch format {
+	case FormatJSON:
+		return &jsonReader{decoder: json.NewDecoder(r)}, nil
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("invalid snapshot format %s", format)
+	}
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (writer *ndjsonWriter) WriteHeader(header HeaderSpec) error {
+	return writer.Write(RecordTypeHeader, header)
+}
+
+func (writer *ndjsonWriter) BeginSection(recordType string) error {
+	return nil
+}
+
+func (writer *ndjsonWriter) Write(recordType string, record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling snapshot record")
+	}
+
+	return writer.encoder.Encode(RecordSpec{
+		Type: recordType,
+		Data: data,
+	})
+}
+
+func (writer *ndjsonWriter) Close() error {
+	return nil
+}
+
+// jsonWriter writes a single JSON object with the header fields followed by
+// an array of records per record type, one record at a time
+type jsonWriter struct {
+	w            io.Writer
+	inSection    bool
+	firstInArray bool
+}
+
+func (writer *jsonWriter) WriteHeader(header HeaderSpec) error {
+	headerJson, err := json.Marshal(header)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling snapshot header")
+	}
+
+	// NOTE: strip the closing brace so the record arrays are written into the same object
+	_, err = writer.w.Write(headerJson[:len(headerJson)-1])
+	return err
+}
+
+func (writer *jsonWriter) BeginSection(recordType string) error {
+	prefix := ","
+	if writer.inSection {
+		prefix = "],"
+	}
+
+	_, err := fmt.Fprintf(writer.w, "%s\n%q:[", prefix, jsonSections[recordType])
+	writer.inSection = true
+	writer.firstInArray = true
+	return err
+}
+
+func (writer *jsonWriter) Write(recordType string, record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling snapshot record")
+	}
+
+	if !writer.firstInArray {
+		_, err = writer.w.Write([]byte(","))
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.firstInArray = false
+	_, err = fmt.Fprintf(writer.w, "\n%s", data)
+	return err
+}
+
+func (writer *jsonWriter) Close() error {
+	suffix := "}\n"
+	if writer.inSection {
+		suffix = "]}\n"
+	}
+
+	_, err := writer.w.Write([]byte(suffix))
+	return err
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (reader *ndjsonReader) Header() (*HeaderSpec, error) {
+	record, err := reader.Next()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("snapshot is empty")
+		}
+
+		return nil, err
+	}
+
+	if record.Type != RecordTypeHeader {
+		return nil, fmt.Errorf("line %d: expected a %s record", reader.line, RecordTypeHeader)
+	}
+
+	var header HeaderSpec
+	err = json.Unmarshal(record.Data, &header)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: invalid %s record", reader.line, RecordTypeHeader)
+	}
+
+	return &header, nil
+}
+
+func (reader *ndjsonReader) Next() (*RecordSpec, error) {
+	for reader.scanner.Scan() {
+		reader.line++
+		line := reader.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var record RecordSpec
+		err := json.Unmarshal(line, &record)
+		if err != nil || record.Type == "" {
+			return nil, fmt.Errorf("line %d: invalid record", reader.line)
+		}
+
+		return &record, nil
+	}
+
+	err := reader.scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %s", reader.line+1, err.Error())
+	}
+
+	return nil, io.EOF
+}
+
+// jsonReader reads a JSON snapshot token by token so that only one record
+// is held in memory at a time
+type jsonReader struct {
+	decoder    *json.Decoder
+	header     HeaderSpec
+	recordType string
+}
+
+func (reader *jsonReader) Header() (*HeaderSpec, error) {
+	err := reader.expectDelim('{')
+	if err != nil {
+		return nil, err
+	}
+
+	for reader.decoder.More() {
+		key, err := reader.nextKey()
+		if err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "version":
+			err = reader.decoder.Decode(&reader.header.Version)
+		case "exportedAt":
+			err = reader.decoder.Decode(&reader.header.ExportedAt)
+		default:
+			// NOTE: the header fields must precede the record arrays
+			err = reader.beginSection(key)
+			if err != nil {
+				return nil, err
+			}
+
+			return &reader.header, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot %s", key)
+		}
+	}
+
+	return &reader.header, nil
+}
+
+func (reader *jsonReader) Next() (*RecordSpec, error) {
+	for {
+		if reader.recordType != "" {
+			if reader.decoder.More() {
+				var data json.RawMessage
+				err := reader.decoder.Decode(&data)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s record", reader.recordType)
+				}
+
+				return &RecordSpec{
+					Type: reader.recordType,
+					Data: data,
+				}, nil
+			}
+
+			err := reader.expectDelim(']')
+			if err != nil {
+				return nil, err
+			}
+
+			reader.recordType = ""
+		}
+
+		if !reader.decoder.More() {
+			return nil, io.EOF
+		}
+
+		key, err := reader.nextKey()
+		if err != nil {
+			return nil, err
+		}
+
+		err = reader.beginSection(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (reader *jsonReader) beginSection(key string) error {
+	for recordType, section := range jsonSections {
+		if section == key {
+			reader.recordType = recordType
+			return reader.expectDelim('[')
+		}
+	}
+
+	return fmt.Errorf("unknown snapshot key %s", key)
+}
+
+func (reader *jsonReader) nextKey() (string, error) {
+	token, err := reader.decoder.Token()
+	if err != nil {
+		return "", fmt.Errorf("invalid snapshot")
+	}
+
+	key, ok := token.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid snapshot")
+	}
+
+	return key, nil
+}
+
+func (reader *jsonReader) expectDelim(delim json.Delim) error {
+	token, err := reader.decoder.Token()
+	if err != nil || token != delim {
+		return fmt.Errorf("invalid snapshot, expected '%s'", delim)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+func (svc SnapshotService) Routes() []service.Route {
+	return []service.Route{
+		// export
+		{
+			Pattern: "/v1/export",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ExportHandler),
+		},
+
+		// import
+		{
+			Pattern: "/v1/import",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, ImportHandler),
+		},
+	}
+}
+
+func ExportHandler(svc SnapshotService, w http.ResponseWriter, r *http.Request) error {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = FormatJSON
+	}
+
+	contentType := "application/json"
+	switch format {
+	case FormatJSON:
+	case FormatNDJSON:
+		contentType = "application/x-ndjson"
+	default:
+		return service.NewInvalidParameterError("format", "must be one of json, ndjson")
+	}
+
+	w.Header().Set("Content-type", contentType)
+	w.WriteHeader(http.StatusOK)
+	err := svc.Export(r.Context(), format, w)
+	if err != nil {
+		// NOTE: the response has already started, so the snapshot is left truncated
+		log.Err(err).Msg("error exporting snapshot")
+	}
+
+	return nil
+}
+
+func ImportHandler(svc SnapshotService, w http.ResponseWriter, r *http.Request) error {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = FormatJSON
+	}
+
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = ModeMerge
+	}
+
+	importResult, err := svc.Import(r.Context(), format, mode, r.Body)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, importResult)
+	return nil
+}
@@ -0,0 +1,654 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"time"
+
+	feature "github.com/warrant-dev/warrant/pkg/authz/feature"
+	object "github.com/warrant-dev/warrant/pkg/authz/object"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	permission "github.com/warrant-dev/warrant/pkg/authz/permission"
+	pricingtier "github.com/warrant-dev/warrant/pkg/authz/pricingtier"
+	quota "github.com/warrant-dev/warrant/pkg/authz/quota"
+	role "github.com/warrant-dev/warrant/pkg/authz/role"
+	tenant "github.com/warrant-dev/warrant/pkg/authz/tenant"
+	user "github.com/warrant-dev/warrant/pkg/authz/user"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/middleware"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+const (
+	ExportBatchSize = 500
+	ImportBatchSize = 100
+)
+
+type SnapshotService struct {
+	service.BaseService
+	objectTypeSvc  objecttype.ObjectTypeService
+	objectSvc      object.ObjectService
+	userSvc        user.UserService
+	tenantSvc      tenant.TenantService
+	roleSvc        role.RoleService
+	permissionSvc  permission.PermissionService
+	featureSvc     feature.FeatureService
+	pricingTierSvc pricingtier.PricingTierService
+	warrantSvc     warrant.WarrantService
+	quotaSvc       quota.QuotaService
+}
+
+func NewService(env service.Env, objectTypeSvc objecttype.ObjectTypeService, objectSvc object.ObjectService, userSvc user.UserService, tenantSvc tenant.TenantService, roleSvc role.RoleService, permissionSvc permission.PermissionService, featureSvc feature.FeatureService, pricingTierSvc pricingtier.PricingTierService, warrantSvc warrant.WarrantService, quotaSvc quota.QuotaService) SnapshotService {
+	return SnapshotService{
+		BaseService:    service.NewBaseService(env),
+		objectTypeSvc:  objectTypeSvc,
+		objectSvc:      objectSvc,
+		userSvc:        userSvc,
+		tenantSvc:      tenantSvc,
+		roleSvc:        roleSvc,
+		permissionSvc:  permissionSvc,
+		featureSvc:     featureSvc,
+		pricingTierSvc: pricingTierSvc,
+		warrantSvc:     warrantSvc,
+		quotaSvc:       quotaSvc,
+	}
+}
+
+// Export writes a snapshot of the entire datastore to w in the given format.
+// Records are read and written in batches, so the snapshot is never held in memory.
+func (svc SnapshotService) Export(ctx context.Context, format string, w io.Writer) error {
+	writer, err := newSnapshotWriter(format, w)
+	if err != nil {
+		return service.NewInvalidParameterError("format", fmt.Sprintf("must be one of %s, %s", FormatJSON, FormatNDJSON))
+	}
+
+	err = writer.WriteHeader(HeaderSpec{
+		Version:    SnapshotVersion,
+		ExportedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, recordType := range recordTypes {
+		err = writer.BeginSection(recordType)
+		if err != nil {
+			return err
+		}
+
+		err = svc.forEach(ctx, recordType, func(record interface{}) error {
+			return writer.Write(recordType, record)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+// Import applies the snapshot read from r in batches of ImportBatchSize
+// records, each in its own transaction. Records that already exist are
+// updated if they differ from the snapshot, so importing the same snapshot
+// more than once is safe. In replace mode, records that are not in the
+// snapshot are deleted once all of its records have been applied.
+func (svc SnapshotService) Import(ctx context.Context, format string, mode string, r io.Reader) (*ImportResultSpec, error) {
+	if mode != ModeMerge && mode != ModeReplace {
+		return nil, service.NewInvalidParameterError("mode", fmt.Sprintf("must be one of %s, %s", ModeMerge, ModeReplace))
+	}
+
+	reader, err := newSnapshotReader(format, r)
+	if err != nil {
+		return nil, service.NewInvalidParameterError("format", fmt.Sprintf("must be one of %s, %s", FormatJSON, FormatNDJSON))
+	}
+
+	header, err := reader.Header()
+	if err != nil {
+		return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid snapshot: %s", err.Error()))
+	}
+
+	if header.Version != SnapshotVersion {
+		return nil, service.NewInvalidRequestError(fmt.Sprintf("Unsupported snapshot version %d. Expected version %d.", header.Version, SnapshotVersion))
+	}
+
+	result := ImportResultSpec{
+		Mode:   mode,
+		Counts: make(map[string]*ImportCountSpec),
+	}
+	for _, recordType := range recordTypes {
+		result.Counts[recordType] = &ImportCountSpec{}
+	}
+
+	importedKeys := make(map[string]map[string]bool)
+	for _, recordType := range recordTypes {
+		importedKeys[recordType] = make(map[string]bool)
+	}
+
+	done := false
+	for !done {
+		batch := make([]RecordSpec, 0, ImportBatchSize)
+		for len(batch) < ImportBatchSize {
+			record, err := reader.Next()
+			if err == io.EOF {
+				done = true
+				break
+			}
+
+			if err != nil {
+				return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid snapshot: %s", err.Error()))
+			}
+
+			if _, ok := result.Counts[record.Type]; !ok {
+				return nil, service.NewInvalidRequestError(fmt.Sprintf("Record %d has unknown type %s", result.Records+int64(len(batch))+1, record.Type))
+			}
+
+			batch = append(batch, *record)
+		}
+
+		err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+			for i, record := range batch {
+				recordNumber := result.Records + int64(i) + 1
+				key, outcome, err := svc.importRecord(txCtx, record)
+				if err != nil {
+					return recordError(recordNumber, record.Type, err)
+				}
+
+				importedKeys[record.Type][key] = true
+				switch outcome {
+				case outcomeCreated:
+					result.Counts[record.Type].Created++
+				case outcomeUpdated:
+					result.Counts[record.Type].Updated++
+				default:
+					result.Counts[record.Type].Unchanged++
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result.Records += int64(len(batch))
+	}
+
+	if mode == ModeReplace {
+		// NOTE: delete in reverse order so warrants are removed before the objects they reference
+		for i := len(recordTypes) - 1; i >= 0; i-- {
+			recordType := recordTypes[i]
+			deleted, err := svc.deleteAllExcept(ctx, recordType, importedKeys[recordType])
+			if err != nil {
+				return nil, err
+			}
+
+			result.Counts[recordType].Deleted = deleted
+		}
+	}
+
+	return &result, nil
+}
+
+const (
+	outcomeCreated = iota
+	outcomeUpdated
+	outcomeUnchanged
+)
+
+// importRecord creates or updates the given record and returns its key
+func (svc SnapshotService) importRecord(ctx context.Context, record RecordSpec) (string, int, error) {
+	switch record.Type {
+	case RecordTypeObjectType:
+		var spec objecttype.ObjectTypeSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		existing, err := svc.objectTypeSvc.GetByTypeId(ctx, spec.Type)
+		if err != nil {
+			return spec.Type, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.objectTypeSvc.Create(ctx, spec)
+				return err
+			})
+		}
+
+		if objectTypesEqual(*existing, spec) {
+			return spec.Type, outcomeUnchanged, nil
+		}
+
+		_, err = svc.objectTypeSvc.UpdateByTypeId(ctx, spec.Type, spec)
+		return spec.Type, outcomeUpdated, err
+	case RecordTypeUser:
+		var spec user.UserSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		existing, err := svc.userSvc.GetByUserId(ctx, spec.UserId)
+		if err != nil {
+			return spec.UserId, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.userSvc.Create(ctx, spec)
+				return err
+			})
+		}
+
+		if existing.Email == spec.Email {
+			return spec.UserId, outcomeUnchanged, nil
+		}
+
+		_, err = svc.userSvc.UpdateByUserId(ctx, spec.UserId, user.UpdateUserSpec{Email: spec.Email})
+		return spec.UserId, outcomeUpdated, err
+	case RecordTypeTenant:
+		var spec tenant.TenantSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		existing, err := svc.tenantSvc.GetByTenantId(ctx, spec.TenantId)
+		if err != nil {
+			return spec.TenantId, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.tenantSvc.Create(ctx, spec)
+				return err
+			})
+		}
+
+		if existing.Name == spec.Name {
+			return spec.TenantId, outcomeUnchanged, nil
+		}
+
+		_, err = svc.tenantSvc.UpdateByTenantId(ctx, spec.TenantId, tenant.UpdateTenantSpec{Name: spec.Name})
+		return spec.TenantId, outcomeUpdated, err
+	case RecordTypeRole:
+		var spec role.RoleSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		existing, err := svc.roleSvc.GetByRoleId(ctx, spec.RoleId)
+		if err != nil {
+			return spec.RoleId, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.roleSvc.Create(ctx, spec)
+				return err
+			})
+		}
+
+		if existing.Name == spec.Name && existing.Description == spec.Description {
+			return spec.RoleId, outcomeUnchanged, nil
+		}
+
+		_, err = svc.roleSvc.UpdateByRoleId(ctx, spec.RoleId, role.UpdateRoleSpec{Name: spec.Name, Description: spec.Description})
+		return spec.RoleId, outcomeUpdated, err
+	case RecordTypePermission:
+		var spec permission.PermissionSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		existing, err := svc.permissionSvc.GetByPermissionId(ctx, spec.PermissionId)
+		if err != nil {
+			return spec.PermissionId, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.permissionSvc.Create(ctx, spec)
+				return err
+			})
+		}
+
+		if existing.Name == spec.Name && existing.Description == spec.Description {
+			return spec.PermissionId, outcomeUnchanged, nil
+		}
+
+		_, err = svc.permissionSvc.UpdateByPermissionId(ctx, spec.PermissionId, permission.UpdatePermissionSpec{Name: spec.Name, Description: spec.Description})
+		return spec.PermissionId, outcomeUpdated, err
+	case RecordTypeFeature:
+		var spec feature.FeatureSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		existing, err := svc.featureSvc.GetByFeatureId(ctx, spec.FeatureId)
+		if err != nil {
+			return spec.FeatureId, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.featureSvc.Create(ctx, spec)
+				return err
+			})
+		}
+
+		if existing.Name == spec.Name && existing.Description == spec.Description {
+			return spec.FeatureId, outcomeUnchanged, nil
+		}
+
+		_, err = svc.featureSvc.UpdateByFeatureId(ctx, spec.FeatureId, feature.UpdateFeatureSpec{Name: spec.Name, Description: spec.Description})
+		return spec.FeatureId, outcomeUpdated, err
+	case RecordTypePricingTier:
+		var spec pricingtier.PricingTierSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		existing, err := svc.pricingTierSvc.GetByPricingTierId(ctx, spec.PricingTierId)
+		if err != nil {
+			return spec.PricingTierId, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.pricingTierSvc.Create(ctx, spec)
+				return err
+			})
+		}
+
+		if existing.Name == spec.Name && existing.Description == spec.Description {
+			return spec.PricingTierId, outcomeUnchanged, nil
+		}
+
+		_, err = svc.pricingTierSvc.UpdateByPricingTierId(ctx, spec.PricingTierId, pricingtier.UpdatePricingTierSpec{Name: spec.Name, Description: spec.Description})
+		return spec.PricingTierId, outcomeUpdated, err
+	case RecordTypeObject:
+		var spec object.ObjectSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		key := objectKey(spec)
+		existing, err := svc.objectSvc.GetByObjectId(ctx, spec.ObjectType, spec.ObjectId)
+		if err != nil {
+			return key, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.objectSvc.Create(ctx, spec)
+				return err
+			})
+		}
+
+		if reflect.DeepEqual(existing.Attributes, spec.Attributes) || (len(existing.Attributes) == 0 && len(spec.Attributes) == 0) {
+			return key, outcomeUnchanged, nil
+		}
+
+		_, err = svc.objectSvc.UpdateByObjectTypeAndId(ctx, spec.ObjectType, spec.ObjectId, object.UpdateObjectSpec{Attributes: spec.Attributes})
+		return key, outcomeUpdated, err
+	case RecordTypeWarrant:
+		var spec warrant.WarrantSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		key := spec.String()
+		_, err = svc.warrantSvc.Get(ctx, spec.ObjectType, spec.ObjectId, spec.Relation, spec.Subject.ObjectType, spec.Subject.ObjectId, spec.Subject.Relation, spec.Context)
+		if err != nil {
+			return key, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.warrantSvc.Create(ctx, spec)
+				return err
+			})
+		}
+
+		return key, outcomeUnchanged, nil
+	case RecordTypeLimit:
+		var spec quota.ObjectLimitSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		key := quotaKey(spec.ObjectType, spec.ObjectId, spec.Name)
+		limitObject := warrant.SubjectSpec{ObjectType: spec.ObjectType, ObjectId: spec.ObjectId}
+		existing, err := svc.quotaSvc.GetLimit(ctx, limitObject, spec.Name)
+		if err != nil {
+			return key, outcomeCreated, createIfNotFound(err, func() error {
+				_, err := svc.quotaSvc.SetLimit(ctx, limitObject, spec.Name, quota.SetLimitSpec{Value: &spec.Value})
+				return err
+			})
+		}
+
+		if existing.Value == spec.Value {
+			return key, outcomeUnchanged, nil
+		}
+
+		_, err = svc.quotaSvc.SetLimit(ctx, limitObject, spec.Name, quota.SetLimitSpec{Value: &spec.Value})
+		return key, outcomeUpdated, err
+	case RecordTypeUsage:
+		var spec quota.ObjectUsageSpec
+		err := parseRecord(record, &spec)
+		if err != nil {
+			return "", 0, err
+		}
+
+		key := quotaKey(spec.ObjectType, spec.ObjectId, spec.Name)
+		subject := warrant.SubjectSpec{ObjectType: spec.ObjectType, ObjectId: spec.ObjectId}
+		existing, err := svc.quotaSvc.GetUsage(ctx, subject, spec.Name)
+		if err != nil {
+			return key, outcomeCreated, createIfNotFound(err, func() error {
+				return svc.quotaSvc.SetUsage(ctx, subject, spec.Name, spec.Used)
+			})
+		}
+
+		if existing.Used == spec.Used {
+			return key, outcomeUnchanged, nil
+		}
+
+		return key, outcomeUpdated, svc.quotaSvc.SetUsage(ctx, subject, spec.Name, spec.Used)
+	default:
+		return "", 0, service.NewInvalidParameterError("type", fmt.Sprintf("unknown record type %s", record.Type))
+	}
+}
+
+// deleteAllExcept deletes all records of the given type whose keys are not in keep
+func (svc SnapshotService) deleteAllExcept(ctx context.Context, recordType string, keep map[string]bool) (int64, error) {
+	var deleted int64
+	err := svc.forEach(ctx, recordType, func(record interface{}) error {
+		var err error
+		switch spec := record.(type) {
+		case objecttype.ObjectTypeSpec:
+			if keep[spec.Type] {
+				return nil
+			}
+
+			err = svc.objectTypeSvc.DeleteByTypeId(ctx, spec.Type)
+		case user.UserSpec:
+			if keep[spec.UserId] {
+				return nil
+			}
+
+			err = svc.userSvc.DeleteByUserId(ctx, spec.UserId)
+		case tenant.TenantSpec:
+			if keep[spec.TenantId] {
+				return nil
+			}
+
+			err = svc.tenantSvc.DeleteByTenantId(ctx, spec.TenantId)
+		case role.RoleSpec:
+			if keep[spec.RoleId] {
+				return nil
+			}
+
+			err = svc.roleSvc.DeleteByRoleId(ctx, spec.RoleId)
+		case permission.PermissionSpec:
+			if keep[spec.PermissionId] {
+				return nil
+			}
+
+			err = svc.permissionSvc.DeleteByPermissionId(ctx, spec.PermissionId)
+		case feature.FeatureSpec:
+			if keep[spec.FeatureId] {
+				return nil
+			}
+
+			err = svc.featureSvc.DeleteByFeatureId(ctx, spec.FeatureId)
+		case pricingtier.PricingTierSpec:
+			if keep[spec.PricingTierId] {
+				return nil
+			}
+
+			err = svc.pricingTierSvc.DeleteByPricingTierId(ctx, spec.PricingTierId)
+		case object.ObjectSpec:
+			// NOTE: objects of built-in types are deleted along with their user, tenant, role, etc.
+			if keep[objectKey(spec)] || objecttype.IsBuiltIn(spec.ObjectType) {
+				return nil
+			}
+
+			err = svc.objectSvc.DeleteByObjectTypeAndId(ctx, spec.ObjectType, spec.ObjectId)
+		case *warrant.WarrantSpec:
+			if keep[spec.String()] {
+				return nil
+			}
+
+			err = svc.warrantSvc.Delete(ctx, *spec)
+		case quota.ObjectLimitSpec:
+			if keep[quotaKey(spec.ObjectType, spec.ObjectId, spec.Name)] {
+				return nil
+			}
+
+			err = svc.quotaSvc.DeleteLimit(ctx, warrant.SubjectSpec{ObjectType: spec.ObjectType, ObjectId: spec.ObjectId}, spec.Name)
+		case quota.ObjectUsageSpec:
+			if keep[quotaKey(spec.ObjectType, spec.ObjectId, spec.Name)] {
+				return nil
+			}
+
+			err = svc.quotaSvc.ResetUsage(ctx, warrant.SubjectSpec{ObjectType: spec.ObjectType, ObjectId: spec.ObjectId}, spec.Name)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		deleted++
+		return nil
+	})
+	if err != nil {
+		return deleted, err
+	}
+
+	return deleted, nil
+}
+
+// forEach calls fn with each record of the given type, reading ExportBatchSize records at a time
+func (svc SnapshotService) forEach(ctx context.Context, recordType string, fn func(record interface{}) error) error {
+	switch recordType {
+	case RecordTypeObjectType:
+		return forEachPage(objecttype.ObjectTypeListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]objecttype.ObjectTypeSpec, error) {
+			return svc.objectTypeSvc.List(ctx, listParams)
+		}, fn)
+	case RecordTypeUser:
+		return forEachPage(user.UserListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]user.UserSpec, error) {
+			return svc.userSvc.List(ctx, listParams)
+		}, fn)
+	case RecordTypeTenant:
+		return forEachPage(tenant.TenantListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]tenant.TenantSpec, error) {
+			return svc.tenantSvc.List(ctx, listParams)
+		}, fn)
+	case RecordTypeRole:
+		return forEachPage(role.RoleListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]role.RoleSpec, error) {
+			return svc.roleSvc.List(ctx, listParams)
+		}, fn)
+	case RecordTypePermission:
+		return forEachPage(permission.PermissionListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]permission.PermissionSpec, error) {
+			return svc.permissionSvc.List(ctx, listParams)
+		}, fn)
+	case RecordTypeFeature:
+		return forEachPage(feature.FeatureListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]feature.FeatureSpec, error) {
+			return svc.featureSvc.List(ctx, listParams)
+		}, fn)
+	case RecordTypePricingTier:
+		return forEachPage(pricingtier.PricingTierListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]pricingtier.PricingTierSpec, error) {
+			return svc.pricingTierSvc.List(ctx, listParams)
+		}, fn)
+	case RecordTypeObject:
+		// NOTE: objects are paged by (objectType, objectId) since objectIds are only unique per objectType
+		return forEachPage("objectType", func(listParams middleware.ListParams) ([]object.ObjectSpec, error) {
+			return svc.objectSvc.List(ctx, nil, listParams)
+		}, fn)
+	case RecordTypeWarrant:
+		return forEachPage(warrant.WarrantListParamParser{}.GetDefaultSortBy(), func(listParams middleware.ListParams) ([]*warrant.WarrantSpec, error) {
+			return svc.warrantSvc.List(ctx, &warrant.FilterOptions{}, listParams)
+		}, fn)
+	case RecordTypeLimit:
+		return svc.quotaSvc.ForEachLimit(ctx, ExportBatchSize, func(limitSpec quota.ObjectLimitSpec) error {
+			return fn(limitSpec)
+		})
+	case RecordTypeUsage:
+		return svc.quotaSvc.ForEachUsage(ctx, ExportBatchSize, func(usageSpec quota.ObjectUsageSpec) error {
+			return fn(usageSpec)
+		})
+	default:
+		return fmt.Errorf("unknown record type %s", recordType)
+	}
+}
+
+func forEachPage[T middleware.CursorSpec](sortBy string, list func(listParams middleware.ListParams) ([]T, error), fn func(record interface{}) error) error {
+	listParams := middleware.ListParams{
+		Page:      1,
+		Limit:     ExportBatchSize,
+		SortBy:    sortBy,
+		SortOrder: middleware.SortOrderAsc,
+	}
+	for {
+		results, err := list(listParams)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			err = fn(result)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(results) < listParams.Limit {
+			return nil
+		}
+
+		cursor := results[len(results)-1].ToCursor(sortBy)
+		listParams.AfterId = cursor.ID
+		listParams.AfterValue = cursor.Value
+	}
+}
+
+func parseRecord(record RecordSpec, spec interface{}) error {
+	err := service.ParseJSONBytes(record.Data, spec)
+	if err != nil {
+		return err
+	}
+
+	return service.ValidateStruct(spec)
+}
+
+// createIfNotFound calls create if err is a RecordNotFoundError and returns err otherwise
+func createIfNotFound(err error, create func() error) error {
+	switch err.(type) {
+	case *service.RecordNotFoundError:
+		return create()
+	default:
+		return err
+	}
+}
+
+// recordError prefixes client errors with the number and type of the record that caused them
+func recordError(recordNumber int64, recordType string, err error) error {
+	apiError, ok := err.(service.Error)
+	if !ok || apiError.GetStatus() >= http.StatusInternalServerError {
+		return err
+	}
+
+	return service.NewInvalidRequestError(fmt.Sprintf("Record %d (%s) could not be imported: %s", recordNumber, recordType, err.Error()))
+}
+
+func objectTypesEqual(a objecttype.ObjectTypeSpec, b objecttype.ObjectTypeSpec) bool {
+	// NOTE: compare serialized definitions so unset and empty fields are equal
+	aJson, aErr := json.Marshal(a)
+	bJson, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJson) == string(bJson)
+}
+
+func objectKey(spec object.ObjectSpec) string {
+	return fmt.Sprintf("%s:%s", spec.ObjectType, spec.ObjectId)
+}
+
+func quotaKey(objectType string, objectId string, name string) string {
+	return fmt.Sprintf("%s:%s#%s", objectType, objectId, name)
+}
@@ -0,0 +1,89 @@
+package authz
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SnapshotVersion is the version of the snapshot format written by exports.
+// Imports reject snapshots of any other version.
+const SnapshotVersion = 1
+
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+
+	ModeMerge   = "merge"
+	ModeReplace = "replace"
+)
+
+// NOTE: records are exported (and must be imported) in this order so that
+// object types exist before the objects and warrants that reference them, and
+// pricing tiers, features, tenants, and users exist before their limits and usage
+const (
+	RecordTypeHeader      = "header"
+	RecordTypeObjectType  = "object-type"
+	RecordTypeUser        = "user"
+	RecordTypeTenant      = "tenant"
+	RecordTypeRole        = "role"
+	RecordTypePermission  = "permission"
+	RecordTypeFeature     = "feature"
+	RecordTypePricingTier = "pricing-tier"
+	RecordTypeObject      = "object"
+	RecordTypeWarrant     = "warrant"
+	RecordTypeLimit       = "limit"
+	RecordTypeUsage       = "usage"
+)
+
+var recordTypes = []string{
+	RecordTypeObjectType,
+	RecordTypeUser,
+	RecordTypeTenant,
+	RecordTypeRole,
+	RecordTypePermission,
+	RecordTypeFeature,
+	RecordTypePricingTier,
+	RecordTypeObject,
+	RecordTypeWarrant,
+	RecordTypeLimit,
+	RecordTypeUsage,
+}
+
+// jsonSections maps each record type to the key of its array in a JSON snapshot
+var jsonSections = map[string]string{
+	RecordTypeObjectType:  "objectTypes",
+	RecordTypeUser:        "users",
+	RecordTypeTenant:      "tenants",
+	RecordTypeRole:        "roles",
+	RecordTypePermission:  "permissions",
+	RecordTypeFeature:     "features",
+	RecordTypePricingTier: "pricingTiers",
+	RecordTypeObject:      "objects",
+	RecordTypeWarrant:     "warrants",
+	RecordTypeLimit:       "limits",
+	RecordTypeUsage:       "usage",
+}
+
+type HeaderSpec struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+}
+
+// RecordSpec is a single line of an NDJSON snapshot
+type RecordSpec struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+type ImportCountSpec struct {
+	Created   int64 `json:"created"`
+	Updated   int64 `json:"updated"`
+	Unchanged int64 `json:"unchanged"`
+	Deleted   int64 `json:"deleted"`
+}
+
+type ImportResultSpec struct {
+	Mode    string                      `json:"mode"`
+	Records int64                       `json:"records"`
+	Counts  map[string]*ImportCountSpec `json:"counts"` // NOTE: map key = record type
+}
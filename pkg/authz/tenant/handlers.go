@@ -34,6 +34,13 @@ func (svc TenantService) Routes() []service.Route {
 			),
 		},
 
+		// restore
+		{
+			Pattern: "/v1/tenants/{tenantId}/restore",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, RestoreHandler),
+		},
+
 		// update
 		{
 			Pattern: "/v1/tenants/{tenantId}",
@@ -52,6 +59,67 @@ func (svc TenantService) Routes() []service.Route {
 			Method:  "DELETE",
 			Handler: service.NewRouteHandler(svc, DeleteHandler),
 		},
+
+		// users
+		{
+			Pattern: "/v1/tenants/{tenantId}/users",
+			Method:  "GET",
+			Handler: middleware.ChainMiddleware(
+				service.NewRouteHandler(svc, ListUsersHandler),
+				middleware.ListMiddleware[TenantUserListParamParser],
+			),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/users",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, AssignUserHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/users/{userId}",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveUserHandler),
+		},
+		{
+			Pattern: "/v1/users/{userId}/tenants",
+			Method:  "GET",
+			Handler: middleware.ChainMiddleware(
+				service.NewRouteHandler(svc, ListTenantsForUserHandler),
+				middleware.ListMiddleware[UserTenantListParamParser],
+			),
+			EnableSessionAuth: true,
+		},
+
+		// hierarchy
+		{
+			Pattern: "/v1/tenants/{tenantId}/parent",
+			Method:  "PUT",
+			Handler: service.NewRouteHandler(svc, SetParentHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/parent",
+			Method:  "DELETE",
+			Handler: service.NewRouteHandler(svc, RemoveParentHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/ancestors",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListAncestorsHandler),
+		},
+		{
+			Pattern: "/v1/tenants/{tenantId}/descendants",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ListDescendantsHandler),
+		},
+		{
+			Pattern: "/v1/tenant-hierarchy",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, GetHierarchySettingsHandler),
+		},
+		{
+			Pattern: "/v1/tenant-hierarchy",
+			Method:  "PUT",
+			Handler: service.NewRouteHandler(svc, UpdateHierarchySettingsHandler),
+		},
 	}
 }
 
@@ -94,6 +162,7 @@ func ListHandler(svc TenantService, w http.ResponseWriter, r *http.Request) erro
 		return err
 	}
 
+	middleware.SetCursorHeaders(w, listParams, tenants)
 	service.SendJSONResponse(w, tenants)
 	return nil
 }
@@ -131,3 +200,189 @@ func DeleteHandler(svc TenantService, w http.ResponseWriter, r *http.Request) er
 	w.WriteHeader(http.StatusOK)
 	return nil
 }
+
+func RestoreHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	tenantId := mux.Vars(r)["tenantId"]
+	restoredTenant, err := svc.RestoreByTenantId(r.Context(), tenantId)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, restoredTenant)
+	return nil
+}
+
+func ListUsersHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	tenantId, err := url.QueryUnescape(mux.Vars(r)["tenantId"])
+	if err != nil {
+		return service.NewInvalidParameterError("tenantId", "")
+	}
+
+	listParams := middleware.GetListParamsFromContext(r.Context())
+	tenantUsers, err := svc.ListUsers(r.Context(), tenantId, listParams)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetCursorHeaders(w, listParams, tenantUsers)
+	service.SendJSONResponse(w, tenantUsers)
+	return nil
+}
+
+func AssignUserHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	var assignSpec AssignTenantUserSpec
+	err := service.ParseJSONBody(r.Body, &assignSpec)
+	if err != nil {
+		return err
+	}
+
+	tenantId, err := url.QueryUnescape(mux.Vars(r)["tenantId"])
+	if err != nil {
+		return service.NewInvalidParameterError("tenantId", "")
+	}
+
+	tenantUser, err := svc.AssignUser(r.Context(), tenantId, assignSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, tenantUser)
+	return nil
+}
+
+func RemoveUserHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	tenantId, err := url.QueryUnescape(mux.Vars(r)["tenantId"])
+	if err != nil {
+		return service.NewInvalidParameterError("tenantId", "")
+	}
+
+	userId, err := url.QueryUnescape(mux.Vars(r)["userId"])
+	if err != nil {
+		return service.NewInvalidParameterError("userId", "")
+	}
+
+	err = svc.RemoveUser(r.Context(), tenantId, userId)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func ListTenantsForUserHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	userId, err := url.QueryUnescape(mux.Vars(r)["userId"])
+	if err != nil {
+		return service.NewInvalidParameterError("userId", "")
+	}
+
+	// NOTE: a session lists all of its user's tenants, not only its own, so the user can switch between them
+	_, err = service.ScopeToSession(service.GetAuthInfoFromRequestContext(r.Context()), userId, "")
+	if err != nil {
+		return err
+	}
+
+	listParams := middleware.GetListParamsFromContext(r.Context())
+	userTenants, err := svc.ListTenantsForUser(r.Context(), userId, listParams)
+	if err != nil {
+		return err
+	}
+
+	middleware.SetCursorHeaders(w, listParams, userTenants)
+	service.SendJSONResponse(w, userTenants)
+	return nil
+}
+
+func SetParentHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	var parentSpec SetParentTenantSpec
+	err := service.ParseJSONBody(r.Body, &parentSpec)
+	if err != nil {
+		return err
+	}
+
+	tenantId, err := url.QueryUnescape(mux.Vars(r)["tenantId"])
+	if err != nil {
+		return service.NewInvalidParameterError("tenantId", "")
+	}
+
+	tenantNode, err := svc.SetParent(r.Context(), tenantId, parentSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, tenantNode)
+	return nil
+}
+
+func RemoveParentHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	tenantId, err := url.QueryUnescape(mux.Vars(r)["tenantId"])
+	if err != nil {
+		return service.NewInvalidParameterError("tenantId", "")
+	}
+
+	err = svc.RemoveParent(r.Context(), tenantId)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+func ListAncestorsHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	tenantId, err := url.QueryUnescape(mux.Vars(r)["tenantId"])
+	if err != nil {
+		return service.NewInvalidParameterError("tenantId", "")
+	}
+
+	ancestors, err := svc.ListAncestors(r.Context(), tenantId)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, ancestors)
+	return nil
+}
+
+func ListDescendantsHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	tenantId, err := url.QueryUnescape(mux.Vars(r)["tenantId"])
+	if err != nil {
+		return service.NewInvalidParameterError("tenantId", "")
+	}
+
+	descendants, err := svc.ListDescendants(r.Context(), tenantId)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, descendants)
+	return nil
+}
+
+func GetHierarchySettingsHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	settings, err := svc.GetHierarchySettings(r.Context())
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, settings)
+	return nil
+}
+
+func UpdateHierarchySettingsHandler(svc TenantService, w http.ResponseWriter, r *http.Request) error {
+	var settingsSpec TenantHierarchySettingsSpec
+	err := service.ParseJSONBody(r.Body, &settingsSpec)
+	if err != nil {
+		return err
+	}
+
+	updatedSettings, err := svc.UpdateHierarchySettings(r.Context(), settingsSpec)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, updatedSettings)
+	return nil
+}
@@ -3,6 +3,8 @@ package tenant
 import (
 	"fmt"
 	"time"
+
+	"github.com/warrant-dev/warrant/pkg/middleware"
 )
 
 type TenantListParamParser struct{}
@@ -40,3 +42,107 @@ func (parser TenantListParamParser) ParseValue(val string, sortBy string) (inter
 		return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
 	}
 }
+
+func (spec TenantSpec) ToCursor(sortBy string) middleware.Cursor {
+	switch sortBy {
+	case "createdAt":
+		return middleware.NewCursor(spec.TenantId, spec.CreatedAt)
+	case "name":
+		if !spec.Name.Valid {
+			return middleware.NewCursor(spec.TenantId, middleware.NullValue{})
+		}
+
+		return middleware.NewCursor(spec.TenantId, spec.Name.String)
+	default:
+		return middleware.NewCursor(spec.TenantId, nil)
+	}
+}
+
+// TenantUserListParamParser parses the list params of a tenant's users,
+// which can only be sorted by userId
+type TenantUserListParamParser struct{}
+
+func (parser TenantUserListParamParser) GetDefaultSortBy() string {
+	return "userId"
+}
+
+func (parser TenantUserListParamParser) GetSupportedSortBys() []string {
+	return []string{"userId"}
+}
+
+func (parser TenantUserListParamParser) ParseValue(val string, sortBy string) (interface{}, error) {
+	return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
+}
+
+func (spec TenantUserSpec) ToCursor(sortBy string) middleware.Cursor {
+	return middleware.NewCursor(spec.UserId, nil)
+}
+
+// UserTenantListParamParser parses the list params of a user's tenants,
+// which can only be sorted by tenantId
+type UserTenantListParamParser struct{}
+
+func (parser UserTenantListParamParser) GetDefaultSortBy() string {
+	return "tenantId"
+}
+
+func (parser UserTenantListParamParser) GetSupportedSortBys() []string {
+	return []string{"tenantId"}
+}
+
+func (parser UserTenantListParamParser) ParseValue(val string, sortBy string) (interface{}, error) {
+	return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
+}
+
+func (spec UserTenantSpec) ToCursor(sortBy string) middleware.Cursor {
+	return middleware.NewCursor(spec.TenantId, nil)
+}
+
+// listPage returns the page of results selected by listParams from the
+// sorted, unique ids. Only the ids needed to fill the page are passed to get,
+// which returns nil for an id that shouldn't be listed.
+func listPage[T any](listParams middleware.ListParams, ids []string, get func(id string) (*T, error)) ([]T, error) {
+	queryIds := ids
+	if listParams.QuerySortOrder() == middleware.SortOrderDesc {
+		queryIds = make([]string, 0, len(ids))
+		for i := len(ids) - 1; i >= 0; i-- {
+			queryIds = append(queryIds, ids[i])
+		}
+	}
+
+	// NOTE: isBefore compares ids in the requested sort order
+	isBefore := func(id string, otherId string) bool {
+		if listParams.SortOrder == middleware.SortOrderDesc {
+			return id > otherId
+		}
+
+		return id < otherId
+	}
+
+	results := make([]T, 0)
+	for _, id := range queryIds {
+		if len(results) == listParams.Limit {
+			break
+		}
+
+		if listParams.AfterId != "" && !isBefore(listParams.AfterId, id) {
+			continue
+		}
+
+		if listParams.BeforeId != "" && !isBefore(id, listParams.BeforeId) {
+			continue
+		}
+
+		result, err := get(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			results = append(results, *result)
+		}
+	}
+
+	middleware.ReversePage(listParams, results)
+	return results, nil
+}
@@ -15,17 +15,19 @@ type Model interface {
 	GetCreatedAt() time.Time
 	GetUpdatedAt() time.Time
 	GetDeletedAt() database.NullTime
+	GetDeletionBatchId() string
 	ToTenantSpec() *TenantSpec
 }
 
 type Tenant struct {
-	ID        int64               `mysql:"id" postgres:"id"`
-	ObjectId  int64               `mysql:"objectId" postgres:"object_id"`
-	TenantId  string              `mysql:"tenantId" postgres:"tenant_id"`
-	Name      database.NullString `mysql:"name" postgres:"name"`
-	CreatedAt time.Time           `mysql:"createdAt" postgres:"created_at"`
-	UpdatedAt time.Time           `mysql:"updatedAt" postgres:"updated_at"`
-	DeletedAt database.NullTime   `mysql:"deletedAt" postgres:"deleted_at"`
+	ID              int64               `mysql:"id" postgres:"id"`
+	ObjectId        int64               `mysql:"objectId" postgres:"object_id"`
+	TenantId        string              `mysql:"tenantId" postgres:"tenant_id"`
+	Name            database.NullString `mysql:"name" postgres:"name"`
+	CreatedAt       time.Time           `mysql:"createdAt" postgres:"created_at"`
+	UpdatedAt       time.Time           `mysql:"updatedAt" postgres:"updated_at"`
+	DeletedAt       database.NullTime   `mysql:"deletedAt" postgres:"deleted_at"`
+	DeletionBatchId database.NullString `mysql:"deletionBatchId" postgres:"deletion_batch_id"`
 }
 
 func (tenant Tenant) GetID() int64 {
@@ -60,6 +62,10 @@ func (tenant Tenant) GetDeletedAt() database.NullTime {
 	return tenant.DeletedAt
 }
 
+func (tenant Tenant) GetDeletionBatchId() string {
+	return tenant.DeletionBatchId.String
+}
+
 func (tenant Tenant) ToTenantSpec() *TenantSpec {
 	return &TenantSpec{
 		TenantId:  tenant.TenantId,
@@ -35,7 +35,8 @@ func (repo MySQLRepository) Create(ctx context.Context, model Model) (int64, err
 				objectId = ?,
 				name = ?,
 				createdAt = CURRENT_TIMESTAMP(6),
-				deletedAt = NULL
+				deletedAt = NULL,
+				deletionBatchId = NULL
 		`,
 		model.GetTenantId(),
 		model.GetObjectId(),
@@ -128,21 +129,9 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 
 	if listParams.AfterId != "" {
 		if listParams.AfterValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s > ? OR (tenantId > ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s < ? OR (tenantId < ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(listParams.SortBy, "tenantId", listParams.SortOrder, listParams.AfterValue, listParams.AfterId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND tenantId > ?", query)
@@ -156,37 +145,26 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 
 	if listParams.BeforeId != "" {
 		if listParams.BeforeValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s < ? OR (tenantId < ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s > ? OR (tenantId > ? AND %s = ?))", query, listParams.SortBy, listParams.SortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(listParams.SortBy, "tenantId", listParams.SortOrder.Reverse(), listParams.BeforeValue, listParams.BeforeId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND tenantId < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND tenantId > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	if listParams.SortBy != "tenantId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s, tenantId %s LIMIT ?", query, listParams.SortBy, listParams.SortOrder, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s, tenantId %s LIMIT ?", query, listParams.SortBy, sortOrder, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY tenantId %s LIMIT ?", query, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY tenantId %s LIMIT ?", query, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -209,6 +187,8 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 		models = append(models, &tenants[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -233,18 +213,20 @@ func (repo MySQLRepository) UpdateByTenantId(ctx context.Context, tenantId strin
 	return nil
 }
 
-func (repo MySQLRepository) DeleteByTenantId(ctx context.Context, tenantId string) error {
+func (repo MySQLRepository) DeleteByTenantId(ctx context.Context, tenantId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE tenant
 			SET
-				deletedAt = ?
+				deletedAt = ?,
+				deletionBatchId = ?
 			WHERE
 				tenantId = ? AND
 				deletedAt IS NULL
 		`,
 		time.Now().UTC(),
+		deletionBatchId,
 		tenantId,
 	)
 	if err != nil {
@@ -258,3 +240,53 @@ func (repo MySQLRepository) DeleteByTenantId(ctx context.Context, tenantId strin
 
 	return nil
 }
+
+func (repo MySQLRepository) GetDeletedByTenantId(ctx context.Context, tenantId string) (Model, error) {
+	var tenant Tenant
+	err := repo.DB.GetContext(
+		ctx,
+		&tenant,
+		`
+			SELECT id, objectId, tenantId, name, createdAt, updatedAt, deletedAt, deletionBatchId
+			FROM tenant
+			WHERE
+				tenantId = ? AND
+				deletedAt IS NOT NULL AND
+				deletionBatchId IS NOT NULL
+		`,
+		tenantId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, service.NewRecordNotFoundError("Tenant", tenantId)
+		default:
+			return nil, service.NewInternalError(fmt.Sprintf("Unable to get Tenant %s from mysql", tenantId))
+		}
+	}
+
+	return &tenant, nil
+}
+
+func (repo MySQLRepository) RestoreByTenantId(ctx context.Context, tenantId string, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE tenant
+			SET
+				deletedAt = NULL,
+				deletionBatchId = NULL
+			WHERE
+				tenantId = ? AND
+				deletionBatchId = ? AND
+				deletedAt IS NOT NULL
+		`,
+		tenantId,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error restoring tenant %s", tenantId))
+	}
+
+	return nil
+}
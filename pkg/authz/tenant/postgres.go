@@ -38,7 +38,8 @@ func (repo PostgresRepository) Create(ctx context.Context, model Model) (int64,
 				object_id = ?,
 				name = ?,
 				created_at = CURRENT_TIMESTAMP(6),
-				deleted_at = NULL
+				deleted_at = NULL,
+				deletion_batch_id = NULL
 			RETURNING id
 		`,
 		model.GetTenantId(),
@@ -128,21 +129,9 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 	sortBy := regexp.MustCompile("([A-Z])").ReplaceAllString(listParams.SortBy, `_$1`)
 	if listParams.AfterId != "" {
 		if listParams.AfterValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s > ? OR (tenant_id > ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s < ? OR (tenant_id < ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.AfterValue,
-					listParams.AfterId,
-					listParams.AfterValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(sortBy, "tenant_id", listParams.SortOrder, listParams.AfterValue, listParams.AfterId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND tenant_id > ?", query)
@@ -156,42 +145,31 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 
 	if listParams.BeforeId != "" {
 		if listParams.BeforeValue != nil {
-			if listParams.SortOrder == middleware.SortOrderAsc {
-				query = fmt.Sprintf("%s AND (%s < ? OR (tenant_id < ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			} else {
-				query = fmt.Sprintf("%s AND (%s > ? OR (tenant_id > ? AND %s = ?))", query, sortBy, sortBy)
-				replacements = append(replacements,
-					listParams.BeforeValue,
-					listParams.BeforeId,
-					listParams.BeforeValue,
-				)
-			}
+			condition, conditionReplacements := middleware.KeysetCondition(sortBy, "tenant_id", listParams.SortOrder.Reverse(), listParams.BeforeValue, listParams.BeforeId)
+			query = fmt.Sprintf("%s AND %s", query, condition)
+			replacements = append(replacements, conditionReplacements...)
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND tenant_id < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND tenant_id > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	nullSortClause := "NULLS LAST"
-	if listParams.SortOrder == middleware.SortOrderAsc {
+	if sortOrder == middleware.SortOrderAsc {
 		nullSortClause = "NULLS FIRST"
 	}
 
 	if listParams.SortBy != "tenantId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s %s, tenant_id %s LIMIT ?", query, sortBy, listParams.SortOrder, nullSortClause, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s %s, tenant_id %s LIMIT ?", query, sortBy, sortOrder, nullSortClause, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY tenant_id %s %s LIMIT ?", query, listParams.SortOrder, nullSortClause)
+		query = fmt.Sprintf("%s ORDER BY tenant_id %s %s LIMIT ?", query, sortOrder, nullSortClause)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -214,6 +192,8 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 		models = append(models, &tenants[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -238,18 +218,20 @@ func (repo PostgresRepository) UpdateByTenantId(ctx context.Context, tenantId st
 	return nil
 }
 
-func (repo PostgresRepository) DeleteByTenantId(ctx context.Context, tenantId string) error {
+func (repo PostgresRepository) DeleteByTenantId(ctx context.Context, tenantId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE tenant
 			SET
-				deleted_at = ?
+				deleted_at = ?,
+				deletion_batch_id = ?
 			WHERE
 				tenant_id = ? AND
 				deleted_at IS NULL
 		`,
 		time.Now().UTC(),
+		deletionBatchId,
 		tenantId,
 	)
 	if err != nil {
@@ -263,3 +245,53 @@ func (repo PostgresRepository) DeleteByTenantId(ctx context.Context, tenantId st
 
 	return nil
 }
+
+func (repo PostgresRepository) GetDeletedByTenantId(ctx context.Context, tenantId string) (Model, error) {
+	var tenant Tenant
+	err := repo.DB.GetContext(
+		ctx,
+		&tenant,
+		`
+			SELECT id, object_id, tenant_id, name, created_at, updated_at, deleted_at, deletion_batch_id
+			FROM tenant
+			WHERE
+				tenant_id = ? AND
+				deleted_at IS NOT NULL AND
+				deletion_batch_id IS NOT NULL
+		`,
+		tenantId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, service.NewRecordNotFoundError("Tenant", tenantId)
+		default:
+			return nil, service.NewInternalError(fmt.Sprintf("Unable to get Tenant %s from mysql", tenantId))
+		}
+	}
+
+	return &tenant, nil
+}
+
+func (repo PostgresRepository) RestoreByTenantId(ctx context.Context, tenantId string, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE tenant
+			SET
+				deleted_at = NULL,
+				deletion_batch_id = NULL
+			WHERE
+				tenant_id = ? AND
+				deletion_batch_id = ? AND
+				deleted_at IS NOT NULL
+		`,
+		tenantId,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error restoring tenant %s", tenantId))
+	}
+
+	return nil
+}
@@ -14,7 +14,9 @@ type TenantRepository interface {
 	GetByTenantId(ctx context.Context, tenantId string) (Model, error)
 	List(ctx context.Context, listParams middleware.ListParams) ([]Model, error)
 	UpdateByTenantId(ctx context.Context, tenantId string, tenant Model) error
-	DeleteByTenantId(ctx context.Context, tenantId string) error
+	DeleteByTenantId(ctx context.Context, tenantId string, deletionBatchId string) error
+	GetDeletedByTenantId(ctx context.Context, tenantId string) (Model, error)
+	RestoreByTenantId(ctx context.Context, tenantId string, deletionBatchId string) error
 }
 
 func NewRepository(db database.Database) (TenantRepository, error) {
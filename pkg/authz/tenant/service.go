@@ -2,11 +2,17 @@ package tenant
 
 import (
 	"context"
+	"fmt"
 	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
+	check "github.com/warrant-dev/warrant/pkg/authz/check"
 	object "github.com/warrant-dev/warrant/pkg/authz/object"
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	user "github.com/warrant-dev/warrant/pkg/authz/user"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
 	"github.com/warrant-dev/warrant/pkg/event"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
@@ -16,17 +22,25 @@ const ResourceTypeTenant = "tenant"
 
 type TenantService struct {
 	service.BaseService
-	repo      TenantRepository
-	eventSvc  event.EventService
-	objectSvc object.ObjectService
+	repo          TenantRepository
+	eventSvc      event.EventService
+	objectSvc     object.ObjectService
+	userSvc       user.UserService
+	warrantSvc    warrant.WarrantService
+	checkSvc      check.CheckService
+	objectTypeSvc objecttype.ObjectTypeService
 }
 
-func NewService(env service.Env, repo TenantRepository, eventSvc event.EventService, objectSvc object.ObjectService) TenantService {
+func NewService(env service.Env, repo TenantRepository, eventSvc event.EventService, objectSvc object.ObjectService, userSvc user.UserService, warrantSvc warrant.WarrantService, checkSvc check.CheckService, objectTypeSvc objecttype.ObjectTypeService) TenantService {
 	return TenantService{
-		BaseService: service.NewBaseService(env),
-		repo:        repo,
-		eventSvc:    eventSvc,
-		objectSvc:   objectSvc,
+		BaseService:   service.NewBaseService(env),
+		repo:          repo,
+		eventSvc:      eventSvc,
+		objectSvc:     objectSvc,
+		userSvc:       userSvc,
+		warrantSvc:    warrantSvc,
+		checkSvc:      checkSvc,
+		objectTypeSvc: objectTypeSvc,
 	}
 }
 
@@ -141,12 +155,15 @@ func (svc TenantService) DeleteByTenantId(ctx context.Context, tenantId string)
 			return err
 		}
 
-		err = tenantRepository.DeleteByTenantId(txCtx, tenantId)
+		// NOTE: the tenant, its object, and its warrants are tagged with the
+		// same deletion batch id so they can be restored together
+		deletionBatchId := uuid.New().String()
+		err = tenantRepository.DeleteByTenantId(txCtx, tenantId, deletionBatchId)
 		if err != nil {
 			return err
 		}
 
-		err = svc.objectSvc.DeleteByObjectTypeAndId(txCtx, objecttype.ObjectTypeTenant, tenantId)
+		err = svc.objectSvc.DeleteByObjectTypeAndIdInBatch(txCtx, objecttype.ObjectTypeTenant, tenantId, deletionBatchId)
 		if err != nil {
 			return err
 		}
@@ -158,6 +175,735 @@ func (svc TenantService) DeleteByTenantId(ctx context.Context, tenantId string)
 	return err
 }
 
+// RestoreByTenantId restores a deleted tenant along with its object and the
+// warrants that were deleted with it. Warrants deleted separately beforehand
+// are not restored.
+func (svc TenantService) RestoreByTenantId(ctx context.Context, tenantId string) (*TenantSpec, error) {
+	var restoredTenant Model
+	err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		tenantRepository, err := NewRepository(svc.Env().DB())
+		if err != nil {
+			return err
+		}
+
+		deletedTenant, err := tenantRepository.GetDeletedByTenantId(txCtx, tenantId)
+		if err != nil {
+			return err
+		}
+
+		err = svc.objectSvc.ValidateRestorable(objecttype.ObjectTypeTenant, tenantId, deletedTenant.GetDeletedAt())
+		if err != nil {
+			return err
+		}
+
+		deletionBatchId := deletedTenant.GetDeletionBatchId()
+		err = tenantRepository.RestoreByTenantId(txCtx, tenantId, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		err = svc.objectSvc.RestoreByObjectTypeAndId(txCtx, objecttype.ObjectTypeTenant, tenantId, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		restoredTenant, err = tenantRepository.GetByTenantId(txCtx, tenantId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restoredTenantSpec := restoredTenant.ToTenantSpec()
+	svc.eventSvc.TrackResourceRestored(ctx, ResourceTypeTenant, tenantId, restoredTenantSpec)
+	return restoredTenantSpec, nil
+}
+
+// ListUsers returns a page of the users of a tenant, sorted by userId. Users
+// are listed if they're assigned to the tenant or, through the tenant
+// hierarchy, are admins of one of its ancestors and inherit one of the
+// TenantRelations on it.
+func (svc TenantService) ListUsers(ctx context.Context, tenantId string, listParams middleware.ListParams) ([]TenantUserSpec, error) {
+	_, err := svc.GetByTenantId(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	warrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+		ObjectType: objecttype.ObjectTypeTenant,
+		ObjectId:   tenantId,
+		Subject: &warrant.SubjectSpec{
+			ObjectType: objecttype.ObjectTypeUser,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ancestorIds, err := svc.ancestorIds(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ancestorId := range ancestorIds {
+		ancestorAdminWarrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+			ObjectType: objecttype.ObjectTypeTenant,
+			ObjectId:   ancestorId,
+			Relation:   objecttype.RelationAdmin,
+			Subject: &warrant.SubjectSpec{
+				ObjectType: objecttype.ObjectTypeUser,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		warrantSpecs = append(warrantSpecs, ancestorAdminWarrantSpecs...)
+	}
+
+	userIds := assignedObjectIds(warrantSpecs, func(warrantSpec *warrant.WarrantSpec) string { return warrantSpec.Subject.ObjectId })
+	return listPage(listParams, userIds, func(userId string) (*TenantUserSpec, error) {
+		return svc.getTenantUser(ctx, tenantId, userId)
+	})
+}
+
+// AssignUser assigns a user to a tenant with the given relation, or as a
+// member if no relation is given
+func (svc TenantService) AssignUser(ctx context.Context, tenantId string, assignSpec AssignTenantUserSpec) (*TenantUserSpec, error) {
+	_, err := svc.GetByTenantId(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.userSvc.GetByUserId(ctx, assignSpec.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	relation := assignSpec.Relation
+	if relation == "" {
+		relation = objecttype.RelationMember
+	}
+
+	_, err = svc.warrantSvc.Create(ctx, warrant.WarrantSpec{
+		ObjectType: objecttype.ObjectTypeTenant,
+		ObjectId:   tenantId,
+		Relation:   relation,
+		Subject: &warrant.SubjectSpec{
+			ObjectType: objecttype.ObjectTypeUser,
+			ObjectId:   assignSpec.UserId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tenantUserSpec, err := svc.getTenantUser(ctx, tenantId, assignSpec.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if tenantUserSpec == nil {
+		return nil, service.NewInternalError("User is not a member of the tenant after being assigned to it")
+	}
+
+	return tenantUserSpec, nil
+}
+
+// RemoveUser removes all of a user's direct assignments to a tenant
+func (svc TenantService) RemoveUser(ctx context.Context, tenantId string, userId string) error {
+	return svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		warrantSpecs, err := svc.warrantSvc.ListAll(txCtx, &warrant.FilterOptions{
+			ObjectType: objecttype.ObjectTypeTenant,
+			ObjectId:   tenantId,
+			Subject: &warrant.SubjectSpec{
+				ObjectType: objecttype.ObjectTypeUser,
+				ObjectId:   userId,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		removed := false
+		for _, warrantSpec := range warrantSpecs {
+			if !isAssignment(warrantSpec) {
+				continue
+			}
+
+			err = svc.warrantSvc.Delete(txCtx, *warrantSpec)
+			if err != nil {
+				return err
+			}
+
+			removed = true
+		}
+
+		if !removed {
+			return service.NewRecordNotFoundError("TenantUser", userId)
+		}
+
+		return nil
+	})
+}
+
+// ListTenantsForUser returns a page of the tenants a user is a member of,
+// sorted by tenantId. Tenants are listed if the user is assigned to them or,
+// through the tenant hierarchy, is an admin of one of their ancestors and
+// inherits one of the TenantRelations on them.
+func (svc TenantService) ListTenantsForUser(ctx context.Context, userId string, listParams middleware.ListParams) ([]UserTenantSpec, error) {
+	_, err := svc.userSvc.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	warrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+		ObjectType: objecttype.ObjectTypeTenant,
+		Subject: &warrant.SubjectSpec{
+			ObjectType: objecttype.ObjectTypeUser,
+			ObjectId:   userId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	tenantIds := make([]string, 0)
+	for _, tenantId := range assignedObjectIds(warrantSpecs, func(warrantSpec *warrant.WarrantSpec) string { return warrantSpec.ObjectId }) {
+		seen[tenantId] = true
+		tenantIds = append(tenantIds, tenantId)
+	}
+
+	for _, warrantSpec := range warrantSpecs {
+		if !isAssignment(warrantSpec) || warrantSpec.Relation != objecttype.RelationAdmin {
+			continue
+		}
+
+		descendantIds, err := svc.descendantIds(ctx, warrantSpec.ObjectId)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, descendantId := range descendantIds {
+			if !seen[descendantId] {
+				seen[descendantId] = true
+				tenantIds = append(tenantIds, descendantId)
+			}
+		}
+	}
+
+	sort.Strings(tenantIds)
+	return listPage(listParams, tenantIds, func(tenantId string) (*UserTenantSpec, error) {
+		relations, err := svc.getRelations(ctx, tenantId, userId)
+		if err != nil || len(relations) == 0 {
+			return nil, err
+		}
+
+		// NOTE: warrants don't require their object to exist, so assignments to tenants that were never created are skipped
+		tenantSpec, err := svc.GetByTenantId(ctx, tenantId)
+		if err != nil {
+			if _, ok := err.(*service.RecordNotFoundError); ok {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+
+		return &UserTenantSpec{
+			TenantId:  tenantSpec.TenantId,
+			Name:      tenantSpec.Name,
+			Relations: relations,
+		}, nil
+	})
+}
+
+// SetParent moves a tenant, along with its descendants, under a parent tenant,
+// replacing its current parent if it has one
+func (svc TenantService) SetParent(ctx context.Context, tenantId string, parentSpec SetParentTenantSpec) (*TenantNodeSpec, error) {
+	tenantSpec, err := svc.GetByTenantId(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = svc.GetByTenantId(ctx, parentSpec.ParentTenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		// NOTE: parent changes are serialized so concurrent moves can't form a cycle
+		err := svc.objectTypeSvc.LockByTypeId(txCtx, objecttype.ObjectTypeTenant)
+		if err != nil {
+			return err
+		}
+
+		// NOTE: a tenant can't be moved under itself or one of its descendants
+		ancestorIds, err := svc.ancestorIds(txCtx, parentSpec.ParentTenantId)
+		if err != nil {
+			return err
+		}
+
+		for _, ancestorId := range append([]string{parentSpec.ParentTenantId}, ancestorIds...) {
+			if ancestorId == tenantId {
+				return service.NewInvalidParameterError("parentTenantId", "cannot be the tenant or one of its descendants")
+			}
+		}
+
+		err = svc.removeParentWarrants(txCtx, tenantId)
+		if err != nil {
+			return err
+		}
+
+		_, err = svc.warrantSvc.Create(txCtx, parentWarrantSpec(tenantId, parentSpec.ParentTenantId))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TenantNodeSpec{
+		TenantId:       tenantSpec.TenantId,
+		Name:           tenantSpec.Name,
+		ParentTenantId: parentSpec.ParentTenantId,
+	}, nil
+}
+
+// RemoveParent makes a tenant, along with its descendants, a top-level tenant
+func (svc TenantService) RemoveParent(ctx context.Context, tenantId string) error {
+	return svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		err := svc.objectTypeSvc.LockByTypeId(txCtx, objecttype.ObjectTypeTenant)
+		if err != nil {
+			return err
+		}
+
+		parentTenantId, err := svc.parentId(txCtx, tenantId)
+		if err != nil {
+			return err
+		}
+
+		if parentTenantId == "" {
+			return service.NewRecordNotFoundError("TenantParent", tenantId)
+		}
+
+		return svc.removeParentWarrants(txCtx, tenantId)
+	})
+}
+
+// ListAncestors returns the ancestors of a tenant, starting with its parent
+func (svc TenantService) ListAncestors(ctx context.Context, tenantId string) ([]TenantNodeSpec, error) {
+	_, err := svc.GetByTenantId(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestorIds, err := svc.ancestorIds(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantNodeSpecs := make([]TenantNodeSpec, 0, len(ancestorIds))
+	for i, ancestorId := range ancestorIds {
+		parentTenantId := ""
+		if i+1 < len(ancestorIds) {
+			parentTenantId = ancestorIds[i+1]
+		}
+
+		tenantNodeSpec, err := svc.getTenantNode(ctx, ancestorId, parentTenantId, i+1)
+		if err != nil {
+			return nil, err
+		}
+
+		if tenantNodeSpec != nil {
+			tenantNodeSpecs = append(tenantNodeSpecs, *tenantNodeSpec)
+		}
+	}
+
+	return tenantNodeSpecs, nil
+}
+
+// ListDescendants returns the descendants of a tenant level by level, each
+// level sorted by tenantId
+func (svc TenantService) ListDescendants(ctx context.Context, tenantId string) ([]TenantNodeSpec, error) {
+	_, err := svc.GetByTenantId(ctx, tenantId)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantNodeSpecs := make([]TenantNodeSpec, 0)
+	visited := map[string]bool{tenantId: true}
+	level := []string{tenantId}
+	for depth := 1; len(level) > 0; depth++ {
+		nextLevel := make([]string, 0)
+		for _, parentTenantId := range level {
+			childIds, err := svc.childIds(ctx, parentTenantId)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, childId := range childIds {
+				if visited[childId] {
+					continue
+				}
+
+				visited[childId] = true
+				tenantNodeSpec, err := svc.getTenantNode(ctx, childId, parentTenantId, depth)
+				if err != nil {
+					return nil, err
+				}
+
+				if tenantNodeSpec != nil {
+					tenantNodeSpecs = append(tenantNodeSpecs, *tenantNodeSpec)
+					nextLevel = append(nextLevel, childId)
+				}
+			}
+		}
+
+		level = nextLevel
+	}
+
+	return tenantNodeSpecs, nil
+}
+
+// GetHierarchySettings returns the TenantRelations the admins of a parent
+// tenant inherit on its child tenants, derived from the tenant object type
+func (svc TenantService) GetHierarchySettings(ctx context.Context) (*TenantHierarchySettingsSpec, error) {
+	objectTypeSpec, err := svc.objectTypeSvc.GetByTypeId(ctx, objecttype.ObjectTypeTenant)
+	if err != nil {
+		return nil, err
+	}
+
+	inheritedRelations := make([]string, 0)
+	for _, relation := range TenantRelations {
+		rule := objectTypeSpec.Relations[relation]
+		if isParentAdminRule(rule) {
+			inheritedRelations = append(inheritedRelations, relation)
+			continue
+		}
+
+		for _, subRule := range rule.Rules {
+			if rule.InheritIf == objecttype.InheritIfAnyOf && isParentAdminRule(subRule) {
+				inheritedRelations = append(inheritedRelations, relation)
+				break
+			}
+		}
+	}
+
+	return &TenantHierarchySettingsSpec{
+		InheritedRelations: inheritedRelations,
+	}, nil
+}
+
+// UpdateHierarchySettings sets the TenantRelations the admins of a parent
+// tenant inherit on its child tenants. It replaces the rules of the
+// TenantRelations on the tenant object type and adds the parent relation if
+// the tenant object type doesn't have it, leaving any other relations as is.
+func (svc TenantService) UpdateHierarchySettings(ctx context.Context, settingsSpec TenantHierarchySettingsSpec) (*TenantHierarchySettingsSpec, error) {
+	inherited := make(map[string]bool)
+	for _, relation := range settingsSpec.InheritedRelations {
+		if !isTenantRelation(relation) {
+			return nil, service.NewInvalidParameterError("inheritedRelations", fmt.Sprintf("must be one of %s", strings.Join(TenantRelations, ", ")))
+		}
+
+		inherited[relation] = true
+	}
+
+	objectTypeSpec, err := svc.objectTypeSvc.GetByTypeId(ctx, objecttype.ObjectTypeTenant)
+	if err != nil {
+		return nil, err
+	}
+
+	parentAdminRule := objecttype.RelationRule{
+		InheritIf:    objecttype.RelationAdmin,
+		OfType:       objecttype.ObjectTypeTenant,
+		WithRelation: objecttype.RelationParent,
+	}
+	for i, relation := range TenantRelations {
+		// NOTE: each relation is inherited from the one above it, and admin is only assigned directly
+		var rule objecttype.RelationRule
+		if i > 0 {
+			rule = objecttype.RelationRule{InheritIf: TenantRelations[i-1]}
+		}
+
+		if inherited[relation] {
+			if rule.InheritIf == "" {
+				rule = parentAdminRule
+			} else {
+				rule = objecttype.RelationRule{
+					InheritIf: objecttype.InheritIfAnyOf,
+					Rules:     []objecttype.RelationRule{rule, parentAdminRule},
+				}
+			}
+		}
+
+		objectTypeSpec.Relations[relation] = rule
+	}
+
+	if _, ok := objectTypeSpec.Relations[objecttype.RelationParent]; !ok {
+		objectTypeSpec.Relations[objecttype.RelationParent] = objecttype.RelationRule{}
+	}
+
+	_, err = svc.objectTypeSvc.UpdateByTypeId(ctx, objecttype.ObjectTypeTenant, *objectTypeSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	return svc.GetHierarchySettings(ctx)
+}
+
+// parentId returns the tenantId of a tenant's parent, or an empty string if it has none
+func (svc TenantService) parentId(ctx context.Context, tenantId string) (string, error) {
+	warrantSpecs, err := svc.listParentWarrants(ctx, tenantId)
+	if err != nil || len(warrantSpecs) == 0 {
+		return "", err
+	}
+
+	return warrantSpecs[0].Subject.ObjectId, nil
+}
+
+// ancestorIds returns the tenantIds of a tenant's ancestors, starting with its parent
+func (svc TenantService) ancestorIds(ctx context.Context, tenantId string) ([]string, error) {
+	ancestorIds := make([]string, 0)
+	visited := map[string]bool{tenantId: true}
+	for {
+		parentTenantId, err := svc.parentId(ctx, tenantId)
+		if err != nil {
+			return nil, err
+		}
+
+		// NOTE: parent warrants created directly (e.g. with the warrants API) could form a cycle
+		if parentTenantId == "" || visited[parentTenantId] {
+			return ancestorIds, nil
+		}
+
+		visited[parentTenantId] = true
+		ancestorIds = append(ancestorIds, parentTenantId)
+		tenantId = parentTenantId
+	}
+}
+
+// descendantIds returns the tenantIds of a tenant's descendants, level by level
+func (svc TenantService) descendantIds(ctx context.Context, tenantId string) ([]string, error) {
+	descendantIds := make([]string, 0)
+	visited := map[string]bool{tenantId: true}
+	level := []string{tenantId}
+	for len(level) > 0 {
+		nextLevel := make([]string, 0)
+		for _, parentTenantId := range level {
+			childIds, err := svc.childIds(ctx, parentTenantId)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, childId := range childIds {
+				if !visited[childId] {
+					visited[childId] = true
+					descendantIds = append(descendantIds, childId)
+					nextLevel = append(nextLevel, childId)
+				}
+			}
+		}
+
+		level = nextLevel
+	}
+
+	return descendantIds, nil
+}
+
+// childIds returns the sorted tenantIds of a tenant's children
+func (svc TenantService) childIds(ctx context.Context, tenantId string) ([]string, error) {
+	warrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+		ObjectType: objecttype.ObjectTypeTenant,
+		Relation:   objecttype.RelationParent,
+		Subject: &warrant.SubjectSpec{
+			ObjectType: objecttype.ObjectTypeTenant,
+			ObjectId:   tenantId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	childIds := make([]string, 0)
+	for _, warrantSpec := range warrantSpecs {
+		if isParentWarrant(warrantSpec) {
+			childIds = append(childIds, warrantSpec.ObjectId)
+		}
+	}
+
+	sort.Strings(childIds)
+	return childIds, nil
+}
+
+func (svc TenantService) listParentWarrants(ctx context.Context, tenantId string) ([]*warrant.WarrantSpec, error) {
+	warrantSpecs, err := svc.warrantSvc.ListAll(ctx, &warrant.FilterOptions{
+		ObjectType: objecttype.ObjectTypeTenant,
+		ObjectId:   tenantId,
+		Relation:   objecttype.RelationParent,
+		Subject: &warrant.SubjectSpec{
+			ObjectType: objecttype.ObjectTypeTenant,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	parentWarrants := make([]*warrant.WarrantSpec, 0)
+	for _, warrantSpec := range warrantSpecs {
+		if isParentWarrant(warrantSpec) {
+			parentWarrants = append(parentWarrants, warrantSpec)
+		}
+	}
+
+	return parentWarrants, nil
+}
+
+func (svc TenantService) removeParentWarrants(ctx context.Context, tenantId string) error {
+	warrantSpecs, err := svc.listParentWarrants(ctx, tenantId)
+	if err != nil {
+		return err
+	}
+
+	for _, warrantSpec := range warrantSpecs {
+		err = svc.warrantSvc.Delete(ctx, *warrantSpec)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getTenantNode returns a tenant in a tenant hierarchy, or nil if the tenant
+// doesn't exist (e.g. a parent warrant was created for a tenant that was never created)
+func (svc TenantService) getTenantNode(ctx context.Context, tenantId string, parentTenantId string, depth int) (*TenantNodeSpec, error) {
+	tenantSpec, err := svc.GetByTenantId(ctx, tenantId)
+	if err != nil {
+		if _, ok := err.(*service.RecordNotFoundError); ok {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &TenantNodeSpec{
+		TenantId:       tenantSpec.TenantId,
+		Name:           tenantSpec.Name,
+		ParentTenantId: parentTenantId,
+		Depth:          depth,
+	}, nil
+}
+
+// getTenantUser returns the membership of a user in a tenant, or nil if the
+// user has none of the TenantRelations
+func (svc TenantService) getTenantUser(ctx context.Context, tenantId string, userId string) (*TenantUserSpec, error) {
+	relations, err := svc.getRelations(ctx, tenantId, userId)
+	if err != nil || len(relations) == 0 {
+		return nil, err
+	}
+
+	userSpec, err := svc.userSvc.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TenantUserSpec{
+		UserId:    userSpec.UserId,
+		Email:     userSpec.Email,
+		Relations: relations,
+	}, nil
+}
+
+// getRelations returns the TenantRelations a user has in a tenant, including
+// the ones inherited through the tenant object type's rules
+func (svc TenantService) getRelations(ctx context.Context, tenantId string, userId string) ([]string, error) {
+	// NOTE: listing memberships doesn't grant or deny any access, so the checks aren't tracked as access events
+	checkCtx := event.WithoutTracking(ctx)
+	relations := make([]string, 0)
+	for _, relation := range TenantRelations {
+		match, _, err := svc.checkSvc.Check(checkCtx, nil, check.CheckSpec{
+			WarrantSpec: warrant.WarrantSpec{
+				ObjectType: objecttype.ObjectTypeTenant,
+				ObjectId:   tenantId,
+				Relation:   relation,
+				Subject: &warrant.SubjectSpec{
+					ObjectType: objecttype.ObjectTypeUser,
+					ObjectId:   userId,
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if match {
+			relations = append(relations, relation)
+		}
+	}
+
+	return relations, nil
+}
+
+func isTenantRelation(relation string) bool {
+	for _, tenantRelation := range TenantRelations {
+		if relation == tenantRelation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isAssignment returns true if the warrant directly assigns a user to a
+// tenant with one of the TenantRelations
+func isAssignment(warrantSpec *warrant.WarrantSpec) bool {
+	if warrantSpec.Subject.Relation != "" || len(warrantSpec.Context) > 0 {
+		return false
+	}
+
+	return isTenantRelation(warrantSpec.Relation)
+}
+
+// assignedObjectIds returns the sorted, unique ids returned by objectId for
+// the warrants that are assignments
+func assignedObjectIds(warrantSpecs []*warrant.WarrantSpec, objectId func(warrantSpec *warrant.WarrantSpec) string) []string {
+	seen := make(map[string]bool)
+	objectIds := make([]string, 0)
+	for _, warrantSpec := range warrantSpecs {
+		if !isAssignment(warrantSpec) || seen[objectId(warrantSpec)] {
+			continue
+		}
+
+		seen[objectId(warrantSpec)] = true
+		objectIds = append(objectIds, objectId(warrantSpec))
+	}
+
+	sort.Strings(objectIds)
+	return objectIds
+}
+
+// isParentWarrant returns true if the warrant makes a tenant the parent of another
+func isParentWarrant(warrantSpec *warrant.WarrantSpec) bool {
+	return warrantSpec.Subject.ObjectType == objecttype.ObjectTypeTenant && warrantSpec.Subject.Relation == "" && len(warrantSpec.Context) == 0
+}
+
+// isParentAdminRule returns true if the rule grants a relation to the admins of a tenant's parent
+func isParentAdminRule(rule objecttype.RelationRule) bool {
+	return rule.InheritIf == objecttype.RelationAdmin && rule.OfType == objecttype.ObjectTypeTenant && rule.WithRelation == objecttype.RelationParent
+}
+
+func parentWarrantSpec(tenantId string, parentTenantId string) warrant.WarrantSpec {
+	return warrant.WarrantSpec{
+		ObjectType: objecttype.ObjectTypeTenant,
+		ObjectId:   tenantId,
+		Relation:   objecttype.RelationParent,
+		Subject: &warrant.SubjectSpec{
+			ObjectType: objecttype.ObjectTypeTenant,
+			ObjectId:   parentTenantId,
+		},
+	}
+}
+
 func validateOrGenerateTenantIdInSpec(tenantSpec *TenantSpec) error {
 	tenantIdRegExp := regexp.MustCompile(`^[a-zA-Z0-9_\-\.@\|]+$`)
 	if tenantSpec.TenantId != "" {
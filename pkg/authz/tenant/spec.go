@@ -32,3 +32,54 @@ func (spec TenantSpec) ToObjectSpec() *object.ObjectSpec {
 type UpdateTenantSpec struct {
 	Name database.NullString `json:"name"`
 }
+
+// TenantRelations are the membership levels of users in a tenant, from the
+// highest down. Each level inherits the ones below it.
+var TenantRelations = []string{
+	objecttype.RelationAdmin,
+	objecttype.RelationManager,
+	objecttype.RelationMember,
+}
+
+type AssignTenantUserSpec struct {
+	UserId   string `json:"userId" validate:"required"`
+	Relation string `json:"relation" validate:"omitempty,oneof=admin manager member"` // NOTE: defaults to member
+}
+
+// TenantUserSpec type for a user's membership in a tenant. Relations are the
+// levels the user has in the tenant, directly or inherited, from the highest down.
+type TenantUserSpec struct {
+	UserId    string              `json:"userId"`
+	Email     database.NullString `json:"email"`
+	Relations []string            `json:"relations"`
+}
+
+// UserTenantSpec type for a tenant a user is a member of. Relations are the
+// levels the user has in the tenant, directly or inherited, from the highest down.
+type UserTenantSpec struct {
+	TenantId  string              `json:"tenantId"`
+	Name      database.NullString `json:"name"`
+	Relations []string            `json:"relations"`
+}
+
+// SetParentTenantSpec type for moving a tenant, along with its descendants,
+// under a parent tenant
+type SetParentTenantSpec struct {
+	ParentTenantId string `json:"parentTenantId" validate:"required"`
+}
+
+// TenantNodeSpec type for a tenant in a tenant hierarchy. Depth is the number
+// of levels between the tenant and the tenant it was listed for.
+type TenantNodeSpec struct {
+	TenantId       string              `json:"tenantId"`
+	Name           database.NullString `json:"name"`
+	ParentTenantId string              `json:"parentTenantId,omitempty"`
+	Depth          int                 `json:"depth"`
+}
+
+// TenantHierarchySettingsSpec type for configuring which TenantRelations the
+// admins of a parent tenant inherit on its child tenants. Admins are only
+// inherited through more than one level of the hierarchy if admin is one of them.
+type TenantHierarchySettingsSpec struct {
+	InheritedRelations []string `json:"inheritedRelations"`
+}
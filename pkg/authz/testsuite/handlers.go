@@ -0,0 +1,46 @@
+package authz
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+// MaxTestSuiteBytes is the largest test suite accepted by the run endpoint
+const MaxTestSuiteBytes = 10 << 20
+
+func (svc TestSuiteService) Routes() []service.Route {
+	return []service.Route{
+		// run
+		{
+			Pattern: "/v1/test-suites/run",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, RunHandler),
+		},
+	}
+}
+
+func RunHandler(svc TestSuiteService, w http.ResponseWriter, r *http.Request) error {
+	contents, err := io.ReadAll(io.LimitReader(r.Body, MaxTestSuiteBytes+1))
+	if err != nil {
+		return service.NewInvalidRequestError("Invalid request body")
+	}
+
+	if len(contents) > MaxTestSuiteBytes {
+		return service.NewInvalidRequestError("Test suite must be at most 10MB")
+	}
+
+	testSuite, err := ParseTestSuite(contents)
+	if err != nil {
+		return err
+	}
+
+	result, err := svc.Run(r.Context(), *testSuite)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, result)
+	return nil
+}
@@ -0,0 +1,81 @@
+package authz
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+// scratchNamespace prefixes the object types of a test suite so they can't
+// collide with the datastore's own object types
+type scratchNamespace struct {
+	prefix string
+}
+
+func newScratchNamespace() scratchNamespace {
+	return scratchNamespace{
+		prefix: fmt.Sprintf("test-%s-", strings.Split(uuid.New().String(), "-")[0]),
+	}
+}
+
+func (namespace scratchNamespace) objectType(objectType string) string {
+	return namespace.prefix + objectType
+}
+
+func (namespace scratchNamespace) objectTypeSpec(spec objecttype.ObjectTypeSpec) objecttype.ObjectTypeSpec {
+	relations := make(map[string]objecttype.RelationRule, len(spec.Relations))
+	for relation, rule := range spec.Relations {
+		relations[relation] = namespace.relationRule(rule)
+	}
+
+	spec.Type = namespace.objectType(spec.Type)
+	spec.Relations = relations
+	return spec
+}
+
+func (namespace scratchNamespace) relationRule(rule objecttype.RelationRule) objecttype.RelationRule {
+	if rule.OfType != "" {
+		rule.OfType = namespace.objectType(rule.OfType)
+	}
+
+	if len(rule.Rules) > 0 {
+		rules := make([]objecttype.RelationRule, 0, len(rule.Rules))
+		for _, subRule := range rule.Rules {
+			rules = append(rules, namespace.relationRule(subRule))
+		}
+
+		rule.Rules = rules
+	}
+
+	return rule
+}
+
+func (namespace scratchNamespace) warrantSpec(spec warrant.WarrantSpec) warrant.WarrantSpec {
+	spec.ObjectType = namespace.objectType(spec.ObjectType)
+	spec.Subject = &warrant.SubjectSpec{
+		ObjectType: namespace.objectType(spec.Subject.ObjectType),
+		ObjectId:   spec.Subject.ObjectId,
+		Relation:   spec.Subject.Relation,
+	}
+	return spec
+}
+
+// strip removes the namespace's prefix from msg
+func (namespace scratchNamespace) strip(msg string) string {
+	return strings.ReplaceAll(msg, namespace.prefix, "")
+}
+
+// clientError prefixes client errors with msg and strips the namespace's prefix from them
+func (namespace scratchNamespace) clientError(msg string, err error) error {
+	apiError, ok := err.(service.Error)
+	if !ok || apiError.GetStatus() >= http.StatusInternalServerError {
+		return err
+	}
+
+	return service.NewInvalidRequestError(fmt.Sprintf("%s: %s", msg, namespace.strip(err.Error())))
+}
@@ -0,0 +1,248 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	check "github.com/warrant-dev/warrant/pkg/authz/check"
+	manifest "github.com/warrant-dev/warrant/pkg/authz/manifest"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/event"
+	"github.com/warrant-dev/warrant/pkg/middleware"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+const (
+	MaxTestSuiteWarrants   = 1000
+	MaxTestSuiteAssertions = 1000
+
+	// NOTE: matches the size of the objectType columns
+	maxObjectTypeLength = 64
+)
+
+// errRollbackTestSuite is returned from the test suite's transaction so it is always rolled back
+var errRollbackTestSuite = errors.New("rolling back test suite")
+
+type TestSuiteService struct {
+	service.BaseService
+	objectTypeSvc objecttype.ObjectTypeService
+	warrantSvc    warrant.WarrantService
+	checkSvc      check.CheckService
+}
+
+func NewService(env service.Env, objectTypeSvc objecttype.ObjectTypeService, warrantSvc warrant.WarrantService, checkSvc check.CheckService) TestSuiteService {
+	return TestSuiteService{
+		BaseService:   service.NewBaseService(env),
+		objectTypeSvc: objectTypeSvc,
+		warrantSvc:    warrantSvc,
+		checkSvc:      checkSvc,
+	}
+}
+
+// Run evaluates the assertions of a test suite using the CheckService in a
+// scratch datastore: the suite's object types and warrants are created in a
+// transaction that is always rolled back, under object type names with a
+// random prefix so the suite never sees the datastore's own warrants. No
+// events are tracked for the suite. Run must not be called within a
+// transaction, since the suite would then be committed along with it.
+func (svc TestSuiteService) Run(ctx context.Context, testSuite TestSuiteSpec) (*TestSuiteResultSpec, error) {
+	if len(testSuite.Warrants) > MaxTestSuiteWarrants {
+		return nil, service.NewInvalidParameterError("warrants", fmt.Sprintf("must have at most %d warrants", MaxTestSuiteWarrants))
+	}
+
+	if len(testSuite.Assertions) > MaxTestSuiteAssertions {
+		return nil, service.NewInvalidParameterError("assertions", fmt.Sprintf("must have at most %d assertions", MaxTestSuiteAssertions))
+	}
+
+	assertions := make([]AssertionSpec, 0, len(testSuite.Assertions))
+	for i, assertion := range testSuite.Assertions {
+		assertionSpec, err := ParseAssertion(assertion)
+		if err != nil {
+			return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid assertion %d: %s", i+1, err.Error()))
+		}
+
+		assertions = append(assertions, *assertionSpec)
+	}
+
+	objectTypeSpecs, err := svc.getObjectTypes(ctx, testSuite)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := newScratchNamespace()
+	for _, objectTypeSpec := range objectTypeSpecs {
+		if len(namespace.objectType(objectTypeSpec.Type)) > maxObjectTypeLength {
+			return nil, service.NewInvalidRequestError(fmt.Sprintf("Object type %s is too long to be tested. Object types in test suites must be at most %d characters.", objectTypeSpec.Type, maxObjectTypeLength-len(namespace.prefix)))
+		}
+	}
+
+	result := TestSuiteResultSpec{
+		Name:       testSuite.Name,
+		Assertions: make([]AssertionResultSpec, 0, len(assertions)),
+	}
+	err = svc.Env().DB().WithinTransaction(event.WithoutTracking(ctx), func(txCtx context.Context) error {
+		for _, objectTypeSpec := range objectTypeSpecs {
+			_, err := svc.objectTypeSvc.Create(txCtx, namespace.objectTypeSpec(objectTypeSpec))
+			if err != nil {
+				return namespace.clientError(fmt.Sprintf("Object type %s could not be created", objectTypeSpec.Type), err)
+			}
+		}
+
+		// NOTE: the wildcard object id of each object type is a candidate result
+		// of queries along with each object (or wildcard) that appears in the
+		// fixture warrants, so a query returning objects outside the fixtures
+		// returns the wildcard instead of leaving them out
+		objectIds := make(map[string][]string)
+		seen := make(map[string]bool)
+		for _, objectTypeSpec := range objectTypeSpecs {
+			seen[fmt.Sprintf("%s:%s", objectTypeSpec.Type, objecttype.ObjectIdWildcard)] = true
+			objectIds[objectTypeSpec.Type] = append(objectIds[objectTypeSpec.Type], objecttype.ObjectIdWildcard)
+		}
+
+		for i, manifestWarrant := range testSuite.Warrants {
+			warrantSpec := manifestWarrant.WarrantSpec
+			_, err := svc.warrantSvc.Create(txCtx, namespace.warrantSpec(warrantSpec))
+			if err != nil {
+				return namespace.clientError(fmt.Sprintf("Warrant %d (%s) could not be created", i+1, warrantSpec.String()), err)
+			}
+
+			for _, object := range [][2]string{{warrantSpec.ObjectType, warrantSpec.ObjectId}, {warrantSpec.Subject.ObjectType, warrantSpec.Subject.ObjectId}} {
+				key := fmt.Sprintf("%s:%s", object[0], object[1])
+				if seen[key] {
+					continue
+				}
+
+				seen[key] = true
+				objectIds[object[0]] = append(objectIds[object[0]], object[1])
+			}
+		}
+
+		for i, assertion := range assertions {
+			assertionResult, err := svc.evaluate(txCtx, namespace, assertion, objectIds[assertion.Warrant.ObjectType])
+			if err != nil {
+				return err
+			}
+
+			assertionResult.Assertion = testSuite.Assertions[i]
+			if assertionResult.Passed {
+				result.NumPassed++
+			} else {
+				result.NumFailed++
+			}
+
+			result.Assertions = append(result.Assertions, *assertionResult)
+		}
+
+		return errRollbackTestSuite
+	})
+	if err != errRollbackTestSuite {
+		return nil, err
+	}
+
+	result.Passed = result.NumFailed == 0
+	return &result, nil
+}
+
+// evaluate runs a single assertion. Client errors (e.g. checking a relation
+// that does not exist) fail the assertion instead of the suite. The result of
+// a query is every candidate object the subject has the relation on.
+func (svc TestSuiteService) evaluate(ctx context.Context, namespace scratchNamespace, assertion AssertionSpec, candidateObjectIds []string) (*AssertionResultSpec, error) {
+	assertionResult := AssertionResultSpec{
+		Expected: assertion.Expected,
+	}
+
+	var actual interface{}
+	var err error
+	switch assertion.Type {
+	case AssertionTypeCheck:
+		actual, _, err = svc.checkSvc.Check(ctx, nil, check.CheckSpec{
+			WarrantSpec: namespace.warrantSpec(assertion.Warrant),
+		})
+	case AssertionTypeQuery:
+		objects := make([]string, 0)
+		for _, objectId := range candidateObjectIds {
+			warrantSpec := assertion.Warrant
+			warrantSpec.ObjectId = objectId
+			var match bool
+			match, _, err = svc.checkSvc.Check(ctx, nil, check.CheckSpec{
+				WarrantSpec: namespace.warrantSpec(warrantSpec),
+			})
+			if err != nil {
+				break
+			}
+
+			if match {
+				objects = append(objects, fmt.Sprintf("%s:%s", warrantSpec.ObjectType, objectId))
+			}
+		}
+
+		sort.Strings(objects)
+		actual = objects
+	}
+	if err != nil {
+		apiError, ok := err.(service.Error)
+		if !ok || apiError.GetStatus() >= http.StatusInternalServerError {
+			return nil, err
+		}
+
+		assertionResult.Error = namespace.strip(err.Error())
+		return &assertionResult, nil
+	}
+
+	assertionResult.Actual = actual
+	assertionResult.Passed = reflect.DeepEqual(assertion.Expected, actual)
+	return &assertionResult, nil
+}
+
+// getObjectTypes returns the object types of the test suite, or the
+// datastore's current object types if the suite has none, along with any
+// built-in object types the suite does not declare
+func (svc TestSuiteService) getObjectTypes(ctx context.Context, testSuite TestSuiteSpec) ([]objecttype.ObjectTypeSpec, error) {
+	objectTypeSpecs := testSuite.ObjectTypes
+	if len(objectTypeSpecs) == 0 {
+		listParams := middleware.ListParams{
+			Page:      1,
+			Limit:     manifest.ListBatchSize,
+			SortBy:    objecttype.ObjectTypeListParamParser{}.GetDefaultSortBy(),
+			SortOrder: middleware.SortOrderAsc,
+		}
+		for {
+			page, err := svc.objectTypeSvc.List(ctx, listParams)
+			if err != nil {
+				return nil, err
+			}
+
+			objectTypeSpecs = append(objectTypeSpecs, page...)
+			if len(page) < listParams.Limit {
+				break
+			}
+
+			cursor := page[len(page)-1].ToCursor(listParams.SortBy)
+			listParams.AfterId = cursor.ID
+			listParams.AfterValue = cursor.Value
+		}
+	}
+
+	defaultManifest, err := manifest.DefaultManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	declared := make(map[string]bool)
+	for _, objectTypeSpec := range objectTypeSpecs {
+		declared[objectTypeSpec.Type] = true
+	}
+
+	for _, objectTypeSpec := range defaultManifest.ObjectTypes {
+		if !declared[objectTypeSpec.Type] {
+			objectTypeSpecs = append(objectTypeSpecs, objectTypeSpec)
+		}
+	}
+
+	return objectTypeSpecs, nil
+}
@@ -0,0 +1,177 @@
+package authz
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	manifest "github.com/warrant-dev/warrant/pkg/authz/manifest"
+	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	warrant "github.com/warrant-dev/warrant/pkg/authz/warrant"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+const (
+	AssertionTypeCheck = "check"
+	AssertionTypeQuery = "query"
+)
+
+// TestSuiteSpec type for a set of fixture warrants and assertions about the
+// access they grant under an object type set. If no object types are given,
+// the suite runs against the datastore's current object types.
+type TestSuiteSpec struct {
+	Name        string                         `json:"name,omitempty"`
+	ObjectTypes []objecttype.ObjectTypeSpec    `json:"objectTypes,omitempty"`
+	Warrants    []manifest.ManifestWarrantSpec `json:"warrants,omitempty"`
+	Assertions  []string                       `json:"assertions" validate:"required,min=1"`
+}
+
+// AssertionSpec is an assertion parsed from one of the following formats:
+//
+//	check type:id#relation@subject[context] == true|false
+//	query type#relation@subject[context] == [type:id, ...]
+type AssertionSpec struct {
+	Type     string
+	Warrant  warrant.WarrantSpec // NOTE: the objectId of query assertions is unset
+	Expected interface{}         // NOTE: a bool for checks and a sorted []string of objects for queries
+}
+
+type AssertionResultSpec struct {
+	Assertion string      `json:"assertion"`
+	Passed    bool        `json:"passed"`
+	Expected  interface{} `json:"expected"`
+	Actual    interface{} `json:"actual,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+type TestSuiteResultSpec struct {
+	Name       string                `json:"name,omitempty"`
+	Passed     bool                  `json:"passed"`
+	NumPassed  int                   `json:"numPassed"`
+	NumFailed  int                   `json:"numFailed"`
+	Assertions []AssertionResultSpec `json:"assertions"`
+}
+
+// ParseTestSuite parses and validates a test suite given in YAML or JSON
+func ParseTestSuite(contents []byte) (*TestSuiteSpec, error) {
+	jsonContents, err := service.YAMLToJSON(contents)
+	if err != nil {
+		return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid test suite: %s", err.Error()))
+	}
+
+	var testSuite TestSuiteSpec
+	err = json.Unmarshal(jsonContents, &testSuite)
+	if err != nil {
+		switch err := err.(type) {
+		case *json.UnmarshalTypeError:
+			return nil, service.NewInvalidParameterError(err.Field, "has an invalid type")
+		default:
+			return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid test suite: %s", err.Error()))
+		}
+	}
+
+	err = service.ValidateStruct(&testSuite)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range testSuite.ObjectTypes {
+		err = service.ValidateStruct(&testSuite.ObjectTypes[i])
+		if err != nil {
+			return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid object type %d: %s", i+1, err.Error()))
+		}
+	}
+
+	for i := range testSuite.Warrants {
+		warrantSpec := &testSuite.Warrants[i].WarrantSpec
+		if warrantSpec.Subject == nil {
+			return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid warrant %d: subject is required", i+1))
+		}
+
+		err = service.ValidateStruct(warrantSpec)
+		if err != nil {
+			return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid warrant %d: %s", i+1, err.Error()))
+		}
+	}
+
+	for i, assertion := range testSuite.Assertions {
+		_, err = ParseAssertion(assertion)
+		if err != nil {
+			return nil, service.NewInvalidRequestError(fmt.Sprintf("Invalid assertion %d: %s", i+1, err.Error()))
+		}
+	}
+
+	return &testSuite, nil
+}
+
+// ParseAssertion parses an assertion in one of the formats described on AssertionSpec
+func ParseAssertion(assertion string) (*AssertionSpec, error) {
+	assertionType, rest, found := strings.Cut(strings.TrimSpace(assertion), " ")
+	if !found {
+		return nil, fmt.Errorf("must be in the format 'check <warrant> == true|false' or 'query <type>#<relation>@<subject> == [<objects>]'")
+	}
+
+	warrantString, expected, found := strings.Cut(rest, "==")
+	if !found {
+		return nil, fmt.Errorf("must compare the result of the %s using ==", assertionType)
+	}
+
+	warrantString = strings.TrimSpace(warrantString)
+	expected = strings.TrimSpace(expected)
+	switch assertionType {
+	case AssertionTypeCheck:
+		warrantSpec, err := warrant.StringToWarrantSpec(warrantString)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a warrant in the format type:id#relation@subject[context]", warrantString)
+		}
+
+		expectedResult, err := strconv.ParseBool(expected)
+		if err != nil {
+			return nil, fmt.Errorf("the expected result of a check must be true or false")
+		}
+
+		return &AssertionSpec{
+			Type:     AssertionTypeCheck,
+			Warrant:  *warrantSpec,
+			Expected: expectedResult,
+		}, nil
+	case AssertionTypeQuery:
+		// NOTE: a placeholder objectId is added so the query parses as a warrant
+		objectTypeAndRelation, subject, found := strings.Cut(warrantString, "@")
+		objectType, relation, _ := strings.Cut(objectTypeAndRelation, "#")
+		warrantSpec, err := warrant.StringToWarrantSpec(fmt.Sprintf("%s:%s#%s@%s", objectType, objecttype.ObjectIdWildcard, relation, subject))
+		if !found || strings.Contains(objectType, ":") || err != nil {
+			return nil, fmt.Errorf("%s must be a query in the format type#relation@subject[context]", warrantString)
+		}
+
+		warrantSpec.ObjectId = ""
+		if !strings.HasPrefix(expected, "[") || !strings.HasSuffix(expected, "]") {
+			return nil, fmt.Errorf("the expected result of a query must be a list of objects (e.g. [%s:1, %s:2])", objectType, objectType)
+		}
+
+		expectedObjects := make([]string, 0)
+		for _, expectedObject := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(expected, "["), "]"), ",") {
+			expectedObject = strings.TrimSpace(expectedObject)
+			if expectedObject == "" {
+				continue
+			}
+
+			if !strings.HasPrefix(expectedObject, fmt.Sprintf("%s:", objectType)) {
+				return nil, fmt.Errorf("%s must be an object of type %s", expectedObject, objectType)
+			}
+
+			expectedObjects = append(expectedObjects, expectedObject)
+		}
+
+		sort.Strings(expectedObjects)
+		return &AssertionSpec{
+			Type:     AssertionTypeQuery,
+			Warrant:  *warrantSpec,
+			Expected: expectedObjects,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%s is not a valid assertion type. Must be one of %s, %s", assertionType, AssertionTypeCheck, AssertionTypeQuery)
+	}
+}
@@ -40,6 +40,13 @@ func (svc UserService) Routes() []service.Route {
 			Handler: service.NewRouteHandler(svc, DeleteHandler),
 		},
 
+		// restore
+		{
+			Pattern: "/v1/users/{userId}/restore",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, RestoreHandler),
+		},
+
 		// update
 		{
 			Pattern: "/v1/users/{userId}",
@@ -93,6 +100,7 @@ func ListHandler(svc UserService, w http.ResponseWriter, r *http.Request) error
 		return err
 	}
 
+	middleware.SetCursorHeaders(w, listParams, users)
 	service.SendJSONResponse(w, users)
 	return nil
 }
@@ -135,3 +143,19 @@ func DeleteHandler(svc UserService, w http.ResponseWriter, r *http.Request) erro
 	w.WriteHeader(http.StatusOK)
 	return nil
 }
+
+func RestoreHandler(svc UserService, w http.ResponseWriter, r *http.Request) error {
+	userIdParam := mux.Vars(r)["userId"]
+	userId, err := url.QueryUnescape(userIdParam)
+	if err != nil {
+		return service.NewInvalidParameterError("userId", "")
+	}
+
+	restoredUser, err := svc.RestoreByUserId(r.Context(), userId)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, restoredUser)
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/mail"
 	"time"
+
+	"github.com/warrant-dev/warrant/pkg/middleware"
 )
 
 type UserListParamParser struct{}
@@ -46,3 +48,14 @@ func (parser UserListParamParser) ParseValue(val string, sortBy string) (interfa
 		return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
 	}
 }
+
+func (spec UserSpec) ToCursor(sortBy string) middleware.Cursor {
+	switch sortBy {
+	case "createdAt":
+		return middleware.NewCursor(spec.UserId, spec.CreatedAt)
+	case "email":
+		return middleware.NewCursor(spec.UserId, spec.Email.String)
+	default:
+		return middleware.NewCursor(spec.UserId, nil)
+	}
+}
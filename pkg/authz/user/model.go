@@ -15,17 +15,19 @@ type Model interface {
 	GetCreatedAt() time.Time
 	GetUpdatedAt() time.Time
 	GetDeletedAt() database.NullTime
+	GetDeletionBatchId() string
 	ToUserSpec() *UserSpec
 }
 
 type User struct {
-	ID        int64               `mysql:"id" postgres:"id"`
-	ObjectId  int64               `mysql:"objectId" postgres:"object_id"`
-	UserId    string              `mysql:"userId" postgres:"user_id"`
-	Email     database.NullString `mysql:"email" postgres:"email"`
-	CreatedAt time.Time           `mysql:"createdAt" postgres:"created_at"`
-	UpdatedAt time.Time           `mysql:"updatedAt" postgres:"updated_at"`
-	DeletedAt database.NullTime   `mysql:"deletedAt" postgres:"deleted_at"`
+	ID              int64               `mysql:"id" postgres:"id"`
+	ObjectId        int64               `mysql:"objectId" postgres:"object_id"`
+	UserId          string              `mysql:"userId" postgres:"user_id"`
+	Email           database.NullString `mysql:"email" postgres:"email"`
+	CreatedAt       time.Time           `mysql:"createdAt" postgres:"created_at"`
+	UpdatedAt       time.Time           `mysql:"updatedAt" postgres:"updated_at"`
+	DeletedAt       database.NullTime   `mysql:"deletedAt" postgres:"deleted_at"`
+	DeletionBatchId database.NullString `mysql:"deletionBatchId" postgres:"deletion_batch_id"`
 }
 
 func (user User) GetID() int64 {
@@ -60,6 +62,10 @@ func (user User) GetDeletedAt() database.NullTime {
 	return user.DeletedAt
 }
 
+func (user User) GetDeletionBatchId() string {
+	return user.DeletionBatchId.String
+}
+
 func (user User) ToUserSpec() *UserSpec {
 	return &UserSpec{
 		UserId:    user.UserId,
@@ -35,7 +35,8 @@ func (repo MySQLRepository) Create(ctx context.Context, model Model) (int64, err
 				objectId = ?,
 				email = ?,
 				createdAt = CURRENT_TIMESTAMP(6),
-				deletedAt = NULL
+				deletedAt = NULL,
+				deletionBatchId = NULL
 		`,
 		model.GetUserId(),
 		model.GetObjectId(),
@@ -172,19 +173,20 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND userId < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND userId > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	if listParams.SortBy != "userId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s, userId %s LIMIT ?", query, listParams.SortBy, listParams.SortOrder, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s, userId %s LIMIT ?", query, listParams.SortBy, sortOrder, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY userId %s LIMIT ?", query, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY userId %s LIMIT ?", query, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -207,6 +209,8 @@ func (repo MySQLRepository) List(ctx context.Context, listParams middleware.List
 		models = append(models, &users[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -231,17 +235,20 @@ func (repo MySQLRepository) UpdateByUserId(ctx context.Context, userId string, m
 	return nil
 }
 
-func (repo MySQLRepository) DeleteByUserId(ctx context.Context, userId string) error {
+func (repo MySQLRepository) DeleteByUserId(ctx context.Context, userId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE user
-			SET deletedAt = ?
+			SET
+				deletedAt = ?,
+				deletionBatchId = ?
 			WHERE
 				userId = ? AND
 				deletedAt IS NULL
 		`,
 		time.Now().UTC(),
+		deletionBatchId,
 		userId,
 	)
 	if err != nil {
@@ -255,3 +262,53 @@ func (repo MySQLRepository) DeleteByUserId(ctx context.Context, userId string) e
 
 	return nil
 }
+
+func (repo MySQLRepository) GetDeletedByUserId(ctx context.Context, userId string) (Model, error) {
+	var user User
+	err := repo.DB.GetContext(
+		ctx,
+		&user,
+		`
+			SELECT id, objectId, userId, email, createdAt, updatedAt, deletedAt, deletionBatchId
+			FROM user
+			WHERE
+				userId = ? AND
+				deletedAt IS NOT NULL AND
+				deletionBatchId IS NOT NULL
+		`,
+		userId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, service.NewRecordNotFoundError("User", userId)
+		default:
+			return nil, err
+		}
+	}
+
+	return &user, nil
+}
+
+func (repo MySQLRepository) RestoreByUserId(ctx context.Context, userId string, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE user
+			SET
+				deletedAt = NULL,
+				deletionBatchId = NULL
+			WHERE
+				userId = ? AND
+				deletionBatchId = ? AND
+				deletedAt IS NOT NULL
+		`,
+		userId,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error restoring user %s", userId))
+	}
+
+	return nil
+}
@@ -38,7 +38,8 @@ func (repo PostgresRepository) Create(ctx context.Context, model Model) (int64,
 				object_id = ?,
 				email = ?,
 				created_at = CURRENT_TIMESTAMP(6),
-				deleted_at = NULL
+				deleted_at = NULL,
+				deletion_batch_id = NULL
 			RETURNING id
 		`,
 		model.GetUserId(),
@@ -172,24 +173,25 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 		} else {
 			if listParams.SortOrder == middleware.SortOrderAsc {
 				query = fmt.Sprintf("%s AND user_id < ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			} else {
 				query = fmt.Sprintf("%s AND user_id > ?", query)
-				replacements = append(replacements, listParams.AfterId)
+				replacements = append(replacements, listParams.BeforeId)
 			}
 		}
 	}
 
+	sortOrder := listParams.QuerySortOrder()
 	nullSortClause := "NULLS LAST"
-	if listParams.SortOrder == middleware.SortOrderAsc {
+	if sortOrder == middleware.SortOrderAsc {
 		nullSortClause = "NULLS FIRST"
 	}
 
 	if listParams.SortBy != "userId" {
-		query = fmt.Sprintf("%s ORDER BY %s %s %s, user_id %s LIMIT ?", query, sortBy, listParams.SortOrder, nullSortClause, listParams.SortOrder)
+		query = fmt.Sprintf("%s ORDER BY %s %s %s, user_id %s LIMIT ?", query, sortBy, sortOrder, nullSortClause, sortOrder)
 		replacements = append(replacements, listParams.Limit)
 	} else {
-		query = fmt.Sprintf("%s ORDER BY user_id %s %s LIMIT ?", query, listParams.SortOrder, nullSortClause)
+		query = fmt.Sprintf("%s ORDER BY user_id %s %s LIMIT ?", query, sortOrder, nullSortClause)
 		replacements = append(replacements, listParams.Limit)
 	}
 
@@ -212,6 +214,8 @@ func (repo PostgresRepository) List(ctx context.Context, listParams middleware.L
 		models = append(models, &users[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
@@ -236,17 +240,20 @@ func (repo PostgresRepository) UpdateByUserId(ctx context.Context, userId string
 	return nil
 }
 
-func (repo PostgresRepository) DeleteByUserId(ctx context.Context, userId string) error {
+func (repo PostgresRepository) DeleteByUserId(ctx context.Context, userId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE "user"
-			SET deleted_at = ?
+			SET
+				deleted_at = ?,
+				deletion_batch_id = ?
 			WHERE
 				user_id = ? AND
 				deleted_at IS NULL
 		`,
 		time.Now().UTC(),
+		deletionBatchId,
 		userId,
 	)
 	if err != nil {
@@ -260,3 +267,53 @@ func (repo PostgresRepository) DeleteByUserId(ctx context.Context, userId string
 
 	return nil
 }
+
+func (repo PostgresRepository) GetDeletedByUserId(ctx context.Context, userId string) (Model, error) {
+	var user User
+	err := repo.DB.GetContext(
+		ctx,
+		&user,
+		`
+			SELECT id, object_id, user_id, email, created_at, updated_at, deleted_at, deletion_batch_id
+			FROM "user"
+			WHERE
+				user_id = ? AND
+				deleted_at IS NOT NULL AND
+				deletion_batch_id IS NOT NULL
+		`,
+		userId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return nil, service.NewRecordNotFoundError("User", userId)
+		default:
+			return nil, err
+		}
+	}
+
+	return &user, nil
+}
+
+func (repo PostgresRepository) RestoreByUserId(ctx context.Context, userId string, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE "user"
+			SET
+				deleted_at = NULL,
+				deletion_batch_id = NULL
+			WHERE
+				user_id = ? AND
+				deletion_batch_id = ? AND
+				deleted_at IS NOT NULL
+		`,
+		userId,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Error restoring user %s", userId))
+	}
+
+	return nil
+}
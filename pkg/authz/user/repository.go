@@ -14,7 +14,9 @@ type UserRepository interface {
 	GetByUserId(ctx context.Context, userId string) (Model, error)
 	List(ctx context.Context, listParams middleware.ListParams) ([]Model, error)
 	UpdateByUserId(ctx context.Context, userId string, user Model) error
-	DeleteByUserId(ctx context.Context, userId string) error
+	DeleteByUserId(ctx context.Context, userId string, deletionBatchId string) error
+	GetDeletedByUserId(ctx context.Context, userId string) (Model, error)
+	RestoreByUserId(ctx context.Context, userId string, deletionBatchId string) error
 }
 
 func NewRepository(db database.Database) (UserRepository, error) {
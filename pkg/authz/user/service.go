@@ -136,12 +136,15 @@ func (svc UserService) DeleteByUserId(ctx context.Context, userId string) error
 			return err
 		}
 
-		err = userRepository.DeleteByUserId(txCtx, userId)
+		// NOTE: the user, its object, and its warrants are tagged with the
+		// same deletion batch id so they can be restored together
+		deletionBatchId := uuid.New().String()
+		err = userRepository.DeleteByUserId(txCtx, userId, deletionBatchId)
 		if err != nil {
 			return err
 		}
 
-		err = svc.objectSvc.DeleteByObjectTypeAndId(txCtx, objecttype.ObjectTypeUser, userId)
+		err = svc.objectSvc.DeleteByObjectTypeAndIdInBatch(txCtx, objecttype.ObjectTypeUser, userId, deletionBatchId)
 		if err != nil {
 			return err
 		}
@@ -153,6 +156,50 @@ func (svc UserService) DeleteByUserId(ctx context.Context, userId string) error
 	return err
 }
 
+// RestoreByUserId restores a deleted user along with its object and the
+// warrants that were deleted with it. Warrants deleted separately beforehand
+// are not restored.
+func (svc UserService) RestoreByUserId(ctx context.Context, userId string) (*UserSpec, error) {
+	var restoredUser Model
+	err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		userRepository, err := NewRepository(svc.Env().DB())
+		if err != nil {
+			return err
+		}
+
+		deletedUser, err := userRepository.GetDeletedByUserId(txCtx, userId)
+		if err != nil {
+			return err
+		}
+
+		err = svc.objectSvc.ValidateRestorable(objecttype.ObjectTypeUser, userId, deletedUser.GetDeletedAt())
+		if err != nil {
+			return err
+		}
+
+		deletionBatchId := deletedUser.GetDeletionBatchId()
+		err = userRepository.RestoreByUserId(txCtx, userId, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		err = svc.objectSvc.RestoreByObjectTypeAndId(txCtx, objecttype.ObjectTypeUser, userId, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		restoredUser, err = userRepository.GetByUserId(txCtx, userId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restoredUserSpec := restoredUser.ToUserSpec()
+	svc.eventSvc.TrackResourceRestored(ctx, ResourceTypeUser, userId, restoredUserSpec)
+	return restoredUserSpec, nil
+}
+
 func validateOrGenerateUserIdInSpec(userSpec *UserSpec) error {
 	userIdRegExp := regexp.MustCompile(`^[a-zA-Z0-9_\-\.@\|]+$`)
 	if userSpec.UserId != "" {
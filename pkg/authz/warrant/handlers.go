@@ -1,8 +1,14 @@
 package authz
 
 import (
+	"bufio"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/rs/zerolog/log"
+	"github.com/warrant-dev/warrant/pkg/context"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
 )
@@ -37,6 +43,18 @@ func (svc WarrantService) Routes() []service.Route {
 				service.NewRouteHandler(svc, DeleteHandler),
 			),
 		},
+
+		// text
+		{
+			Pattern: "/v1/warrants/text",
+			Method:  "POST",
+			Handler: service.NewRouteHandler(svc, ApplyTextHandler),
+		},
+		{
+			Pattern: "/v1/warrants/text",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, ExportTextHandler),
+		},
 	}
 }
 
@@ -58,28 +76,39 @@ func CreateHandler(svc WarrantService, w http.ResponseWriter, r *http.Request) e
 
 func ListHandler(svc WarrantService, w http.ResponseWriter, r *http.Request) error {
 	listParams := middleware.GetListParamsFromContext(r.Context())
-	queryParams := r.URL.Query()
-	filters := FilterOptions{
-		ObjectType: queryParams.Get("objectType"),
-		ObjectId:   queryParams.Get("objectId"),
-		Relation:   queryParams.Get("relation"),
-		Subject: &SubjectSpec{
-			ObjectType: queryParams.Get("subjectType"),
-			ObjectId:   queryParams.Get("subjectId"),
-			Relation:   queryParams.Get("subjectRelation"),
-		},
+	filters, err := parseFilterOptions(r)
+	if err != nil {
+		return err
+	}
+
+	if listParams.AfterId != "" {
+		if _, err := strconv.ParseInt(listParams.AfterId, 10, 64); err != nil {
+			return service.NewInvalidParameterError("afterId", "must be a valid warrant cursor")
+		}
 	}
 
-	warrants, err := svc.List(r.Context(), &filters, listParams)
+	if listParams.BeforeId != "" {
+		if _, err := strconv.ParseInt(listParams.BeforeId, 10, 64); err != nil {
+			return service.NewInvalidParameterError("beforeId", "must be a valid warrant cursor")
+		}
+	}
+
+	warrants, err := svc.List(r.Context(), filters, listParams)
 	if err != nil {
 		return err
 	}
 
+	middleware.SetCursorHeaders(w, listParams, warrants)
 	service.SendJSONResponse(w, warrants)
 	return nil
 }
 
 func DeleteHandler(svc WarrantService, w http.ResponseWriter, r *http.Request) error {
+	// Delete all warrants matching the filters in the query params if any are given
+	if isDeleteAllRequest(r) {
+		return deleteAllHandler(svc, w, r)
+	}
+
 	var warrantSpec WarrantSpec
 	err := service.ParseJSONBody(r.Body, &warrantSpec)
 	if err != nil {
@@ -95,3 +124,171 @@ func DeleteHandler(svc WarrantService, w http.ResponseWriter, r *http.Request) e
 	w.WriteHeader(http.StatusOK)
 	return nil
 }
+
+func deleteAllHandler(svc WarrantService, w http.ResponseWriter, r *http.Request) error {
+	filters, err := parseFilterOptions(r)
+	if err != nil {
+		return err
+	}
+
+	if filters.ObjectType == "" && filters.ObjectId == "" && len(filters.ObjectIds) == 0 && filters.Relation == "" && filters.Subject == nil && len(filters.SubjectIds) == 0 && filters.Context == nil {
+		return service.NewInvalidRequestError("At least one filter is required to delete warrants")
+	}
+
+	dryRun := false
+	if r.URL.Query().Has("dryRun") {
+		dryRun, err = strconv.ParseBool(r.URL.Query().Get("dryRun"))
+		if err != nil {
+			return service.NewInvalidParameterError("dryRun", "must be true or false")
+		}
+	}
+
+	result, err := svc.DeleteAll(r.Context(), filters, dryRun)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, result)
+	return nil
+}
+
+func ApplyTextHandler(svc WarrantService, w http.ResponseWriter, r *http.Request) error {
+	var err error
+	dryRun := false
+	if r.URL.Query().Has("dryRun") {
+		dryRun, err = strconv.ParseBool(r.URL.Query().Get("dryRun"))
+		if err != nil {
+			return service.NewInvalidParameterError("dryRun", "must be true or false")
+		}
+	}
+
+	// NOTE: the warrant text can also be sent as a JSON object (e.g. {"text": "..."})
+	// by clients that only send JSON. Warrant text never starts with '{'.
+	body := bufio.NewReader(r.Body)
+	var warrantText io.Reader = body
+	firstByte, peekErr := body.Peek(1)
+	if peekErr == nil && firstByte[0] == '{' {
+		var warrantsTextSpec WarrantsTextSpec
+		err = service.ParseJSONBody(body, &warrantsTextSpec)
+		if err != nil {
+			return err
+		}
+
+		warrantText = strings.NewReader(warrantsTextSpec.Text)
+	}
+
+	result, err := svc.ApplyText(r.Context(), warrantText, dryRun)
+	if err != nil {
+		return err
+	}
+
+	service.SendJSONResponse(w, result)
+	return nil
+}
+
+func ExportTextHandler(svc WarrantService, w http.ResponseWriter, r *http.Request) error {
+	filters, err := parseFilterOptions(r)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	err = svc.ExportText(r.Context(), filters, w)
+	if err != nil {
+		// NOTE: the response has already started, so the export is left truncated
+		log.Err(err).Msg("error exporting warrant text")
+	}
+
+	return nil
+}
+
+// isDeleteAllRequest returns true if the query params of a delete request
+// include any of the warrant filters (or dryRun). Any other query params are
+// ignored, so a request with only those deletes the warrant in its body.
+func isDeleteAllRequest(r *http.Request) bool {
+	for param := range r.URL.Query() {
+		switch {
+		case param == "objectType" || param == "objectId" || param == "relation":
+			return true
+		case param == "subjectType" || param == "subjectId" || param == "subjectRelation":
+			return true
+		case param == "context" || (strings.HasPrefix(param, "context[") && strings.HasSuffix(param, "]")):
+			return true
+		case param == "dryRun":
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseFilterOptions parses the warrant filters given in the query params of a request
+func parseFilterOptions(r *http.Request) (*FilterOptions, error) {
+	queryParams := r.URL.Query()
+	filters := FilterOptions{
+		ObjectType: queryParams.Get("objectType"),
+		Relation:   queryParams.Get("relation"),
+	}
+
+	// NOTE: objectId and subjectId can be comma separated lists of ids
+	objectIds := splitQueryParam(queryParams["objectId"])
+	if len(objectIds) == 1 {
+		filters.ObjectId = objectIds[0]
+	} else if len(objectIds) > 1 {
+		filters.ObjectIds = objectIds
+	}
+
+	subjectIds := splitQueryParam(queryParams["subjectId"])
+	if len(subjectIds) > 1 {
+		filters.SubjectIds = subjectIds
+	}
+
+	if queryParams.Get("subjectType") != "" || queryParams.Get("subjectRelation") != "" || len(subjectIds) == 1 {
+		filters.Subject = &SubjectSpec{
+			ObjectType: queryParams.Get("subjectType"),
+			Relation:   queryParams.Get("subjectRelation"),
+		}
+
+		if len(subjectIds) == 1 {
+			filters.Subject.ObjectId = subjectIds[0]
+		}
+	}
+
+	if queryParams.Get("context") != "" {
+		contextSetSpec, err := context.StringToContextSetSpec(strings.TrimSuffix(strings.TrimPrefix(queryParams.Get("context"), "["), "]"))
+		if err != nil {
+			return nil, service.NewInvalidParameterError("context", "must be a space separated list of name=value pairs (e.g. tenant=tenant-a region=us)")
+		}
+
+		filters.Context = &contextSetSpec
+	}
+
+	// NOTE: context can also be given as context[name]=value
+	for param, values := range queryParams {
+		if !strings.HasPrefix(param, "context[") || !strings.HasSuffix(param, "]") || len(values) == 0 {
+			continue
+		}
+
+		if filters.Context == nil {
+			filters.Context = &context.ContextSetSpec{}
+		}
+
+		(*filters.Context)[strings.TrimSuffix(strings.TrimPrefix(param, "context["), "]")] = values[0]
+	}
+
+	return &filters, nil
+}
+
+func splitQueryParam(values []string) []string {
+	splitValues := make([]string, 0)
+	for _, value := range values {
+		for _, splitValue := range strings.Split(value, ",") {
+			if splitValue != "" {
+				splitValues = append(splitValues, splitValue)
+			}
+		}
+	}
+
+	return splitValues
+}
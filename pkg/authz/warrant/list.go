@@ -2,7 +2,10 @@ package authz
 
 import (
 	"fmt"
+	"strconv"
 	"time"
+
+	"github.com/warrant-dev/warrant/pkg/middleware"
 )
 
 type WarrantListParamParser struct{}
@@ -28,3 +31,8 @@ func (parser WarrantListParamParser) ParseValue(val string, sortBy string) (inte
 		return nil, fmt.Errorf("must match type of selected sortBy attribute %s", sortBy)
 	}
 }
+
+// NOTE: warrants are paginated by id regardless of sortBy (see List), so warrant cursors never include a value
+func (spec WarrantSpec) ToCursor(sortBy string) middleware.Cursor {
+	return middleware.NewCursor(strconv.FormatInt(spec.ID, 10), nil)
+}
@@ -84,6 +84,7 @@ func (warrant Warrant) GetDeletedAt() database.NullTime {
 
 func (warrant Warrant) ToWarrantSpec() *WarrantSpec {
 	warrantSpec := WarrantSpec{
+		ID:         warrant.ID,
 		ObjectType: warrant.ObjectType,
 		ObjectId:   warrant.ObjectId,
 		Relation:   warrant.Relation,
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
@@ -36,9 +37,6 @@ func (repo MySQLRepository) Create(ctx context.Context, model Model) (int64, err
 				subjectRelation,
 				contextHash
 			) VALUES (?, ?, ?, ?, ?, ?, ?)
-			ON DUPLICATE KEY UPDATE
-				createdAt = CURRENT_TIMESTAMP(6),
-				deletedAt = NULL
 		`,
 		model.GetObjectType(),
 		model.GetObjectId(),
@@ -62,6 +60,11 @@ func (repo MySQLRepository) Create(ctx context.Context, model Model) (int64, err
 		return 0, err
 	}
 
+	err = repo.openHistory(ctx, "id = ?", newWarrantId)
+	if err != nil {
+		return 0, err
+	}
+
 	return newWarrantId, nil
 }
 
@@ -70,12 +73,11 @@ func (repo MySQLRepository) DeleteById(ctx context.Context, id int64) error {
 		ctx,
 		`
 			UPDATE warrant
-			SET deletedAt = ?
+			SET deletedAt = CURRENT_TIMESTAMP(6)
 			WHERE
 				id = ? AND
 				deletedAt IS NULL
 		`,
-		time.Now().UTC(),
 		id,
 	)
 	if err != nil {
@@ -87,22 +89,54 @@ func (repo MySQLRepository) DeleteById(ctx context.Context, id int64) error {
 		}
 	}
 
-	return nil
+	return repo.closeHistory(ctx, "warrant.id = ?", id)
+}
+
+func (repo MySQLRepository) DeleteByIds(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	idReplacements := make([]interface{}, 0)
+	for _, id := range ids {
+		idReplacements = append(idReplacements, id)
+	}
+
+	_, err := repo.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+				UPDATE warrant
+				SET deletedAt = CURRENT_TIMESTAMP(6)
+				WHERE
+					id IN (%s) AND
+					deletedAt IS NULL
+			`,
+			strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "),
+		),
+		idReplacements...,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to delete warrants from mysql")
+	}
+
+	return repo.closeHistory(ctx, fmt.Sprintf("warrant.id IN (%s)", strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")), idReplacements...)
 }
 
-func (repo MySQLRepository) DeleteAllByObject(ctx context.Context, objectType string, objectId string) error {
+func (repo MySQLRepository) DeleteAllByObject(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE warrant
 			SET
-				deletedAt = ?
+				deletedAt = CURRENT_TIMESTAMP(6),
+				deletionBatchId = ?
 			WHERE
 				objectType = ? AND
 				objectId = ? AND
 				deletedAt IS NULL
 		`,
-		time.Now().UTC(),
+		deletionBatchId,
 		objectType,
 		objectId,
 	)
@@ -115,22 +149,23 @@ func (repo MySQLRepository) DeleteAllByObject(ctx context.Context, objectType st
 		}
 	}
 
-	return nil
+	return repo.closeHistory(ctx, "warrant.deletionBatchId = ?", deletionBatchId)
 }
 
-func (repo MySQLRepository) DeleteAllBySubject(ctx context.Context, subjectType string, subjectId string) error {
+func (repo MySQLRepository) DeleteAllBySubject(ctx context.Context, subjectType string, subjectId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE warrant
 			SET
-				deletedAt = ?
+				deletedAt = CURRENT_TIMESTAMP(6),
+				deletionBatchId = ?
 			WHERE
 				subjectType = ? AND
 				subjectId = ? AND
 				deletedAt IS NULL
 		`,
-		time.Now().UTC(),
+		deletionBatchId,
 		subjectType,
 		subjectId,
 	)
@@ -143,7 +178,7 @@ func (repo MySQLRepository) DeleteAllBySubject(ctx context.Context, subjectType
 		}
 	}
 
-	return nil
+	return repo.closeHistory(ctx, "warrant.deletionBatchId = ?", deletionBatchId)
 }
 
 func (repo MySQLRepository) Get(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, contextHash string) (Model, error) {
@@ -184,31 +219,37 @@ func (repo MySQLRepository) Get(ctx context.Context, objectType string, objectId
 	return &warrant, nil
 }
 
-func (repo MySQLRepository) GetWithContextMatch(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, contextHash string) (Model, error) {
+func (repo MySQLRepository) GetWithContextMatch(ctx context.Context, objectType string, objectIds []string, relation string, subjectType string, subjectId string, subjectRelation string, contextHash string, asOf *time.Time) (Model, error) {
 	var warrant Warrant
+	replacements := []interface{}{objectType}
+	for _, objectId := range objectIds {
+		replacements = append(replacements, objectId)
+	}
+
+	replacements = append(replacements, relation, subjectType, subjectId, subjectRelation, contextHash)
+	validAtCondition, validAtReplacements := repo.validAt("warrant", asOf)
+	replacements = append(replacements, validAtReplacements...)
 	err := repo.DB.GetContext(
 		ctx,
 		&warrant,
-		`
-			SELECT id, objectType, objectId, relation, subjectType, subjectId, subjectRelation, createdAt, updatedAt, deletedAt
-			FROM warrant
-			WHERE
-				objectType = ? AND
-				(objectId = ? OR objectId = "*") AND
-				relation = ? AND
-				subjectType = ? AND
-				subjectId = ? AND
-				subjectRelation = ? AND
-				(contextHash = ? OR contextHash = "") AND
-				deletedAt IS NULL
-		`,
-		objectType,
-		objectId,
-		relation,
-		subjectType,
-		subjectId,
-		subjectRelation,
-		contextHash,
+		fmt.Sprintf(
+			`
+				SELECT id, objectType, objectId, relation, subjectType, subjectId, subjectRelation, contextHash, createdAt, updatedAt, deletedAt
+				FROM warrant
+				WHERE
+					objectType = ? AND
+					objectId IN (%s) AND
+					relation = ? AND
+					subjectType = ? AND
+					subjectId = ? AND
+					subjectRelation = ? AND
+					(contextHash = ? OR contextHash = "") AND
+					%s
+			`,
+			strings.TrimSuffix(strings.Repeat("?, ", len(objectIds)), ", "),
+			validAtCondition,
+		),
+		replacements...,
 	)
 	if err != nil {
 		switch err {
@@ -270,24 +311,87 @@ func (repo MySQLRepository) List(ctx context.Context, filterOptions *FilterOptio
 		replacements = append(replacements, filterOptions.ObjectId)
 	}
 
+	if len(filterOptions.ObjectIds) > 0 {
+		query = fmt.Sprintf("%s AND objectId IN (%s)", query, strings.TrimSuffix(strings.Repeat("?, ", len(filterOptions.ObjectIds)), ", "))
+		for _, objectId := range filterOptions.ObjectIds {
+			replacements = append(replacements, objectId)
+		}
+	}
+
 	if filterOptions.Relation != "" {
 		query = fmt.Sprintf("%s AND relation = ?", query)
 		replacements = append(replacements, filterOptions.Relation)
 	}
 
+	// NOTE: only the fields of the subject that are set are filtered on
 	if filterOptions.Subject != nil {
-		query = fmt.Sprintf("%s AND subjectType = ? AND subjectId = ? AND subjectRelation = ?", query)
-		replacements = append(replacements, filterOptions.Subject.ObjectType, filterOptions.Subject.ObjectId, filterOptions.Subject.Relation)
+		if filterOptions.Subject.ObjectType != "" {
+			query = fmt.Sprintf("%s AND subjectType = ?", query)
+			replacements = append(replacements, filterOptions.Subject.ObjectType)
+		}
+
+		if filterOptions.Subject.ObjectId != "" {
+			query = fmt.Sprintf("%s AND subjectId = ?", query)
+			replacements = append(replacements, filterOptions.Subject.ObjectId)
+		}
+
+		if filterOptions.Subject.Relation != "" {
+			query = fmt.Sprintf("%s AND subjectRelation = ?", query)
+			replacements = append(replacements, filterOptions.Subject.Relation)
+		}
 	}
 
-	if listParams.SortBy != "" {
-		query = fmt.Sprintf("%s ORDER BY %s %s", query, listParams.SortBy, listParams.SortOrder)
-	} else {
-		query = fmt.Sprintf("%s ORDER BY createdAt DESC, id DESC", query)
+	if len(filterOptions.SubjectIds) > 0 {
+		query = fmt.Sprintf("%s AND subjectId IN (%s)", query, strings.TrimSuffix(strings.Repeat("?, ", len(filterOptions.SubjectIds)), ", "))
+		for _, subjectId := range filterOptions.SubjectIds {
+			replacements = append(replacements, subjectId)
+		}
+	}
+
+	// NOTE: warrants match a context filter if their context includes each of its name/value pairs
+	if filterOptions.Context != nil {
+		for name, value := range *filterOptions.Context {
+			query = fmt.Sprintf("%s AND EXISTS (SELECT 1 FROM context WHERE context.warrantId = warrant.id AND context.name = ? AND context.value = ? AND context.deletedAt IS NULL)", query)
+			replacements = append(replacements, name, value)
+		}
+	}
+
+	sortBy := listParams.SortBy
+	if sortBy == "" {
+		sortBy = "createdAt"
 	}
 
-	query = fmt.Sprintf("%s LIMIT ?, ?", query)
-	replacements = append(replacements, offset, listParams.Limit)
+	// NOTE: warrants are paginated by id, so the sortBy value of the warrant
+	// a cursor points to is looked up rather than passed by the client
+	if listParams.AfterId != "" {
+		comparator := ">"
+		if listParams.SortOrder == middleware.SortOrderDesc {
+			comparator = "<"
+		}
+
+		query = fmt.Sprintf("%s AND (%s %s (SELECT %s FROM warrant WHERE id = ?) OR (%s = (SELECT %s FROM warrant WHERE id = ?) AND id %s ?))", query, sortBy, comparator, sortBy, sortBy, sortBy, comparator)
+		replacements = append(replacements, listParams.AfterId, listParams.AfterId, listParams.AfterId)
+	}
+
+	if listParams.BeforeId != "" {
+		comparator := "<"
+		if listParams.SortOrder == middleware.SortOrderDesc {
+			comparator = ">"
+		}
+
+		query = fmt.Sprintf("%s AND (%s %s (SELECT %s FROM warrant WHERE id = ?) OR (%s = (SELECT %s FROM warrant WHERE id = ?) AND id %s ?))", query, sortBy, comparator, sortBy, sortBy, sortBy, comparator)
+		replacements = append(replacements, listParams.BeforeId, listParams.BeforeId, listParams.BeforeId)
+	}
+
+	sortOrder := listParams.QuerySortOrder()
+	query = fmt.Sprintf("%s ORDER BY %s %s, id %s", query, sortBy, sortOrder, sortOrder)
+	if listParams.UseCursorPagination() {
+		query = fmt.Sprintf("%s LIMIT ?", query)
+		replacements = append(replacements, listParams.Limit)
+	} else {
+		query = fmt.Sprintf("%s LIMIT ?, ?", query)
+		replacements = append(replacements, offset, listParams.Limit)
+	}
 	err := repo.DB.SelectContext(
 		ctx,
 		&warrants,
@@ -303,51 +407,59 @@ func (repo MySQLRepository) List(ctx context.Context, filterOptions *FilterOptio
 		}
 	}
 
-	for _, w := range warrants {
-		models = append(models, &w)
+	for i := range warrants {
+		models = append(models, &warrants[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
-func (repo MySQLRepository) GetAllMatchingWildcard(ctx context.Context, objectType string, objectId string, relation string, contextHash string) ([]Model, error) {
+func (repo MySQLRepository) GetAllMatchingWildcard(ctx context.Context, objectType string, objectId string, relation string, contextHash string, asOf *time.Time) ([]Model, error) {
 	models := make([]Model, 0)
 	warrants := make([]Warrant, 0)
+	w1ValidAtCondition, w1ValidAtReplacements := repo.validAt("w1", asOf)
+	w2ValidAtCondition, w2ValidAtReplacements := repo.validAt("w2", asOf)
+	replacements := []interface{}{objectType, objectId, relation, contextHash}
+	replacements = append(replacements, w1ValidAtReplacements...)
+	replacements = append(replacements, w2ValidAtReplacements...)
 	err := repo.DB.SelectContext(
 		ctx,
 		&warrants,
-		`
-			SELECT
-				w2.id,
-				w2.objectType,
-				w2.objectId,
-				w2.relation,
-				w1.subjectType,
-				w1.subjectId,
-				w1.subjectRelation,
-				w2.contextHash,
-				w2.createdAt,
-				w2.updatedAt
-			FROM warrant AS w1
-			JOIN warrant AS w2 ON
-				w1.id != w2.id AND
-				w1.objectType = w2.objectType AND
-				w1.relation = w2.relation AND
-				w1.contextHash = w2.contextHash
-			WHERE
-				w1.objectType = ? AND
-				w1.objectId = "*" AND
-				w2.objectId = ? AND
-				w1.relation = ? AND
-				(w1.contextHash = ? OR w1.contextHash = "") AND
-				w1.deletedAt IS NULL AND
-				w2.deletedAt IS NULL
-			ORDER BY w2.createdAt DESC, w2.id DESC
-		`,
-		objectType,
-		objectId,
-		relation,
-		contextHash,
+		fmt.Sprintf(
+			`
+				SELECT
+					w2.id,
+					w2.objectType,
+					w2.objectId,
+					w2.relation,
+					w1.subjectType,
+					w1.subjectId,
+					w1.subjectRelation,
+					w2.contextHash,
+					w2.createdAt,
+					w2.updatedAt
+				FROM warrant AS w1
+				JOIN warrant AS w2 ON
+					w1.id != w2.id AND
+					w1.objectType = w2.objectType AND
+					w1.relation = w2.relation AND
+					w1.contextHash = w2.contextHash
+				WHERE
+					w1.objectType = ? AND
+					w1.objectId = "*" AND
+					w2.objectId = ? AND
+					w1.relation = ? AND
+					(w1.contextHash = ? OR w1.contextHash = "") AND
+					%s AND
+					%s
+				ORDER BY w2.createdAt DESC, w2.id DESC
+			`,
+			w1ValidAtCondition,
+			w2ValidAtCondition,
+		),
+		replacements...,
 	)
 	if err != nil {
 		switch err {
@@ -358,48 +470,56 @@ func (repo MySQLRepository) GetAllMatchingWildcard(ctx context.Context, objectTy
 		}
 	}
 
-	for _, w := range warrants {
-		models = append(models, &w)
+	for i := range warrants {
+		models = append(models, &warrants[i])
 	}
 
 	return models, nil
 }
 
-func (repo MySQLRepository) GetAllMatchingObjectAndRelation(ctx context.Context, objectType string, objectId string, relation string, subjectType string, contextHash string) ([]Model, error) {
+func (repo MySQLRepository) GetAllMatchingObjectAndRelation(ctx context.Context, objectType string, objectIds []string, relation string, subjectType string, contextHash string, asOf *time.Time) ([]Model, error) {
 	models := make([]Model, 0)
 	warrants := make([]Warrant, 0)
+	replacements := []interface{}{objectType}
+	for _, objectId := range objectIds {
+		replacements = append(replacements, objectId)
+	}
+
+	replacements = append(replacements, relation, subjectType, contextHash)
+	validAtCondition, validAtReplacements := repo.validAt("warrant", asOf)
+	replacements = append(replacements, validAtReplacements...)
 	err := repo.DB.SelectContext(
 		ctx,
 		&warrants,
-		`
-			SELECT id, objectType, objectId, relation, subjectType, subjectId, subjectRelation, contextHash, createdAt, updatedAt, deletedAt
-			FROM warrant
-			WHERE
-				objectType = ? AND
-				objectId = ? AND
-				relation = ? AND
-				subjectType = ? AND
-				(contextHash = ? OR contextHash = "") AND
-				deletedAt IS NULL
-			ORDER BY createdAt DESC, id DESC
-		`,
-		objectType,
-		objectId,
-		relation,
-		subjectType,
-		contextHash,
+		fmt.Sprintf(
+			`
+				SELECT id, objectType, objectId, relation, subjectType, subjectId, subjectRelation, contextHash, createdAt, updatedAt, deletedAt
+				FROM warrant
+				WHERE
+					objectType = ? AND
+					objectId IN (%s) AND
+					relation = ? AND
+					subjectType = ? AND
+					(contextHash = ? OR contextHash = "") AND
+					%s
+				ORDER BY createdAt DESC, id DESC
+			`,
+			strings.TrimSuffix(strings.Repeat("?, ", len(objectIds)), ", "),
+			validAtCondition,
+		),
+		replacements...,
 	)
 	if err != nil {
 		switch err {
 		case sql.ErrNoRows:
 			return models, nil
 		default:
-			return nil, errors.Wrap(err, fmt.Sprintf("Unable to get warrants with object type %s, object id %s, and relation %s from mysql", objectType, objectId, relation))
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to get warrants with object type %s, object ids %s, and relation %s from mysql", objectType, strings.Join(objectIds, ", "), relation))
 		}
 	}
 
-	for _, w := range warrants {
-		models = append(models, &w)
+	for i := range warrants {
+		models = append(models, &warrants[i])
 	}
 
 	return models, nil
@@ -474,9 +594,244 @@ func (repo MySQLRepository) GetAllMatchingSubjectAndRelation(ctx context.Context
 		}
 	}
 
-	for _, w := range warrants {
-		models = append(models, &w)
+	for i := range warrants {
+		models = append(models, &warrants[i])
+	}
+
+	return models, nil
+}
+
+// ListByDeletionBatchId returns the deleted warrants with the given deletion batch id
+func (repo MySQLRepository) ListByDeletionBatchId(ctx context.Context, deletionBatchId string) ([]Model, error) {
+	models := make([]Model, 0)
+	warrants := make([]Warrant, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&warrants,
+		`
+			SELECT id, objectType, objectId, relation, subjectType, subjectId, subjectRelation, createdAt, updatedAt, deletedAt
+			FROM warrant
+			WHERE
+				deletionBatchId = ? AND
+				deletedAt IS NOT NULL
+			ORDER BY id ASC
+		`,
+		deletionBatchId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to list warrants with deletion batch id %s from mysql", deletionBatchId))
+		}
+	}
+
+	for i := range warrants {
+		models = append(models, &warrants[i])
+	}
+
+	return models, nil
+}
+
+// RestoreByDeletionBatchId restores the deleted warrants with the given deletion
+// batch id, skipping any that have since been re-created
+func (repo MySQLRepository) RestoreByDeletionBatchId(ctx context.Context, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE warrant
+			LEFT JOIN warrant AS active ON
+				active.objectType = warrant.objectType AND
+				active.objectId = warrant.objectId AND
+				active.relation = warrant.relation AND
+				active.subjectType = warrant.subjectType AND
+				active.subjectId = warrant.subjectId AND
+				active.subjectRelation = warrant.subjectRelation AND
+				active.contextHash = warrant.contextHash AND
+				active.deletedAt IS NULL
+			SET warrant.deletedAt = NULL
+			WHERE
+				warrant.deletionBatchId = ? AND
+				warrant.deletedAt IS NOT NULL AND
+				active.id IS NULL
+		`,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to restore warrants with deletion batch id %s from mysql", deletionBatchId))
+	}
+
+	err = repo.openHistory(ctx, "deletionBatchId = ? AND deletedAt IS NULL", deletionBatchId)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE warrant
+			SET deletionBatchId = NULL
+			WHERE
+				deletionBatchId = ? AND
+				deletedAt IS NULL
+		`,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to restore warrants with deletion batch id %s from mysql", deletionBatchId))
+	}
+
+	return nil
+}
+
+// openHistory starts a validity interval for each warrant matching condition
+// that doesn't already have an open one. The interval starts at the warrant's
+// updatedAt, which is set by the same statement that created or restored it.
+func (repo MySQLRepository) openHistory(ctx context.Context, condition string, args ...interface{}) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+				INSERT INTO warrantHistory (warrantId, validFrom)
+				SELECT id, updatedAt
+				FROM warrant
+				WHERE
+					%s AND
+					NOT EXISTS (
+						SELECT 1
+						FROM warrantHistory
+						WHERE
+							warrantHistory.warrantId = warrant.id AND
+							warrantHistory.validTo IS NULL
+					)
+			`,
+			condition,
+		),
+		args...,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to record warrant history in mysql")
+	}
+
+	return nil
+}
+
+// closeHistory ends the open validity interval of each deleted warrant matching
+// condition at the warrant's deletedAt
+func (repo MySQLRepository) closeHistory(ctx context.Context, condition string, args ...interface{}) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+				UPDATE warrantHistory
+				JOIN warrant ON warrant.id = warrantHistory.warrantId
+				SET warrantHistory.validTo = warrant.deletedAt
+				WHERE
+					warrantHistory.validTo IS NULL AND
+					warrant.deletedAt IS NOT NULL AND
+					%s
+			`,
+			condition,
+		),
+		args...,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to record warrant history in mysql")
+	}
+
+	return nil
+}
+
+// validAt returns a condition matching the warrants aliased as alias that were
+// valid at asOf according to their history, or that are currently valid if asOf is nil
+func (repo MySQLRepository) validAt(alias string, asOf *time.Time) (string, []interface{}) {
+	if asOf == nil {
+		return fmt.Sprintf("%s.deletedAt IS NULL", alias), nil
+	}
+
+	return fmt.Sprintf(
+		`EXISTS (
+			SELECT 1
+			FROM warrantHistory
+			WHERE
+				warrantHistory.warrantId = %s.id AND
+				warrantHistory.validFrom <= ? AND
+				(warrantHistory.validTo IS NULL OR warrantHistory.validTo > ?)
+		)`,
+		alias,
+	), []interface{}{*asOf, *asOf}
+}
+
+// GetAllObjectsValidAt returns the distinct objects (other than wildcards) of
+// the warrants that were valid at any of the given times
+func (repo MySQLRepository) GetAllObjectsValidAt(ctx context.Context, objectType string, asOfs []time.Time, limit int) ([]Model, error) {
+	models := make([]Model, 0)
+	warrants := make([]Warrant, 0)
+	if len(asOfs) == 0 {
+		return models, nil
+	}
+
+	query := `
+		SELECT DISTINCT objectType, objectId
+		FROM warrant
+		WHERE
+			objectId != "*"
+	`
+	replacements := make([]interface{}, 0)
+	if objectType != "" {
+		query = fmt.Sprintf("%s AND objectType = ?", query)
+		replacements = append(replacements, objectType)
+	}
+
+	validAtConditions := make([]string, 0)
+	for i := range asOfs {
+		validAtCondition, validAtReplacements := repo.validAt("warrant", &asOfs[i])
+		validAtConditions = append(validAtConditions, validAtCondition)
+		replacements = append(replacements, validAtReplacements...)
+	}
+
+	query = fmt.Sprintf("%s AND (%s) ORDER BY objectType, objectId LIMIT ?", query, strings.Join(validAtConditions, " OR "))
+	replacements = append(replacements, limit)
+	err := repo.DB.SelectContext(
+		ctx,
+		&warrants,
+		query,
+		replacements...,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, "Unable to get objects of warrants from mysql")
+		}
+	}
+
+	for i := range warrants {
+		models = append(models, &warrants[i])
 	}
 
 	return models, nil
 }
+
+// GetHistoryHorizon returns the earliest time warrant and object type history
+// is recorded from. Point-in-time checks before it can't be answered.
+func (repo MySQLRepository) GetHistoryHorizon(ctx context.Context) (time.Time, error) {
+	var horizon time.Time
+	err := repo.DB.GetContext(
+		ctx,
+		&horizon,
+		`
+			SELECT horizon
+			FROM historyHorizon
+			WHERE
+				id = 1
+		`,
+	)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "Unable to get history horizon from mysql")
+	}
+
+	return horizon, nil
+}
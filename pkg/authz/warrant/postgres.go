@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
@@ -39,9 +40,6 @@ func (repo PostgresRepository) Create(ctx context.Context, model Model) (int64,
 				subject_relation,
 				context_hash
 			) VALUES (?, ?, ?, ?, ?, ?, ?)
-			ON CONFLICT (object_type, object_id, relation, subject_type, subject_id, subject_relation, context_hash) DO UPDATE SET
-				created_at = CURRENT_TIMESTAMP(6),
-				deleted_at = NULL
 			RETURNING id
 		`,
 		model.GetObjectType(),
@@ -54,13 +52,18 @@ func (repo PostgresRepository) Create(ctx context.Context, model Model) (int64,
 	)
 	if err != nil {
 		postgresErr, ok := err.(*pq.Error)
-		if ok && postgresErr.Code.Name() == "duplicate_object" {
+		if ok && postgresErr.Code.Name() == "unique_violation" {
 			return 0, service.NewDuplicateRecordError("Warrant", model, "Warrant for the given objectType, objectId, relation, and subject already exists")
 		}
 
 		return 0, errors.Wrap(err, "Unable to create warrant")
 	}
 
+	err = repo.openHistory(ctx, "id = ?", newWarrantId)
+	if err != nil {
+		return 0, err
+	}
+
 	return newWarrantId, nil
 }
 
@@ -69,12 +72,11 @@ func (repo PostgresRepository) DeleteById(ctx context.Context, id int64) error {
 		ctx,
 		`
 			UPDATE warrant
-			SET deleted_at = ?
+			SET deleted_at = CURRENT_TIMESTAMP(6)
 			WHERE
 				id = ? AND
 				deleted_at IS NULL
 		`,
-		time.Now().UTC(),
 		id,
 	)
 	if err != nil {
@@ -86,22 +88,54 @@ func (repo PostgresRepository) DeleteById(ctx context.Context, id int64) error {
 		}
 	}
 
-	return nil
+	return repo.closeHistory(ctx, "warrant.id = ?", id)
+}
+
+func (repo PostgresRepository) DeleteByIds(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	idReplacements := make([]interface{}, 0)
+	for _, id := range ids {
+		idReplacements = append(idReplacements, id)
+	}
+
+	_, err := repo.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+				UPDATE warrant
+				SET deleted_at = CURRENT_TIMESTAMP(6)
+				WHERE
+					id IN (%s) AND
+					deleted_at IS NULL
+			`,
+			strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "),
+		),
+		idReplacements...,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to delete warrants from postgres")
+	}
+
+	return repo.closeHistory(ctx, fmt.Sprintf("warrant.id IN (%s)", strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")), idReplacements...)
 }
 
-func (repo PostgresRepository) DeleteAllByObject(ctx context.Context, objectType string, objectId string) error {
+func (repo PostgresRepository) DeleteAllByObject(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE warrant
 			SET
-				deleted_at = ?
+				deleted_at = CURRENT_TIMESTAMP(6),
+				deletion_batch_id = ?
 			WHERE
 				object_type = ? AND
 				object_id = ? AND
 				deleted_at IS NULL
 		`,
-		time.Now().UTC(),
+		deletionBatchId,
 		objectType,
 		objectId,
 	)
@@ -114,22 +148,23 @@ func (repo PostgresRepository) DeleteAllByObject(ctx context.Context, objectType
 		}
 	}
 
-	return nil
+	return repo.closeHistory(ctx, "warrant.deletion_batch_id = ?", deletionBatchId)
 }
 
-func (repo PostgresRepository) DeleteAllBySubject(ctx context.Context, subjectType string, subjectId string) error {
+func (repo PostgresRepository) DeleteAllBySubject(ctx context.Context, subjectType string, subjectId string, deletionBatchId string) error {
 	_, err := repo.DB.ExecContext(
 		ctx,
 		`
 			UPDATE warrant
 			SET
-				deleted_at = ?
+				deleted_at = CURRENT_TIMESTAMP(6),
+				deletion_batch_id = ?
 			WHERE
 				subject_type = ? AND
 				subject_id = ? AND
 				deleted_at IS NULL
 		`,
-		time.Now().UTC(),
+		deletionBatchId,
 		subjectType,
 		subjectId,
 	)
@@ -142,7 +177,7 @@ func (repo PostgresRepository) DeleteAllBySubject(ctx context.Context, subjectTy
 		}
 	}
 
-	return nil
+	return repo.closeHistory(ctx, "warrant.deletion_batch_id = ?", deletionBatchId)
 }
 
 func (repo PostgresRepository) Get(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, contextHash string) (Model, error) {
@@ -183,31 +218,37 @@ func (repo PostgresRepository) Get(ctx context.Context, objectType string, objec
 	return &warrant, nil
 }
 
-func (repo PostgresRepository) GetWithContextMatch(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, contextHash string) (Model, error) {
+func (repo PostgresRepository) GetWithContextMatch(ctx context.Context, objectType string, objectIds []string, relation string, subjectType string, subjectId string, subjectRelation string, contextHash string, asOf *time.Time) (Model, error) {
 	var warrant Warrant
+	replacements := []interface{}{objectType}
+	for _, objectId := range objectIds {
+		replacements = append(replacements, objectId)
+	}
+
+	replacements = append(replacements, relation, subjectType, subjectId, subjectRelation, contextHash)
+	validAtCondition, validAtReplacements := repo.validAt("warrant", asOf)
+	replacements = append(replacements, validAtReplacements...)
 	err := repo.DB.GetContext(
 		ctx,
 		&warrant,
-		`
-			SELECT id, object_type, object_id, relation, subject_type, subject_id, subject_relation, created_at, updated_at, deleted_at
-			FROM warrant
-			WHERE
-				object_type = ? AND
-				(object_id = ? OR object_id = '*') AND
-				relation = ? AND
-				subject_type = ? AND
-				subject_id = ? AND
-				subject_relation = ? AND
-				(context_hash = ? OR context_hash = '') AND
-				deleted_at IS NULL
-		`,
-		objectType,
-		objectId,
-		relation,
-		subjectType,
-		subjectId,
-		subjectRelation,
-		contextHash,
+		fmt.Sprintf(
+			`
+				SELECT id, object_type, object_id, relation, subject_type, subject_id, subject_relation, context_hash, created_at, updated_at, deleted_at
+				FROM warrant
+				WHERE
+					object_type = ? AND
+					object_id IN (%s) AND
+					relation = ? AND
+					subject_type = ? AND
+					subject_id = ? AND
+					subject_relation = ? AND
+					(context_hash = ? OR context_hash = '') AND
+					%s
+			`,
+			strings.TrimSuffix(strings.Repeat("?, ", len(objectIds)), ", "),
+			validAtCondition,
+		),
+		replacements...,
 	)
 	if err != nil {
 		switch err {
@@ -269,25 +310,87 @@ func (repo PostgresRepository) List(ctx context.Context, filterOptions *FilterOp
 		replacements = append(replacements, filterOptions.ObjectId)
 	}
 
+	if len(filterOptions.ObjectIds) > 0 {
+		query = fmt.Sprintf(`%s AND object_id IN (%s)`, query, strings.TrimSuffix(strings.Repeat("?, ", len(filterOptions.ObjectIds)), ", "))
+		for _, objectId := range filterOptions.ObjectIds {
+			replacements = append(replacements, objectId)
+		}
+	}
+
 	if filterOptions.Relation != "" {
 		query = fmt.Sprintf(`%s AND relation = ?`, query)
 		replacements = append(replacements, filterOptions.Relation)
 	}
 
+	// NOTE: only the fields of the subject that are set are filtered on
 	if filterOptions.Subject != nil {
-		query = fmt.Sprintf(`%s AND subject_type = ? AND subject_id = ? AND subject_relation = ?`, query)
-		replacements = append(replacements, filterOptions.Subject.ObjectType, filterOptions.Subject.ObjectId, filterOptions.Subject.Relation)
+		if filterOptions.Subject.ObjectType != "" {
+			query = fmt.Sprintf(`%s AND subject_type = ?`, query)
+			replacements = append(replacements, filterOptions.Subject.ObjectType)
+		}
+
+		if filterOptions.Subject.ObjectId != "" {
+			query = fmt.Sprintf(`%s AND subject_id = ?`, query)
+			replacements = append(replacements, filterOptions.Subject.ObjectId)
+		}
+
+		if filterOptions.Subject.Relation != "" {
+			query = fmt.Sprintf(`%s AND subject_relation = ?`, query)
+			replacements = append(replacements, filterOptions.Subject.Relation)
+		}
 	}
 
-	if listParams.SortBy != "" {
-		sortBy := regexp.MustCompile("([A-Z])").ReplaceAllString(listParams.SortBy, `_$1`)
-		query = fmt.Sprintf(`%s ORDER BY %s %s`, query, sortBy, listParams.SortOrder)
-	} else {
-		query = fmt.Sprintf(`%s ORDER BY created_at DESC, id DESC`, query)
+	if len(filterOptions.SubjectIds) > 0 {
+		query = fmt.Sprintf(`%s AND subject_id IN (%s)`, query, strings.TrimSuffix(strings.Repeat("?, ", len(filterOptions.SubjectIds)), ", "))
+		for _, subjectId := range filterOptions.SubjectIds {
+			replacements = append(replacements, subjectId)
+		}
 	}
 
-	query = fmt.Sprintf(`%s LIMIT ? OFFSET ?`, query)
-	replacements = append(replacements, listParams.Limit, offset)
+	// NOTE: warrants match a context filter if their context includes each of its name/value pairs
+	if filterOptions.Context != nil {
+		for name, value := range *filterOptions.Context {
+			query = fmt.Sprintf(`%s AND EXISTS (SELECT 1 FROM context WHERE context.warrant_id = warrant.id AND context.name = ? AND context.value = ? AND context.deleted_at IS NULL)`, query)
+			replacements = append(replacements, name, value)
+		}
+	}
+
+	sortBy := regexp.MustCompile("([A-Z])").ReplaceAllString(listParams.SortBy, `_$1`)
+	if sortBy == "" {
+		sortBy = "created_at"
+	}
+
+	// NOTE: warrants are paginated by id, so the sortBy value of the warrant
+	// a cursor points to is looked up rather than passed by the client
+	if listParams.AfterId != "" {
+		comparator := ">"
+		if listParams.SortOrder == middleware.SortOrderDesc {
+			comparator = "<"
+		}
+
+		query = fmt.Sprintf(`%s AND (%s %s (SELECT %s FROM warrant WHERE id = ?) OR (%s = (SELECT %s FROM warrant WHERE id = ?) AND id %s ?))`, query, sortBy, comparator, sortBy, sortBy, sortBy, comparator)
+		replacements = append(replacements, listParams.AfterId, listParams.AfterId, listParams.AfterId)
+	}
+
+	if listParams.BeforeId != "" {
+		comparator := "<"
+		if listParams.SortOrder == middleware.SortOrderDesc {
+			comparator = ">"
+		}
+
+		query = fmt.Sprintf(`%s AND (%s %s (SELECT %s FROM warrant WHERE id = ?) OR (%s = (SELECT %s FROM warrant WHERE id = ?) AND id %s ?))`, query, sortBy, comparator, sortBy, sortBy, sortBy, comparator)
+		replacements = append(replacements, listParams.BeforeId, listParams.BeforeId, listParams.BeforeId)
+	}
+
+	sortOrder := listParams.QuerySortOrder()
+	query = fmt.Sprintf(`%s ORDER BY %s %s, id %s`, query, sortBy, sortOrder, sortOrder)
+	if listParams.UseCursorPagination() {
+		query = fmt.Sprintf(`%s LIMIT ?`, query)
+		replacements = append(replacements, listParams.Limit)
+	} else {
+		query = fmt.Sprintf(`%s LIMIT ? OFFSET ?`, query)
+		replacements = append(replacements, listParams.Limit, offset)
+	}
 	err := repo.DB.SelectContext(
 		ctx,
 		&warrants,
@@ -303,51 +406,59 @@ func (repo PostgresRepository) List(ctx context.Context, filterOptions *FilterOp
 		}
 	}
 
-	for _, w := range warrants {
-		models = append(models, &w)
+	for i := range warrants {
+		models = append(models, &warrants[i])
 	}
 
+	middleware.ReversePage(listParams, models)
+
 	return models, nil
 }
 
-func (repo PostgresRepository) GetAllMatchingWildcard(ctx context.Context, objectType string, objectId string, relation string, contextHash string) ([]Model, error) {
+func (repo PostgresRepository) GetAllMatchingWildcard(ctx context.Context, objectType string, objectId string, relation string, contextHash string, asOf *time.Time) ([]Model, error) {
 	models := make([]Model, 0)
 	warrants := make([]Warrant, 0)
+	w1ValidAtCondition, w1ValidAtReplacements := repo.validAt("w1", asOf)
+	w2ValidAtCondition, w2ValidAtReplacements := repo.validAt("w2", asOf)
+	replacements := []interface{}{objectType, objectId, relation, contextHash}
+	replacements = append(replacements, w1ValidAtReplacements...)
+	replacements = append(replacements, w2ValidAtReplacements...)
 	err := repo.DB.SelectContext(
 		ctx,
 		&warrants,
-		`
-			SELECT
-				w2.id,
-				w2.object_type,
-				w2.object_id,
-				w2.relation,
-				w1.subject_type,
-				w1.subject_id,
-				w1.subject_relation,
-				w2.context_hash,
-				w2.created_at,
-				w2.updated_at
-			FROM warrant AS w1
-			JOIN warrant AS w2 ON
-				w1.id != w2.id AND
-				w1.object_type = w2.object_type AND
-				w1.relation = w2.relation AND
-				w1.context_hash = w2.context_hash
-			WHERE
-				w1.object_type = ? AND
-				w1.object_id = '*' AND
-				w2.object_id = ? AND
-				w1.relation = ? AND
-				(w1.context_hash = ? OR w1.context_hash = '') AND
-				w1.deleted_at IS NULL AND
-				w2.deleted_at IS NULL
-			ORDER BY w2.created_at DESC, w2.id DESC
-		`,
-		objectType,
-		objectId,
-		relation,
-		contextHash,
+		fmt.Sprintf(
+			`
+				SELECT
+					w2.id,
+					w2.object_type,
+					w2.object_id,
+					w2.relation,
+					w1.subject_type,
+					w1.subject_id,
+					w1.subject_relation,
+					w2.context_hash,
+					w2.created_at,
+					w2.updated_at
+				FROM warrant AS w1
+				JOIN warrant AS w2 ON
+					w1.id != w2.id AND
+					w1.object_type = w2.object_type AND
+					w1.relation = w2.relation AND
+					w1.context_hash = w2.context_hash
+				WHERE
+					w1.object_type = ? AND
+					w1.object_id = '*' AND
+					w2.object_id = ? AND
+					w1.relation = ? AND
+					(w1.context_hash = ? OR w1.context_hash = '') AND
+					%s AND
+					%s
+				ORDER BY w2.created_at DESC, w2.id DESC
+			`,
+			w1ValidAtCondition,
+			w2ValidAtCondition,
+		),
+		replacements...,
 	)
 	if err != nil {
 		switch err {
@@ -358,48 +469,56 @@ func (repo PostgresRepository) GetAllMatchingWildcard(ctx context.Context, objec
 		}
 	}
 
-	for _, w := range warrants {
-		models = append(models, &w)
+	for i := range warrants {
+		models = append(models, &warrants[i])
 	}
 
 	return models, nil
 }
 
-func (repo PostgresRepository) GetAllMatchingObjectAndRelation(ctx context.Context, objectType string, objectId string, relation string, subjectType string, contextHash string) ([]Model, error) {
+func (repo PostgresRepository) GetAllMatchingObjectAndRelation(ctx context.Context, objectType string, objectIds []string, relation string, subjectType string, contextHash string, asOf *time.Time) ([]Model, error) {
 	models := make([]Model, 0)
 	warrants := make([]Warrant, 0)
+	replacements := []interface{}{objectType}
+	for _, objectId := range objectIds {
+		replacements = append(replacements, objectId)
+	}
+
+	replacements = append(replacements, relation, subjectType, contextHash)
+	validAtCondition, validAtReplacements := repo.validAt("warrant", asOf)
+	replacements = append(replacements, validAtReplacements...)
 	err := repo.DB.SelectContext(
 		ctx,
 		&warrants,
-		`
-			SELECT id, object_type, object_id, relation, subject_type, subject_id, subject_relation, context_hash, created_at, updated_at, deleted_at
-			FROM warrant
-			WHERE
-				object_type = ? AND
-				object_id = ? AND
-				relation = ? AND
-				subject_type = ? AND
-				(context_hash = ? OR context_hash = '') AND
-				deleted_at IS NULL
-			ORDER BY created_at DESC, id DESC
-		`,
-		objectType,
-		objectId,
-		relation,
-		subjectType,
-		contextHash,
+		fmt.Sprintf(
+			`
+				SELECT id, object_type, object_id, relation, subject_type, subject_id, subject_relation, context_hash, created_at, updated_at, deleted_at
+				FROM warrant
+				WHERE
+					object_type = ? AND
+					object_id IN (%s) AND
+					relation = ? AND
+					subject_type = ? AND
+					(context_hash = ? OR context_hash = '') AND
+					%s
+				ORDER BY created_at DESC, id DESC
+			`,
+			strings.TrimSuffix(strings.Repeat("?, ", len(objectIds)), ", "),
+			validAtCondition,
+		),
+		replacements...,
 	)
 	if err != nil {
 		switch err {
 		case sql.ErrNoRows:
 			return models, nil
 		default:
-			return nil, errors.Wrap(err, fmt.Sprintf("Unable to get warrants with object type %s, object id %s, and relation %s from mysql", objectType, objectId, relation))
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to get warrants with object type %s, object ids %s, and relation %s from postgres", objectType, strings.Join(objectIds, ", "), relation))
 		}
 	}
 
-	for _, w := range warrants {
-		models = append(models, &w)
+	for i := range warrants {
+		models = append(models, &warrants[i])
 	}
 
 	return models, nil
@@ -474,9 +593,248 @@ func (repo PostgresRepository) GetAllMatchingSubjectAndRelation(ctx context.Cont
 		}
 	}
 
-	for _, w := range warrants {
-		models = append(models, &w)
+	for i := range warrants {
+		models = append(models, &warrants[i])
+	}
+
+	return models, nil
+}
+
+// ListByDeletionBatchId returns the deleted warrants with the given deletion batch id
+func (repo PostgresRepository) ListByDeletionBatchId(ctx context.Context, deletionBatchId string) ([]Model, error) {
+	models := make([]Model, 0)
+	warrants := make([]Warrant, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&warrants,
+		`
+			SELECT id, object_type, object_id, relation, subject_type, subject_id, subject_relation, created_at, updated_at, deleted_at
+			FROM warrant
+			WHERE
+				deletion_batch_id = ? AND
+				deleted_at IS NOT NULL
+			ORDER BY id ASC
+		`,
+		deletionBatchId,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, fmt.Sprintf("Unable to list warrants with deletion batch id %s from postgres", deletionBatchId))
+		}
+	}
+
+	for i := range warrants {
+		models = append(models, &warrants[i])
 	}
 
 	return models, nil
 }
+
+// RestoreByDeletionBatchId restores the deleted warrants with the given deletion
+// batch id, skipping any that have since been re-created
+func (repo PostgresRepository) RestoreByDeletionBatchId(ctx context.Context, deletionBatchId string) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE warrant
+			SET deleted_at = NULL
+			WHERE
+				deletion_batch_id = ? AND
+				deleted_at IS NOT NULL AND
+				NOT EXISTS (
+					SELECT 1
+					FROM warrant AS active
+					WHERE
+						active.object_type = warrant.object_type AND
+						active.object_id = warrant.object_id AND
+						active.relation = warrant.relation AND
+						active.subject_type = warrant.subject_type AND
+						active.subject_id = warrant.subject_id AND
+						active.subject_relation = warrant.subject_relation AND
+						active.context_hash = warrant.context_hash AND
+						active.deleted_at IS NULL
+				)
+		`,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to restore warrants with deletion batch id %s from postgres", deletionBatchId))
+	}
+
+	err = repo.openHistory(ctx, "deletion_batch_id = ? AND deleted_at IS NULL", deletionBatchId)
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE warrant
+			SET deletion_batch_id = NULL
+			WHERE
+				deletion_batch_id = ? AND
+				deleted_at IS NULL
+		`,
+		deletionBatchId,
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to restore warrants with deletion batch id %s from postgres", deletionBatchId))
+	}
+
+	return nil
+}
+
+// openHistory starts a validity interval for each warrant matching condition
+// that doesn't already have an open one. The interval starts at the warrant's
+// updated_at, which is set by the same statement that created or restored it.
+func (repo PostgresRepository) openHistory(ctx context.Context, condition string, args ...interface{}) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+				INSERT INTO warrant_history (warrant_id, valid_from)
+				SELECT id, updated_at
+				FROM warrant
+				WHERE
+					%s AND
+					NOT EXISTS (
+						SELECT 1
+						FROM warrant_history
+						WHERE
+							warrant_history.warrant_id = warrant.id AND
+							warrant_history.valid_to IS NULL
+					)
+			`,
+			condition,
+		),
+		args...,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to record warrant history in postgres")
+	}
+
+	return nil
+}
+
+// closeHistory ends the open validity interval of each deleted warrant matching
+// condition at the warrant's deleted_at
+func (repo PostgresRepository) closeHistory(ctx context.Context, condition string, args ...interface{}) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+				UPDATE warrant_history
+				SET valid_to = warrant.deleted_at
+				FROM warrant
+				WHERE
+					warrant.id = warrant_history.warrant_id AND
+					warrant_history.valid_to IS NULL AND
+					warrant.deleted_at IS NOT NULL AND
+					%s
+			`,
+			condition,
+		),
+		args...,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to record warrant history in postgres")
+	}
+
+	return nil
+}
+
+// validAt returns a condition matching the warrants aliased as alias that were
+// valid at asOf according to their history, or that are currently valid if asOf is nil
+func (repo PostgresRepository) validAt(alias string, asOf *time.Time) (string, []interface{}) {
+	if asOf == nil {
+		return fmt.Sprintf("%s.deleted_at IS NULL", alias), nil
+	}
+
+	return fmt.Sprintf(
+		`EXISTS (
+			SELECT 1
+			FROM warrant_history
+			WHERE
+				warrant_history.warrant_id = %s.id AND
+				warrant_history.valid_from <= ? AND
+				(warrant_history.valid_to IS NULL OR warrant_history.valid_to > ?)
+		)`,
+		alias,
+	), []interface{}{*asOf, *asOf}
+}
+
+// GetAllObjectsValidAt returns the distinct objects (other than wildcards) of
+// the warrants that were valid at any of the given times
+func (repo PostgresRepository) GetAllObjectsValidAt(ctx context.Context, objectType string, asOfs []time.Time, limit int) ([]Model, error) {
+	models := make([]Model, 0)
+	warrants := make([]Warrant, 0)
+	if len(asOfs) == 0 {
+		return models, nil
+	}
+
+	query := `
+		SELECT DISTINCT object_type, object_id
+		FROM warrant
+		WHERE
+			object_id != '*'
+	`
+	replacements := make([]interface{}, 0)
+	if objectType != "" {
+		query = fmt.Sprintf("%s AND object_type = ?", query)
+		replacements = append(replacements, objectType)
+	}
+
+	validAtConditions := make([]string, 0)
+	for i := range asOfs {
+		validAtCondition, validAtReplacements := repo.validAt("warrant", &asOfs[i])
+		validAtConditions = append(validAtConditions, validAtCondition)
+		replacements = append(replacements, validAtReplacements...)
+	}
+
+	query = fmt.Sprintf("%s AND (%s) ORDER BY object_type, object_id LIMIT ?", query, strings.Join(validAtConditions, " OR "))
+	replacements = append(replacements, limit)
+	err := repo.DB.SelectContext(
+		ctx,
+		&warrants,
+		query,
+		replacements...,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, "Unable to get objects of warrants from postgres")
+		}
+	}
+
+	for i := range warrants {
+		models = append(models, &warrants[i])
+	}
+
+	return models, nil
+}
+
+// GetHistoryHorizon returns the earliest time warrant and object type history
+// is recorded from. Point-in-time checks before it can't be answered.
+func (repo PostgresRepository) GetHistoryHorizon(ctx context.Context) (time.Time, error) {
+	var horizon time.Time
+	err := repo.DB.GetContext(
+		ctx,
+		&horizon,
+		`
+			SELECT horizon
+			FROM history_horizon
+			WHERE
+				id = 1
+		`,
+	)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "Unable to get history horizon from postgres")
+	}
+
+	return horizon, nil
+}
@@ -3,6 +3,7 @@ package authz
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/warrant-dev/warrant/pkg/database"
 	"github.com/warrant-dev/warrant/pkg/middleware"
@@ -12,15 +13,20 @@ type WarrantRepository interface {
 	Create(ctx context.Context, warrant Model) (int64, error)
 	Get(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, contextHash string) (Model, error)
 	GetByID(ctx context.Context, id int64) (Model, error)
-	GetWithContextMatch(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, contextHash string) (Model, error)
-	GetAllMatchingWildcard(ctx context.Context, objectType string, objectId string, relation string, contextHash string) ([]Model, error)
-	GetAllMatchingObjectAndRelation(ctx context.Context, objectType string, objectId string, relation string, subjectType string, contextHash string) ([]Model, error)
+	GetWithContextMatch(ctx context.Context, objectType string, objectIds []string, relation string, subjectType string, subjectId string, subjectRelation string, contextHash string, asOf *time.Time) (Model, error)
+	GetAllMatchingWildcard(ctx context.Context, objectType string, objectId string, relation string, contextHash string, asOf *time.Time) ([]Model, error)
+	GetAllMatchingObjectAndRelation(ctx context.Context, objectType string, objectIds []string, relation string, subjectType string, contextHash string, asOf *time.Time) ([]Model, error)
 	GetAllMatchingObjectAndSubject(ctx context.Context, objectType string, objectId string, subjectType string, subjectId string, subjectRelation string) ([]Model, error)
 	GetAllMatchingSubjectAndRelation(ctx context.Context, objectType string, relation string, subjectType string, subjectId string, subjectRelation string) ([]Model, error)
+	GetAllObjectsValidAt(ctx context.Context, objectType string, asOfs []time.Time, limit int) ([]Model, error)
+	GetHistoryHorizon(ctx context.Context) (time.Time, error)
 	List(ctx context.Context, filterOptions *FilterOptions, listParams middleware.ListParams) ([]Model, error)
 	DeleteById(ctx context.Context, id int64) error
-	DeleteAllByObject(ctx context.Context, objectType string, objectId string) error
-	DeleteAllBySubject(ctx context.Context, subjectType string, subjectId string) error
+	DeleteByIds(ctx context.Context, ids []int64) error
+	DeleteAllByObject(ctx context.Context, objectType string, objectId string, deletionBatchId string) error
+	DeleteAllBySubject(ctx context.Context, subjectType string, subjectId string, deletionBatchId string) error
+	ListByDeletionBatchId(ctx context.Context, deletionBatchId string) ([]Model, error)
+	RestoreByDeletionBatchId(ctx context.Context, deletionBatchId string) error
 }
 
 func NewRepository(db database.Database) (WarrantRepository, error) {
@@ -2,29 +2,43 @@ package authz
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
 
 	objecttype "github.com/warrant-dev/warrant/pkg/authz/objecttype"
+	"github.com/warrant-dev/warrant/pkg/change"
 	wntContext "github.com/warrant-dev/warrant/pkg/context"
 	"github.com/warrant-dev/warrant/pkg/event"
 	"github.com/warrant-dev/warrant/pkg/middleware"
 	"github.com/warrant-dev/warrant/pkg/service"
 )
 
+const (
+	DeleteWarrantsBatchSize    = 500
+	DeleteWarrantsPreviewLimit = 100
+	MaxWarrantsTextLines       = 10000
+	ExportWarrantsBatchSize    = 500
+	ListAllBatchSize           = 500
+)
+
 type WarrantService struct {
 	service.BaseService
 	repo          WarrantRepository
 	eventSvc      event.EventService
 	objectTypeSvc objecttype.ObjectTypeService
 	ctxSvc        wntContext.ContextService
+	changeSvc     change.ChangeService
 }
 
-func NewService(env service.Env, repo WarrantRepository, eventSvc event.EventService, objectTypeSvc objecttype.ObjectTypeService, ctxSvc wntContext.ContextService) WarrantService {
+func NewService(env service.Env, repo WarrantRepository, eventSvc event.EventService, objectTypeSvc objecttype.ObjectTypeService, ctxSvc wntContext.ContextService, changeSvc change.ChangeService) WarrantService {
 	return WarrantService{
 		BaseService:   service.NewBaseService(env),
 		repo:          repo,
 		eventSvc:      eventSvc,
 		objectTypeSvc: objectTypeSvc,
 		ctxSvc:        ctxSvc,
+		changeSvc:     changeSvc,
 	}
 }
 
@@ -41,8 +55,19 @@ func (svc WarrantService) Create(ctx context.Context, warrantSpec WarrantSpec) (
 		return nil, service.NewInvalidParameterError("relation", "An object type with the given relation does not exist.")
 	}
 
+	// Check that objectId and subject objectId are valid for their object types
+	err = objectTypeDef.ValidateObjectId(warrantSpec.ObjectId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.objectTypeSvc.ValidateObjectId(ctx, warrantSpec.Subject.ObjectType, warrantSpec.Subject.ObjectId)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check that warrant does not already exist
-	_, err = svc.repo.Get(ctx, warrantSpec.ObjectType, warrantSpec.ObjectId, warrantSpec.Relation, warrantSpec.Subject.ObjectType, warrantSpec.Subject.ObjectId, warrantSpec.Subject.Relation, warrantSpec.Context.String())
+	_, err = svc.repo.Get(ctx, warrantSpec.ObjectType, warrantSpec.ObjectId, warrantSpec.Relation, warrantSpec.Subject.ObjectType, warrantSpec.Subject.ObjectId, warrantSpec.Subject.Relation, warrantSpec.Context.ToHash())
 	if err == nil {
 		return nil, service.NewDuplicateRecordError("Warrant", warrantSpec, "A warrant with the given objectType, objectId, relation, subject, and context already exists")
 	}
@@ -74,6 +99,11 @@ func (svc WarrantService) Create(ctx context.Context, warrantSpec WarrantSpec) (
 			}
 		}
 
+		err = svc.changeSvc.TrackChange(txCtx, change.ChangeTypeWarrantCreated, change.ResourceTypeWarrant, createdWarrantSpec.String(), createdWarrantSpec)
+		if err != nil {
+			return err
+		}
+
 		svc.eventSvc.TrackAccessGrantedEvent(txCtx, createdWarrantSpec.ObjectType, createdWarrantSpec.ObjectId, createdWarrantSpec.Relation, createdWarrantSpec.Subject.ObjectType, createdWarrantSpec.Subject.ObjectId, createdWarrantSpec.Subject.Relation, warrantSpec.Context)
 		return nil
 	})
@@ -107,11 +137,9 @@ func (svc WarrantService) List(ctx context.Context, filterOptions *FilterOptions
 		return nil, err
 	}
 
-	warrantMap := make(map[int64]WarrantSpec)
 	warrantIds := make([]int64, 0)
-	for i, warrant := range warrants {
+	for _, warrant := range warrants {
 		warrantIds = append(warrantIds, warrant.GetID())
-		warrantMap[warrant.GetID()] = *warrants[i].ToWarrantSpec()
 	}
 
 	contextSetSpecs, err := svc.ctxSvc.ListByWarrantId(ctx, warrantIds)
@@ -119,18 +147,42 @@ func (svc WarrantService) List(ctx context.Context, filterOptions *FilterOptions
 		return nil, err
 	}
 
-	for warrantId := range warrantMap {
-		warrantSpec := warrantMap[warrantId]
-		warrantSpec.Context = contextSetSpecs[warrantId]
-	}
-
 	for _, warrant := range warrants {
-		warrantSpecs = append(warrantSpecs, warrant.ToWarrantSpec())
+		warrantSpec := warrant.ToWarrantSpec()
+		warrantSpec.Context = contextSetSpecs[warrant.GetID()]
+		warrantSpecs = append(warrantSpecs, warrantSpec)
 	}
 
 	return warrantSpecs, nil
 }
 
+// ListAll returns all warrants matching filterOptions, listing them in batches
+// of ListAllBatchSize
+func (svc WarrantService) ListAll(ctx context.Context, filterOptions *FilterOptions) ([]*WarrantSpec, error) {
+	warrantSpecs := make([]*WarrantSpec, 0)
+	listParams := middleware.ListParams{
+		Page:      1,
+		Limit:     ListAllBatchSize,
+		SortBy:    WarrantListParamParser{}.GetDefaultSortBy(),
+		SortOrder: middleware.SortOrderAsc,
+	}
+	for {
+		batch, err := svc.List(ctx, filterOptions, listParams)
+		if err != nil {
+			return nil, err
+		}
+
+		warrantSpecs = append(warrantSpecs, batch...)
+		if len(batch) < listParams.Limit {
+			return warrantSpecs, nil
+		}
+
+		cursor := batch[len(batch)-1].ToCursor(listParams.SortBy)
+		listParams.AfterId = cursor.ID
+		listParams.AfterValue = cursor.Value
+	}
+}
+
 func (svc WarrantService) Delete(ctx context.Context, warrantSpec WarrantSpec) error {
 	err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
 		warrant, err := svc.repo.Get(txCtx, warrantSpec.ObjectType, warrantSpec.ObjectId, warrantSpec.Relation, warrantSpec.Subject.ObjectType, warrantSpec.Subject.ObjectId, warrantSpec.Subject.Relation, warrantSpec.Context.ToHash())
@@ -153,6 +205,11 @@ func (svc WarrantService) Delete(ctx context.Context, warrantSpec WarrantSpec) e
 			return err
 		}
 
+		err = svc.changeSvc.TrackChange(txCtx, change.ChangeTypeWarrantDeleted, change.ResourceTypeWarrant, warrantSpec.String(), warrantSpec)
+		if err != nil {
+			return err
+		}
+
 		svc.eventSvc.TrackAccessRevokedEvent(txCtx, warrantSpec.ObjectType, warrantSpec.ObjectId, warrantSpec.Relation, warrantSpec.Subject.ObjectType, warrantSpec.Subject.ObjectId, warrantSpec.Subject.Relation, warrantSpec.Context)
 		return nil
 	})
@@ -163,19 +220,126 @@ func (svc WarrantService) Delete(ctx context.Context, warrantSpec WarrantSpec) e
 	return nil
 }
 
-func (svc WarrantService) DeleteRelatedWarrants(ctx context.Context, objectType string, objectId string) error {
+// DeleteAll deletes all warrants matching the given filter in a single transaction.
+// If dryRun is true, the matching warrants are counted (and a preview of them is
+// returned) but not deleted.
+func (svc WarrantService) DeleteAll(ctx context.Context, filterOptions *FilterOptions, dryRun bool) (*DeleteWarrantsSpec, error) {
+	result := DeleteWarrantsSpec{
+		DryRun: dryRun,
+	}
+	revokedEvents := make([]event.CreateAccessEventSpec, 0)
 	err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
-		err := svc.repo.DeleteAllByObject(txCtx, objectType, objectId)
+		listParams := middleware.ListParams{
+			Page:      1,
+			Limit:     DeleteWarrantsBatchSize,
+			SortBy:    "createdAt",
+			SortOrder: middleware.SortOrderAsc,
+		}
+		for {
+			warrantSpecs, err := svc.List(txCtx, filterOptions, listParams)
+			if err != nil {
+				return err
+			}
+
+			if len(warrantSpecs) == 0 {
+				return nil
+			}
+
+			result.Count += int64(len(warrantSpecs))
+			listParams.AfterId = warrantSpecs[len(warrantSpecs)-1].ToCursor(listParams.SortBy).ID
+			if dryRun {
+				for _, warrantSpec := range warrantSpecs {
+					if len(result.Warrants) < DeleteWarrantsPreviewLimit {
+						result.Warrants = append(result.Warrants, warrantSpec)
+					}
+				}
+
+				continue
+			}
+
+			warrantIds := make([]int64, 0)
+			for _, warrantSpec := range warrantSpecs {
+				warrantIds = append(warrantIds, warrantSpec.ID)
+			}
+
+			err = svc.ctxSvc.DeleteAllByWarrantIds(txCtx, warrantIds)
+			if err != nil {
+				return err
+			}
+
+			err = svc.repo.DeleteByIds(txCtx, warrantIds)
+			if err != nil {
+				return err
+			}
+
+			for _, warrantSpec := range warrantSpecs {
+				err = svc.changeSvc.TrackChange(txCtx, change.ChangeTypeWarrantDeleted, change.ResourceTypeWarrant, warrantSpec.String(), warrantSpec)
+				if err != nil {
+					return err
+				}
+
+				revokedEvents = append(revokedEvents, event.CreateAccessEventSpec{
+					Type:            fmt.Sprintf("%s.%s", warrantSpec.ObjectType, event.EventTypeAccessRevoked),
+					Source:          event.EventSourceApi,
+					ObjectType:      warrantSpec.ObjectType,
+					ObjectId:        warrantSpec.ObjectId,
+					Relation:        warrantSpec.Relation,
+					SubjectType:     warrantSpec.Subject.ObjectType,
+					SubjectId:       warrantSpec.Subject.ObjectId,
+					SubjectRelation: warrantSpec.Subject.Relation,
+					Context:         warrantSpec.Context,
+				})
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Track access revoked events in batches once the warrants are deleted
+	for start := 0; start < len(revokedEvents); start += DeleteWarrantsBatchSize {
+		end := start + DeleteWarrantsBatchSize
+		if end > len(revokedEvents) {
+			end = len(revokedEvents)
+		}
+
+		svc.eventSvc.TrackAccessEvents(ctx, revokedEvents[start:end])
+	}
+
+	return &result, nil
+}
+
+// DeleteRelatedWarrants deletes all warrants with the given object as their
+// object or subject, tagging them with deletionBatchId so they can be restored,
+// and tracks a change for each.
+func (svc WarrantService) DeleteRelatedWarrants(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
+	err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		err := svc.repo.DeleteAllByObject(txCtx, objectType, objectId, deletionBatchId)
 		if err != nil {
 			return err
 		}
 
-		err = svc.repo.DeleteAllBySubject(txCtx, objectType, objectId)
+		err = svc.repo.DeleteAllBySubject(txCtx, objectType, objectId, deletionBatchId)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		deletedWarrants, err := svc.repo.ListByDeletionBatchId(txCtx, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		warrantIds := make([]int64, 0, len(deletedWarrants))
+		for _, deletedWarrant := range deletedWarrants {
+			warrantIds = append(warrantIds, deletedWarrant.GetID())
+		}
+
+		contextSetSpecs, err := svc.ctxSvc.ListByWarrantId(txCtx, warrantIds)
+		if err != nil {
+			return err
+		}
+
+		return svc.trackWarrantChanges(txCtx, change.ChangeTypeWarrantDeleted, deletedWarrants, contextSetSpecs)
 	})
 	if err != nil {
 		return err
@@ -183,3 +347,238 @@ func (svc WarrantService) DeleteRelatedWarrants(ctx context.Context, objectType
 
 	return nil
 }
+
+// RestoreRelatedWarrants restores the warrants deleted along with the given
+// object, tracking a change and an access granted event for each. Warrants
+// re-created since the deletion are left untouched.
+func (svc WarrantService) RestoreRelatedWarrants(ctx context.Context, objectType string, objectId string, deletionBatchId string) error {
+	return svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		deletedWarrants, err := svc.repo.ListByDeletionBatchId(txCtx, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		warrantIds := make([]int64, 0, len(deletedWarrants))
+		for _, deletedWarrant := range deletedWarrants {
+			warrantIds = append(warrantIds, deletedWarrant.GetID())
+		}
+
+		contextSetSpecs, err := svc.ctxSvc.ListByWarrantId(txCtx, warrantIds)
+		if err != nil {
+			return err
+		}
+
+		err = svc.repo.RestoreByDeletionBatchId(txCtx, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		// warrants re-created since the deletion are left deleted
+		skippedWarrants, err := svc.repo.ListByDeletionBatchId(txCtx, deletionBatchId)
+		if err != nil {
+			return err
+		}
+
+		skippedWarrantIds := make(map[int64]bool)
+		for _, skippedWarrant := range skippedWarrants {
+			skippedWarrantIds[skippedWarrant.GetID()] = true
+		}
+
+		restoredWarrants := make([]Model, 0, len(deletedWarrants))
+		for _, deletedWarrant := range deletedWarrants {
+			if !skippedWarrantIds[deletedWarrant.GetID()] {
+				restoredWarrants = append(restoredWarrants, deletedWarrant)
+			}
+		}
+
+		err = svc.trackWarrantChanges(txCtx, change.ChangeTypeWarrantCreated, restoredWarrants, contextSetSpecs)
+		if err != nil {
+			return err
+		}
+
+		grantedEvents := make([]event.CreateAccessEventSpec, 0, len(restoredWarrants))
+		for _, restoredWarrant := range restoredWarrants {
+			warrantSpec := restoredWarrant.ToWarrantSpec()
+			grantedEvents = append(grantedEvents, event.CreateAccessEventSpec{
+				Type:            fmt.Sprintf("%s.%s", warrantSpec.ObjectType, event.EventTypeAccessGranted),
+				Source:          event.EventSourceApi,
+				ObjectType:      warrantSpec.ObjectType,
+				ObjectId:        warrantSpec.ObjectId,
+				Relation:        warrantSpec.Relation,
+				SubjectType:     warrantSpec.Subject.ObjectType,
+				SubjectId:       warrantSpec.Subject.ObjectId,
+				SubjectRelation: warrantSpec.Subject.Relation,
+				Context:         contextSetSpecs[restoredWarrant.GetID()],
+			})
+		}
+
+		for start := 0; start < len(grantedEvents); start += DeleteWarrantsBatchSize {
+			end := start + DeleteWarrantsBatchSize
+			if end > len(grantedEvents) {
+				end = len(grantedEvents)
+			}
+
+			svc.eventSvc.TrackAccessEvents(txCtx, grantedEvents[start:end])
+		}
+
+		return nil
+	})
+}
+
+// trackWarrantChanges tracks a change of the given type for each of the given
+// warrants, so watchers see each warrant deleted or restored along with an object
+func (svc WarrantService) trackWarrantChanges(ctx context.Context, changeType string, warrants []Model, contextSetSpecs map[int64]wntContext.ContextSetSpec) error {
+	for _, warrant := range warrants {
+		warrantSpec := warrant.ToWarrantSpec()
+		warrantSpec.Context = contextSetSpecs[warrant.GetID()]
+		err := svc.changeSvc.TrackChange(ctx, changeType, change.ResourceTypeWarrant, warrantSpec.String(), warrantSpec)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ApplyText creates and deletes the warrants in the given warrant text (see
+// ParseWarrantsText) in a single transaction. Every line is validated before
+// any are applied. If dryRun is true, the changes are counted but not applied.
+func (svc WarrantService) ApplyText(ctx context.Context, r io.Reader, dryRun bool) (*ApplyWarrantsTextSpec, error) {
+	warrantLines, err := ParseWarrantsText(r)
+	if err != nil {
+		return nil, err
+	}
+
+	lineErrors := make([]service.LineError, 0)
+	objectTypeSpecs := make(map[string]*objecttype.ObjectTypeSpec)
+	for _, warrantLine := range warrantLines {
+		warrantSpec := warrantLine.Warrant
+		objectTypeSpec, ok := objectTypeSpecs[warrantSpec.ObjectType]
+		if !ok {
+			objectTypeSpec, err = svc.objectTypeSvc.GetByTypeId(ctx, warrantSpec.ObjectType)
+			if err != nil {
+				switch err.(type) {
+				case *service.RecordNotFoundError:
+					objectTypeSpec = nil
+				default:
+					return nil, err
+				}
+			}
+
+			objectTypeSpecs[warrantSpec.ObjectType] = objectTypeSpec
+		}
+
+		if objectTypeSpec == nil {
+			lineErrors = append(lineErrors, service.LineError{Line: warrantLine.Line, Message: fmt.Sprintf("object type %s does not exist", warrantSpec.ObjectType)})
+			continue
+		}
+
+		if _, exists := objectTypeSpec.Relations[warrantSpec.Relation]; !exists {
+			lineErrors = append(lineErrors, service.LineError{Line: warrantLine.Line, Message: fmt.Sprintf("object type %s does not have relation %s", warrantSpec.ObjectType, warrantSpec.Relation)})
+			continue
+		}
+
+		err = objectTypeSpec.ValidateObjectId(warrantSpec.ObjectId)
+		if err != nil {
+			lineErrors = append(lineErrors, service.LineError{Line: warrantLine.Line, Message: lineErrorMessage(err)})
+			continue
+		}
+
+		err = svc.objectTypeSvc.ValidateObjectId(ctx, warrantSpec.Subject.ObjectType, warrantSpec.Subject.ObjectId)
+		if err != nil {
+			lineErrors = append(lineErrors, service.LineError{Line: warrantLine.Line, Message: lineErrorMessage(err)})
+		}
+	}
+
+	if len(lineErrors) > 0 {
+		return nil, service.NewInvalidLinesError(lineErrors)
+	}
+
+	result := ApplyWarrantsTextSpec{
+		DryRun: dryRun,
+	}
+	err = svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+		// NOTE: track whether each warrant exists as lines are applied so that
+		// repeated warrants are counted the same way in dry runs
+		exists := make(map[string]bool)
+		for _, warrantLine := range warrantLines {
+			warrantSpec := warrantLine.Warrant
+			warrantExists, ok := exists[warrantSpec.String()]
+			if !ok {
+				_, err := svc.repo.Get(txCtx, warrantSpec.ObjectType, warrantSpec.ObjectId, warrantSpec.Relation, warrantSpec.Subject.ObjectType, warrantSpec.Subject.ObjectId, warrantSpec.Subject.Relation, warrantSpec.Context.ToHash())
+				if err != nil {
+					if _, ok := err.(*service.RecordNotFoundError); !ok {
+						return err
+					}
+				}
+
+				warrantExists = err == nil
+			}
+
+			exists[warrantSpec.String()] = !warrantLine.Delete
+			if warrantExists != warrantLine.Delete {
+				result.Unchanged++
+				continue
+			}
+
+			var err error
+
+			if warrantLine.Delete {
+				result.Deleted++
+				if !dryRun {
+					err = svc.Delete(txCtx, warrantSpec)
+				}
+			} else {
+				result.Created++
+				if !dryRun {
+					_, err = svc.Create(txCtx, warrantSpec)
+				}
+			}
+
+			if err != nil {
+				if apiError, ok := err.(service.Error); ok && apiError.GetStatus() < http.StatusInternalServerError {
+					return service.NewInvalidLinesError([]service.LineError{{Line: warrantLine.Line, Message: lineErrorMessage(err)}})
+				}
+
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// ExportText writes the warrants matching the given filters to w, one per
+// line, in the format accepted by ApplyText
+func (svc WarrantService) ExportText(ctx context.Context, filterOptions *FilterOptions, w io.Writer) error {
+	listParams := middleware.ListParams{
+		Page:      1,
+		Limit:     ExportWarrantsBatchSize,
+		SortBy:    "createdAt",
+		SortOrder: middleware.SortOrderAsc,
+	}
+	for {
+		warrantSpecs, err := svc.List(ctx, filterOptions, listParams)
+		if err != nil {
+			return err
+		}
+
+		for _, warrantSpec := range warrantSpecs {
+			_, err = fmt.Fprintln(w, warrantSpec.String())
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(warrantSpecs) < listParams.Limit {
+			return nil
+		}
+
+		listParams.AfterId = warrantSpecs[len(warrantSpecs)-1].ToCursor(listParams.SortBy).ID
+	}
+}
@@ -1,12 +1,15 @@
 package authz
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	context "github.com/warrant-dev/warrant/pkg/context"
 	"github.com/warrant-dev/warrant/pkg/database"
+	"github.com/warrant-dev/warrant/pkg/service"
 )
 
 // FilterOptions type for the filter options available on the warrant table
@@ -20,6 +23,103 @@ type FilterOptions struct {
 	SubjectIds []string
 }
 
+// DeleteWarrantsSpec type for the result of deleting all warrants matching a filter
+type DeleteWarrantsSpec struct {
+	DryRun   bool           `json:"dryRun"`
+	Count    int64          `json:"count"`
+	Warrants []*WarrantSpec `json:"warrants,omitempty"` // NOTE: only returned for dry runs, limited to the first DeleteWarrantsPreviewLimit warrants
+}
+
+// WarrantTextLine is a warrant parsed from a line of warrant text
+type WarrantTextLine struct {
+	Line    int
+	Delete  bool // NOTE: true if the line is prefixed with '-'
+	Warrant WarrantSpec
+}
+
+type WarrantsTextSpec struct {
+	Text string `json:"text" validate:"required"`
+}
+
+type ApplyWarrantsTextSpec struct {
+	DryRun    bool  `json:"dryRun"`
+	Created   int64 `json:"created"`
+	Deleted   int64 `json:"deleted"`
+	Unchanged int64 `json:"unchanged"` // NOTE: warrants that already existed, or didn't exist if deleted
+}
+
+// ParseWarrantsText parses text with one warrant per line in the format
+// type:id#relation@subject[context]. Lines prefixed with '-' are deletes,
+// and blank lines and lines starting with "//" are skipped. All invalid
+// lines are returned in a single InvalidLinesError.
+func ParseWarrantsText(r io.Reader) ([]WarrantTextLine, error) {
+	warrantLines := make([]WarrantTextLine, 0)
+	lineErrors := make([]service.LineError, 0)
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		if len(warrantLines)+len(lineErrors) >= MaxWarrantsTextLines {
+			return nil, service.NewInvalidRequestError(fmt.Sprintf("Warrant text can contain at most %d warrants", MaxWarrantsTextLines))
+		}
+
+		isDelete := strings.HasPrefix(line, "-")
+		warrantSpec, err := StringToWarrantSpec(strings.TrimSpace(strings.TrimPrefix(line, "-")))
+		if err != nil {
+			lineErrors = append(lineErrors, service.LineError{
+				Line:    lineNumber,
+				Message: "must be a warrant in the format type:id#relation@subject[context]",
+			})
+			continue
+		}
+
+		err = service.ValidateStruct(warrantSpec)
+		if err != nil {
+			lineErrors = append(lineErrors, service.LineError{
+				Line:    lineNumber,
+				Message: lineErrorMessage(err),
+			})
+			continue
+		}
+
+		warrantLines = append(warrantLines, WarrantTextLine{
+			Line:    lineNumber,
+			Delete:  isDelete,
+			Warrant: *warrantSpec,
+		})
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, service.NewInvalidRequestError(fmt.Sprintf("Line %d could not be read: %s", lineNumber+1, err.Error()))
+	}
+
+	if len(lineErrors) > 0 {
+		return nil, service.NewInvalidLinesError(lineErrors)
+	}
+
+	return warrantLines, nil
+}
+
+// lineErrorMessage returns the message of an error on a line of warrant text
+func lineErrorMessage(err error) string {
+	switch err := err.(type) {
+	case *service.InvalidParameterError:
+		return fmt.Sprintf("%s %s", err.Parameter, err.Message)
+	case *service.MissingRequiredParameterError:
+		return err.Message
+	case *service.InvalidRequestError:
+		return err.Message
+	default:
+		return err.Error()
+	}
+}
+
 // SortOptions type for sorting filtered results from the warrant table
 type SortOptions struct {
 	Column      string
@@ -29,7 +129,7 @@ type SortOptions struct {
 // ObjectSpec type
 type ObjectSpec struct {
 	ObjectType string `json:"objectType" validate:"required,valid_object_type"`
-	ObjectId   string `json:"objectId" validate:"required,valid_object_id"`
+	ObjectId   string `json:"objectId" validate:"required,valid_object_id_or_path"`
 }
 
 func StringToObjectSpec(str string) (*ObjectSpec, error) {
@@ -48,7 +148,7 @@ func StringToObjectSpec(str string) (*ObjectSpec, error) {
 // SubjectSpec type
 type SubjectSpec struct {
 	ObjectType string `json:"objectType,omitempty" validate:"required_with=ObjectId,valid_object_type"`
-	ObjectId   string `json:"objectId,omitempty" validate:"required_with=ObjectType,valid_object_id"`
+	ObjectId   string `json:"objectId,omitempty" validate:"required_with=ObjectType,valid_subject_id_or_path"`
 	Relation   string `json:"relation,omitempty" validate:"valid_relation"`
 }
 
@@ -90,8 +190,11 @@ func StringToSubjectSpec(str string) (*SubjectSpec, error) {
 
 // WarrantSpec type
 type WarrantSpec struct {
+	// NOTE: ID is required here for internal use (e.g. pagination).
+	// However, we don't return it to the client.
+	ID         int64                  `json:"-"`
 	ObjectType string                 `json:"objectType" validate:"required,valid_object_type"`
-	ObjectId   string                 `json:"objectId" validate:"required,valid_object_id"`
+	ObjectId   string                 `json:"objectId" validate:"required,valid_object_id_or_path"`
 	Relation   string                 `json:"relation" validate:"required,valid_relation"`
 	Subject    *SubjectSpec           `json:"subject" validate:"required"`
 	Context    context.ContextSetSpec `json:"context,omitempty"`
@@ -187,7 +290,7 @@ func StringToWarrantSpec(warrantString string) (*WarrantSpec, error) {
 // SessionWarrantSpec type
 type SessionWarrantSpec struct {
 	ObjectType string                 `json:"objectType" validate:"required,valid_object_type"`
-	ObjectId   string                 `json:"objectId" validate:"required,valid_object_id"`
+	ObjectId   string                 `json:"objectId" validate:"required,valid_object_id_or_path"`
 	Relation   string                 `json:"relation" validate:"required,valid_relation"`
 	Context    context.ContextSetSpec `json:"context,omitempty"`
 	CreatedAt  time.Time              `json:"createdAt"`
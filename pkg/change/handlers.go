@@ -0,0 +1,200 @@
+package change
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/hlog"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+const (
+	QueryParamCursor       = "cursor"
+	QueryParamType         = "type"
+	QueryParamResourceType = "resourceType"
+	HeaderLastEventId      = "Last-Event-ID"
+
+	ContentTypeEventStream = "text/event-stream"
+	ContentTypeNDJSON      = "application/x-ndjson"
+
+	DefaultBatchSize  = 100
+	PollInterval      = time.Second
+	HeartbeatInterval = 15 * time.Second
+	SequenceBatchSize = 1000
+)
+
+func (svc ChangeService) Routes() []service.Route {
+	return []service.Route{
+		// get
+		{
+			Pattern: "/v1/watch",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, watchHandler),
+		},
+	}
+}
+
+// watchHandler streams changes to the client as they are committed, either as
+// Server-Sent Events (if the client accepts text/event-stream) or as newline
+// delimited JSON. Clients can resume from the cursor of the last change they
+// received. If no cursor is given, only changes made after the request are streamed.
+func watchHandler(svc ChangeService, w http.ResponseWriter, r *http.Request) error {
+	queryParams := r.URL.Query()
+	types := splitQueryParam(queryParams[QueryParamType])
+	resourceTypes := splitQueryParam(queryParams[QueryParamResourceType])
+	listParams := ListChangeParams{
+		Limit: DefaultBatchSize,
+	}
+
+	cursor := queryParams.Get(QueryParamCursor)
+	if cursor == "" {
+		cursor = r.Header.Get(HeaderLastEventId)
+	}
+
+	var err error
+	if cursor != "" {
+		listParams.AfterSequenceNumber, err = ParseCursor(cursor)
+		if err != nil {
+			return err
+		}
+
+		err = svc.ValidateCursor(r.Context(), listParams.AfterSequenceNumber)
+		if err != nil {
+			return err
+		}
+	} else {
+		listParams.AfterSequenceNumber, err = svc.GetLatestCursor(r.Context())
+		if err != nil {
+			return err
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return service.NewInternalError("Streaming is not supported")
+	}
+
+	useEventStream := strings.Contains(r.Header.Get("Accept"), ContentTypeEventStream)
+	if useEventStream {
+		w.Header().Set("Content-Type", ContentTypeEventStream)
+	} else {
+		w.Header().Set("Content-Type", ContentTypeNDJSON)
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	pollTicker := time.NewTicker(PollInterval)
+	defer pollTicker.Stop()
+	heartbeatTicker := time.NewTicker(HeartbeatInterval)
+	defer heartbeatTicker.Stop()
+
+	// NOTE: once the response has started, errors can no longer be sent to the
+	// client, so they are logged and the stream is closed.
+	for {
+		err := svc.SequenceChanges(r.Context())
+		if err != nil {
+			if r.Context().Err() == nil {
+				hlog.FromRequest(r).Error().Err(err).Msg("Unable to sequence changes")
+			}
+
+			return nil
+		}
+
+		changes, err := svc.List(r.Context(), listParams)
+		if err != nil {
+			if r.Context().Err() == nil {
+				hlog.FromRequest(r).Error().Err(err).Msg("Unable to list changes")
+			}
+
+			return nil
+		}
+
+		numWritten := 0
+		for _, change := range changes {
+			listParams.AfterSequenceNumber, _ = ParseCursor(change.Cursor)
+			if !matchesFilter(change.Type, types) || !matchesFilter(change.ResourceType, resourceTypes) {
+				continue
+			}
+
+			err = writeChange(w, change, useEventStream)
+			if err != nil {
+				return nil
+			}
+
+			numWritten++
+		}
+
+		if numWritten > 0 {
+			flusher.Flush()
+		}
+
+		// Keep reading without waiting while there is a backlog of changes
+		if len(changes) == int(listParams.Limit) {
+			continue
+		}
+
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-heartbeatTicker.C:
+			if useEventStream {
+				_, err = fmt.Fprint(w, ": heartbeat\n\n")
+			} else {
+				_, err = fmt.Fprint(w, "\n")
+			}
+			if err != nil {
+				return nil
+			}
+
+			flusher.Flush()
+		case <-pollTicker.C:
+		}
+	}
+}
+
+func writeChange(w http.ResponseWriter, change ChangeSpec, useEventStream bool) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	if useEventStream {
+		_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", change.Cursor, change.Type, data)
+	} else {
+		_, err = fmt.Fprintf(w, "%s\n", data)
+	}
+
+	return err
+}
+
+func splitQueryParam(values []string) []string {
+	splitValues := make([]string, 0)
+	for _, value := range values {
+		for _, splitValue := range strings.Split(value, ",") {
+			if splitValue != "" {
+				splitValues = append(splitValues, splitValue)
+			}
+		}
+	}
+
+	return splitValues
+}
+
+func matchesFilter(value string, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, filterValue := range filter {
+		if value == filterValue {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+package change
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+type Model interface {
+	GetID() int64
+	GetSequenceNumber() int64
+	GetType() string
+	GetResourceType() string
+	GetResourceId() string
+	GetData() database.NullString
+	GetCreatedAt() time.Time
+	ToChangeSpec() (*ChangeSpec, error)
+}
+
+type Change struct {
+	ID             int64               `mysql:"id" postgres:"id"`
+	SequenceNumber int64               `mysql:"sequenceNumber" postgres:"sequence_number"`
+	Type           string              `mysql:"type" postgres:"type"`
+	ResourceType   string              `mysql:"resourceType" postgres:"resource_type"`
+	ResourceId     string              `mysql:"resourceId" postgres:"resource_id"`
+	Data           database.NullString `mysql:"data" postgres:"data"`
+	CreatedAt      time.Time           `mysql:"createdAt" postgres:"created_at"`
+}
+
+func (change Change) GetID() int64 {
+	return change.ID
+}
+
+func (change Change) GetSequenceNumber() int64 {
+	return change.SequenceNumber
+}
+
+func (change Change) GetType() string {
+	return change.Type
+}
+
+func (change Change) GetResourceType() string {
+	return change.ResourceType
+}
+
+func (change Change) GetResourceId() string {
+	return change.ResourceId
+}
+
+func (change Change) GetData() database.NullString {
+	return change.Data
+}
+
+func (change Change) GetCreatedAt() time.Time {
+	return change.CreatedAt
+}
+
+func (change Change) ToChangeSpec() (*ChangeSpec, error) {
+	var data interface{}
+	if change.Data.Valid {
+		err := json.Unmarshal([]byte(change.Data.String), &data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error unmarshaling change data %s", change.Data.String)
+		}
+	}
+
+	return &ChangeSpec{
+		Cursor:       strconv.FormatInt(change.SequenceNumber, 10),
+		Type:         change.Type,
+		ResourceType: change.ResourceType,
+		ResourceId:   change.ResourceId,
+		Data:         data,
+		CreatedAt:    change.CreatedAt,
+	}, nil
+}
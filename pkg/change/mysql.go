@@ -0,0 +1,184 @@
+package change
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+type MySQLRepository struct {
+	database.SQLRepository
+}
+
+func NewMySQLRepository(db *database.MySQL) MySQLRepository {
+	return MySQLRepository{
+		database.NewSQLRepository(&db.SQL),
+	}
+}
+
+func (repo MySQLRepository) Create(ctx context.Context, model Model) (int64, error) {
+	result, err := repo.DB.ExecContext(
+		ctx,
+		`
+			INSERT INTO changeLog (
+				type,
+				resourceType,
+				resourceId,
+				data
+			) VALUES (?, ?, ?, ?)
+		`,
+		model.GetType(),
+		model.GetResourceType(),
+		model.GetResourceId(),
+		model.GetData(),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to create change")
+	}
+
+	newChangeId, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return newChangeId, nil
+}
+
+func (repo MySQLRepository) List(ctx context.Context, listParams ListChangeParams) ([]Model, error) {
+	models := make([]Model, 0)
+	changes := make([]Change, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&changes,
+		`
+			SELECT id, sequenceNumber, type, resourceType, resourceId, data, createdAt
+			FROM changeLog
+			WHERE
+				sequenceNumber > ?
+			ORDER BY sequenceNumber ASC
+			LIMIT ?
+		`,
+		listParams.AfterSequenceNumber,
+		listParams.Limit,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, "Unable to list changes from mysql")
+		}
+	}
+
+	for i := range changes {
+		models = append(models, &changes[i])
+	}
+
+	return models, nil
+}
+
+// NOTE: the sequence row is locked until the end of the transaction, so
+// sequence numbers are assigned by one transaction at a time
+func (repo MySQLRepository) AssignSequenceNumbers(ctx context.Context, limit int64) (int64, error) {
+	var lastSequenceNumber int64
+	err := repo.DB.GetContext(
+		ctx,
+		&lastSequenceNumber,
+		`
+			SELECT lastSequenceNumber
+			FROM changeLogSequence
+			WHERE
+				id = 1
+			FOR UPDATE
+		`,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to lock change sequence in mysql")
+	}
+
+	result, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE changeLog
+			JOIN (
+				SELECT id, ROW_NUMBER() OVER (ORDER BY id ASC) AS rowNumber
+				FROM changeLog
+				WHERE sequenceNumber IS NULL
+				ORDER BY id ASC
+				LIMIT ?
+			) AS unsequenced ON changeLog.id = unsequenced.id
+			SET changeLog.sequenceNumber = ? + unsequenced.rowNumber
+		`,
+		limit,
+		lastSequenceNumber,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to assign change sequence numbers in mysql")
+	}
+
+	numSequenced, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to assign change sequence numbers in mysql")
+	}
+
+	if numSequenced == 0 {
+		return 0, nil
+	}
+
+	_, err = repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE changeLogSequence
+			SET lastSequenceNumber = ?
+			WHERE
+				id = 1
+		`,
+		lastSequenceNumber+numSequenced,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to update change sequence in mysql")
+	}
+
+	return numSequenced, nil
+}
+
+func (repo MySQLRepository) GetLatestSequenceNumber(ctx context.Context) (int64, error) {
+	var latestSequenceNumber int64
+	err := repo.DB.GetContext(
+		ctx,
+		&latestSequenceNumber,
+		`
+			SELECT lastSequenceNumber
+			FROM changeLogSequence
+			WHERE
+				id = 1
+		`,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to get latest change sequence number from mysql")
+	}
+
+	return latestSequenceNumber, nil
+}
+
+// NOTE: if all changes have been purged, the next sequence number is the earliest
+func (repo MySQLRepository) GetEarliestSequenceNumber(ctx context.Context) (int64, error) {
+	var earliestSequenceNumber int64
+	err := repo.DB.GetContext(
+		ctx,
+		&earliestSequenceNumber,
+		`
+			SELECT COALESCE(MIN(sequenceNumber), (SELECT lastSequenceNumber + 1 FROM changeLogSequence WHERE id = 1))
+			FROM changeLog
+			WHERE
+				sequenceNumber IS NOT NULL
+		`,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to get earliest change sequence number from mysql")
+	}
+
+	return earliestSequenceNumber, nil
+}
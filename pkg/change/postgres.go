@@ -0,0 +1,183 @@
+package change
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+type PostgresRepository struct {
+	database.SQLRepository
+}
+
+func NewPostgresRepository(db *database.Postgres) PostgresRepository {
+	return PostgresRepository{
+		database.NewSQLRepository(&db.SQL),
+	}
+}
+
+func (repo PostgresRepository) Create(ctx context.Context, model Model) (int64, error) {
+	var newChangeId int64
+	err := repo.DB.GetContext(
+		ctx,
+		&newChangeId,
+		`
+			INSERT INTO change_log (
+				type,
+				resource_type,
+				resource_id,
+				data
+			) VALUES (?, ?, ?, ?)
+			RETURNING id
+		`,
+		model.GetType(),
+		model.GetResourceType(),
+		model.GetResourceId(),
+		model.GetData(),
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to create change")
+	}
+
+	return newChangeId, nil
+}
+
+func (repo PostgresRepository) List(ctx context.Context, listParams ListChangeParams) ([]Model, error) {
+	models := make([]Model, 0)
+	changes := make([]Change, 0)
+	err := repo.DB.SelectContext(
+		ctx,
+		&changes,
+		`
+			SELECT id, sequence_number, type, resource_type, resource_id, data, created_at
+			FROM change_log
+			WHERE
+				sequence_number > ?
+			ORDER BY sequence_number ASC
+			LIMIT ?
+		`,
+		listParams.AfterSequenceNumber,
+		listParams.Limit,
+	)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return models, nil
+		default:
+			return nil, errors.Wrap(err, "Unable to list changes from postgres")
+		}
+	}
+
+	for i := range changes {
+		models = append(models, &changes[i])
+	}
+
+	return models, nil
+}
+
+// NOTE: the sequence row is locked until the end of the transaction, so
+// sequence numbers are assigned by one transaction at a time
+func (repo PostgresRepository) AssignSequenceNumbers(ctx context.Context, limit int64) (int64, error) {
+	var lastSequenceNumber int64
+	err := repo.DB.GetContext(
+		ctx,
+		&lastSequenceNumber,
+		`
+			SELECT last_sequence_number
+			FROM change_log_sequence
+			WHERE
+				id = 1
+			FOR UPDATE
+		`,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to lock change sequence in postgres")
+	}
+
+	result, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE change_log
+			SET sequence_number = ? + unsequenced.row_number
+			FROM (
+				SELECT id, ROW_NUMBER() OVER (ORDER BY id ASC) AS row_number
+				FROM change_log
+				WHERE sequence_number IS NULL
+				ORDER BY id ASC
+				LIMIT ?
+			) AS unsequenced
+			WHERE change_log.id = unsequenced.id
+		`,
+		lastSequenceNumber,
+		limit,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to assign change sequence numbers in postgres")
+	}
+
+	numSequenced, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to assign change sequence numbers in postgres")
+	}
+
+	if numSequenced == 0 {
+		return 0, nil
+	}
+
+	_, err = repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE change_log_sequence
+			SET last_sequence_number = ?
+			WHERE
+				id = 1
+		`,
+		lastSequenceNumber+numSequenced,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to update change sequence in postgres")
+	}
+
+	return numSequenced, nil
+}
+
+func (repo PostgresRepository) GetLatestSequenceNumber(ctx context.Context) (int64, error) {
+	var latestSequenceNumber int64
+	err := repo.DB.GetContext(
+		ctx,
+		&latestSequenceNumber,
+		`
+			SELECT last_sequence_number
+			FROM change_log_sequence
+			WHERE
+				id = 1
+		`,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to get latest change sequence number from postgres")
+	}
+
+	return latestSequenceNumber, nil
+}
+
+// NOTE: if all changes have been purged, the next sequence number is the earliest
+func (repo PostgresRepository) GetEarliestSequenceNumber(ctx context.Context) (int64, error) {
+	var earliestSequenceNumber int64
+	err := repo.DB.GetContext(
+		ctx,
+		&earliestSequenceNumber,
+		`
+			SELECT COALESCE(MIN(sequence_number), (SELECT last_sequence_number + 1 FROM change_log_sequence WHERE id = 1))
+			FROM change_log
+			WHERE
+				sequence_number IS NOT NULL
+		`,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Unable to get earliest change sequence number from postgres")
+	}
+
+	return earliestSequenceNumber, nil
+}
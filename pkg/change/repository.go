@@ -0,0 +1,40 @@
+package change
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+type ChangeRepository interface {
+	Create(context.Context, Model) (int64, error)
+	List(context.Context, ListChangeParams) ([]Model, error)
+	// AssignSequenceNumbers assigns the next sequence numbers to up to limit
+	// committed changes without one, in id order, and returns the number of
+	// changes sequenced. It must be called within a transaction.
+	AssignSequenceNumbers(ctx context.Context, limit int64) (int64, error)
+	GetLatestSequenceNumber(context.Context) (int64, error)
+	GetEarliestSequenceNumber(context.Context) (int64, error)
+}
+
+func NewRepository(db database.Database) (ChangeRepository, error) {
+	switch db.Type() {
+	case database.TypeMySQL:
+		mysql, ok := db.(*database.MySQL)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s database config", database.TypeMySQL)
+		}
+
+		return NewMySQLRepository(mysql), nil
+	case database.TypePostgres:
+		postgres, ok := db.(*database.Postgres)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s database config", database.TypePostgres)
+		}
+
+		return NewPostgresRepository(postgres), nil
+	default:
+		return nil, fmt.Errorf("unsupported database type %s specified", db.Type())
+	}
+}
@@ -0,0 +1,116 @@
+package change
+
+import (
+	"context"
+
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+const (
+	ChangeTypeWarrantCreated    = "warrant.created"
+	ChangeTypeWarrantDeleted    = "warrant.deleted"
+	ChangeTypeObjectTypeCreated = "object-type.created"
+	ChangeTypeObjectTypeUpdated = "object-type.updated"
+	ChangeTypeObjectTypeDeleted = "object-type.deleted"
+
+	ResourceTypeWarrant    = "warrant"
+	ResourceTypeObjectType = "object-type"
+)
+
+type ChangeService struct {
+	service.BaseService
+	repo ChangeRepository
+}
+
+func NewService(env service.Env, repo ChangeRepository) ChangeService {
+	return ChangeService{
+		BaseService: service.NewBaseService(env),
+		repo:        repo,
+	}
+}
+
+// TrackChange records a change to the changefeed. Unlike events, changes are
+// written synchronously so that they are committed (or rolled back) along with
+// the transaction that made the change.
+func (svc ChangeService) TrackChange(ctx context.Context, changeType string, resourceType string, resourceId string, data interface{}) error {
+	change, err := CreateChangeSpec{
+		Type:         changeType,
+		ResourceType: resourceType,
+		ResourceId:   resourceId,
+		Data:         data,
+	}.ToChange()
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.repo.Create(ctx, change)
+	return err
+}
+
+func (svc ChangeService) List(ctx context.Context, listParams ListChangeParams) ([]ChangeSpec, error) {
+	changeSpecs := make([]ChangeSpec, 0)
+	changes, err := svc.repo.List(ctx, listParams)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		changeSpec, err := change.ToChangeSpec()
+		if err != nil {
+			return nil, err
+		}
+
+		changeSpecs = append(changeSpecs, *changeSpec)
+	}
+
+	return changeSpecs, nil
+}
+
+// SequenceChanges assigns sequence numbers to the changes committed since it
+// last ran. Change ids are allocated before the transaction that made a change
+// commits, so changes can commit out of id order or be rolled back. A change
+// only gets a sequence number once it has committed, and numbers are assigned
+// by one transaction at a time, so sequence numbers follow commit order and
+// have no gaps. The changefeed is streamed in sequence number order.
+func (svc ChangeService) SequenceChanges(ctx context.Context) error {
+	for {
+		var numSequenced int64
+		err := svc.Env().DB().WithinTransaction(ctx, func(txCtx context.Context) error {
+			var err error
+			numSequenced, err = svc.repo.AssignSequenceNumbers(txCtx, SequenceBatchSize)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if numSequenced < SequenceBatchSize {
+			return nil
+		}
+	}
+}
+
+// GetLatestCursor returns the cursor of the latest committed change
+func (svc ChangeService) GetLatestCursor(ctx context.Context) (int64, error) {
+	err := svc.SequenceChanges(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return svc.repo.GetLatestSequenceNumber(ctx)
+}
+
+// ValidateCursor returns an error if changes after the given cursor have been
+// purged, since the changefeed can no longer be resumed from it without loss
+func (svc ChangeService) ValidateCursor(ctx context.Context, cursor int64) error {
+	earliestSequenceNumber, err := svc.repo.GetEarliestSequenceNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	if cursor < earliestSequenceNumber-1 {
+		return service.NewInvalidParameterError(QueryParamCursor, "Must be the cursor of a change that has not been purged")
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package change
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/warrant-dev/warrant/pkg/database"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+type CreateChangeSpec struct {
+	Type         string      `json:"type"`
+	ResourceType string      `json:"resourceType"`
+	ResourceId   string      `json:"resourceId"`
+	Data         interface{} `json:"data"`
+}
+
+func (spec CreateChangeSpec) ToChange() (*Change, error) {
+	var data *string
+	if spec.Data != nil {
+		serializedData, err := json.Marshal(spec.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error marshaling change data %v", spec.Data)
+		}
+
+		dataStr := string(serializedData)
+		data = &dataStr
+	}
+
+	return &Change{
+		Type:         spec.Type,
+		ResourceType: spec.ResourceType,
+		ResourceId:   spec.ResourceId,
+		Data:         database.StringToNullString(data),
+	}, nil
+}
+
+// ChangeSpec is a single entry of the changefeed. Cursor is opaque to clients
+// and can be passed back to resume the changefeed after this change.
+type ChangeSpec struct {
+	Cursor       string      `json:"cursor"`
+	Type         string      `json:"type"`
+	ResourceType string      `json:"resourceType"`
+	ResourceId   string      `json:"resourceId"`
+	Data         interface{} `json:"data,omitempty"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
+
+type ListChangeParams struct {
+	AfterSequenceNumber int64
+	Limit               int64
+}
+
+func ParseCursor(cursor string) (int64, error) {
+	sequenceNumber, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || sequenceNumber < 0 {
+		return 0, service.NewInvalidParameterError(QueryParamCursor, "Must be the cursor of a previously received change")
+	}
+
+	return sequenceNumber, nil
+}
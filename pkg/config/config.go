@@ -14,10 +14,10 @@ import (
 )
 
 const (
-	DefaultMySQLDatastoreMigrationSource     = "github://warrant-dev/warrant/migrations/datastore/mysql"
-	DefaultMySQLEventstoreMigrationSource    = "github://warrant-dev/warrant/migrations/eventstore/mysql"
-	DefaultPostgresDatastoreMigrationSource  = "github://warrant-dev/warrant/migrations/datastore/postgres"
-	DefaultPostgresEventstoreMigrationSource = "github://warrant-dev/warrant/migrations/eventstore/postgres"
+	DefaultMySQLDatastoreMigrationSource     = "embed://datastore/mysql"
+	DefaultMySQLEventstoreMigrationSource    = "embed://eventstore/mysql"
+	DefaultPostgresDatastoreMigrationSource  = "embed://datastore/postgres"
+	DefaultPostgresEventstoreMigrationSource = "embed://eventstore/postgres"
 	PrefixWarrant                            = "warrant"
 )
 
@@ -27,8 +27,11 @@ type Config struct {
 	EnableAccessLog bool             `mapstructure:"enableAccessLog"`
 	Datastore       DatastoreConfig  `mapstructure:"datastore"`
 	Eventstore      EventstoreConfig `mapstructure:"eventstore"`
+	AutoMigrate     bool             `mapstructure:"autoMigrate"` // NOTE: only applies to serve. If false, or for any other command, databases must be migrated with `warrant migrate` first
 	ApiKey          string           `mapstructure:"apiKey"`
 	Authentication  AuthConfig       `mapstructure:"authentication"`
+	ObjectSync      ObjectSyncConfig `mapstructure:"objectSync"`
+	Retention       RetentionConfig  `mapstructure:"retention"`
 }
 
 type DatastoreConfig struct {
@@ -62,6 +65,18 @@ type EventstoreConfig struct {
 	Postgres *PostgresConfig `mapstructure:"postgres"`
 }
 
+type ObjectSyncConfig struct {
+	PollInterval int                        `mapstructure:"pollInterval"` // NOTE: in seconds, polling is disabled if 0
+	Sources      map[string]DatastoreConfig `mapstructure:"sources"`      // NOTE: map key = dbName of an object type source
+}
+
+type RetentionConfig struct {
+	Days          int `mapstructure:"days"`          // NOTE: soft-deleted rows are purged after this many days, purging is disabled if 0
+	ChangeDays    int `mapstructure:"changeDays"`    // NOTE: changefeed changes are purged after this many days, purging is disabled if 0
+	BatchSize     int `mapstructure:"batchSize"`     // NOTE: max number of rows deleted per statement
+	PurgeInterval int `mapstructure:"purgeInterval"` // NOTE: in seconds
+}
+
 type AuthConfig struct {
 	Provider      string `mapstructure:"provider"`
 	PublicKey     string `mapstructure:"publicKey"`
@@ -76,10 +91,14 @@ func NewConfig() Config {
 	viper.SetDefault("port", 8000)
 	viper.SetDefault("levelLevel", zerolog.DebugLevel)
 	viper.SetDefault("enableAccessLog", true)
+	viper.SetDefault("autoMigrate", true)
 	viper.SetDefault("datastore.mysql.migrationSource", DefaultMySQLDatastoreMigrationSource)
 	viper.SetDefault("eventstore.mysql.migrationSource", DefaultMySQLEventstoreMigrationSource)
 	viper.SetDefault("datastore.postgres.migrationSource", DefaultPostgresDatastoreMigrationSource)
 	viper.SetDefault("eventstore.postgres.migrationSource", DefaultPostgresEventstoreMigrationSource)
+	viper.SetDefault("retention.changeDays", 7)
+	viper.SetDefault("retention.batchSize", 1000)
+	viper.SetDefault("retention.purgeInterval", 3600)
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
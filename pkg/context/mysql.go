@@ -120,3 +120,35 @@ func (repository MySQLRepository) DeleteAllByWarrantId(ctx context.Context, warr
 
 	return nil
 }
+
+func (repository MySQLRepository) DeleteAllByWarrantIds(ctx context.Context, warrantIds []int64) error {
+	if len(warrantIds) == 0 {
+		return nil
+	}
+
+	warrantIdStrings := make([]string, 0)
+	for _, warrantId := range warrantIds {
+		warrantIdStrings = append(warrantIdStrings, strconv.FormatInt(warrantId, 10))
+	}
+
+	_, err := repository.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+				UPDATE context
+				SET
+					deletedAt = ?
+				WHERE
+					warrantId IN (%s) AND
+					deletedAt IS NULL
+			`,
+			strings.Join(warrantIdStrings, ", "),
+		),
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete contexts for warrant ids %s from mysql", strings.Join(warrantIdStrings, ", ")))
+	}
+
+	return nil
+}
@@ -120,3 +120,35 @@ func (repository PostgresRepository) DeleteAllByWarrantId(ctx context.Context, w
 
 	return nil
 }
+
+func (repository PostgresRepository) DeleteAllByWarrantIds(ctx context.Context, warrantIds []int64) error {
+	if len(warrantIds) == 0 {
+		return nil
+	}
+
+	warrantIdStrings := make([]string, 0)
+	for _, warrantId := range warrantIds {
+		warrantIdStrings = append(warrantIdStrings, strconv.FormatInt(warrantId, 10))
+	}
+
+	_, err := repository.DB.ExecContext(
+		ctx,
+		fmt.Sprintf(
+			`
+				UPDATE context
+				SET
+					deleted_at = ?
+				WHERE
+					warrant_id IN (%s) AND
+					deleted_at IS NULL
+			`,
+			strings.Join(warrantIdStrings, ", "),
+		),
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("Unable to delete contexts for warrant ids %s from postgres", strings.Join(warrantIdStrings, ", ")))
+	}
+
+	return nil
+}
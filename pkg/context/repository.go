@@ -12,6 +12,7 @@ type ContextRepository interface {
 	CreateAll(ctx context.Context, contexts []Model) ([]Model, error)
 	ListByWarrantId(ctx context.Context, warrantIds []int64) ([]Model, error)
 	DeleteAllByWarrantId(ctx context.Context, warrantId int64) error
+	DeleteAllByWarrantIds(ctx context.Context, warrantIds []int64) error
 }
 
 func NewRepository(db database.Database) (ContextRepository, error) {
@@ -49,3 +49,7 @@ func (svc ContextService) ListByWarrantId(ctx context.Context, warrantIds []int6
 func (svc ContextService) DeleteAllByWarrantId(ctx context.Context, warrantId int64) error {
 	return svc.repo.DeleteAllByWarrantId(ctx, warrantId)
 }
+
+func (svc ContextService) DeleteAllByWarrantIds(ctx context.Context, warrantIds []int64) error {
+	return svc.repo.DeleteAllByWarrantIds(ctx, warrantIds)
+}
@@ -12,6 +12,9 @@ type Database interface {
 	Type() string
 	Connect(ctx context.Context) error
 	Migrate(ctx context.Context, toVersion uint) error
+	MigrateDown(ctx context.Context, steps uint) error
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+	ForceMigrationVersion(ctx context.Context, version int) error
 	Ping(ctx context.Context) error
 	WithinTransaction(ctx context.Context, txCallback func(ctx context.Context) error) error
 }
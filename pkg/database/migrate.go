@@ -0,0 +1,79 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/rs/zerolog/log"
+	"github.com/warrant-dev/warrant/migrations"
+)
+
+// EmbeddedMigrationSourcePrefix is the prefix of migration sources that are
+// read from the migrations embedded in the binary (e.g. embed://datastore/mysql)
+const EmbeddedMigrationSourcePrefix = "embed://"
+
+// newMigrate returns a migrate instance that migrates the database at
+// databaseUrl using the migrations from the given source
+func newMigrate(migrationSource string, databaseUrl string) (*migrate.Migrate, error) {
+	if !strings.HasPrefix(migrationSource, EmbeddedMigrationSourcePrefix) {
+		return migrate.New(migrationSource, databaseUrl)
+	}
+
+	sourceDriver, err := iofs.New(migrations.FS, strings.TrimPrefix(migrationSource, EmbeddedMigrationSourcePrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithSourceInstance("iofs", sourceDriver, databaseUrl)
+}
+
+// ValidateMigrationVersion returns an error if the last migration of a
+// database did not complete or if its currentVersion is newer than
+// latestVersion, which means it was migrated by a newer version of warrant
+func ValidateMigrationVersion(databaseName string, currentVersion uint, dirty bool, latestVersion uint) error {
+	if dirty {
+		return fmt.Errorf("database %s is at version %d, but that migration did not complete: fix the database and run `warrant migrate force`", databaseName, currentVersion)
+	}
+
+	if currentVersion > latestVersion {
+		return fmt.Errorf("database %s is at version %d, which is newer than the latest version (%d) supported by this version of warrant: upgrade warrant before starting", databaseName, currentVersion, latestVersion)
+	}
+
+	return nil
+}
+
+// migrateTo migrates the database to toVersion, refusing to migrate a
+// database that fails ValidateMigrationVersion
+func migrateTo(mig *migrate.Migrate, databaseName string, toVersion uint) error {
+	currentVersion, dirty, err := migrationVersion(mig)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateMigrationVersion(databaseName, currentVersion, dirty, toVersion)
+	if err != nil {
+		return err
+	}
+
+	if currentVersion == toVersion {
+		log.Debug().Msg("Migrations already up-to-date")
+		return nil
+	}
+
+	numStepsToMigrate := toVersion - currentVersion
+	log.Debug().Msgf("Applying %d migration(s)", numStepsToMigrate)
+	return mig.Steps(int(numStepsToMigrate))
+}
+
+// migrationVersion returns the current version of the database, which is 0
+// if no migrations have been applied
+func migrationVersion(mig *migrate.Migrate) (uint, bool, error) {
+	version, dirty, err := mig.Version()
+	if err == migrate.ErrNilVersion {
+		return 0, false, nil
+	}
+
+	return version, dirty, err
+}
@@ -79,40 +79,73 @@ func (ds *MySQL) Connect(ctx context.Context) error {
 func (ds MySQL) Migrate(ctx context.Context, toVersion uint) error {
 	log.Debug().Msgf("Migrating mysql database %s", ds.Config.Database)
 	// migrate database to latest schema
-	mig, err := migrate.New(
-		ds.Config.MigrationSource,
-		fmt.Sprintf("mysql://%s:%s@tcp(%s:3306)/%s?multiStatements=true", ds.Config.Username, ds.Config.Password, ds.Config.Hostname, ds.Config.Database),
-	)
+	mig, err := ds.newMigrate()
 	if err != nil {
 		return errors.Wrap(err, "Error migrating mysql database")
 	}
 
 	defer mig.Close()
-	currentVersion, _, err := mig.Version()
+	err = migrateTo(mig, ds.Config.Database, toVersion)
 	if err != nil {
-		if err == migrate.ErrNilVersion {
-			currentVersion = 0
-		} else {
-			return errors.Wrap(err, "Error migrating mysql database")
-		}
+		return errors.Wrap(err, "Error migrating mysql database")
 	}
 
-	if currentVersion == toVersion {
-		log.Debug().Msg("Migrations already up-to-date")
-		return nil
+	log.Debug().Msgf("Migrations for database %s up-to-date.", ds.Config.Database)
+	return nil
+}
+
+func (ds MySQL) MigrateDown(ctx context.Context, steps uint) error {
+	mig, err := ds.newMigrate()
+	if err != nil {
+		return errors.Wrap(err, "Error migrating mysql database")
 	}
 
-	numStepsToMigrate := toVersion - currentVersion
-	log.Debug().Msgf("Applying %d migration(s)", numStepsToMigrate)
-	err = mig.Steps(int(numStepsToMigrate))
+	defer mig.Close()
+	err = mig.Steps(-int(steps))
 	if err != nil {
 		return errors.Wrap(err, "Error migrating mysql database")
 	}
 
-	log.Debug().Msgf("Migrations for database %s up-to-date.", ds.Config.Database)
 	return nil
 }
 
+func (ds MySQL) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	mig, err := ds.newMigrate()
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error getting mysql database version")
+	}
+
+	defer mig.Close()
+	version, dirty, err := migrationVersion(mig)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error getting mysql database version")
+	}
+
+	return version, dirty, nil
+}
+
+func (ds MySQL) ForceMigrationVersion(ctx context.Context, version int) error {
+	mig, err := ds.newMigrate()
+	if err != nil {
+		return errors.Wrap(err, "Error forcing mysql database version")
+	}
+
+	defer mig.Close()
+	err = mig.Force(version)
+	if err != nil {
+		return errors.Wrap(err, "Error forcing mysql database version")
+	}
+
+	return nil
+}
+
+func (ds MySQL) newMigrate() (*migrate.Migrate, error) {
+	return newMigrate(
+		ds.Config.MigrationSource,
+		fmt.Sprintf("mysql://%s:%s@tcp(%s:3306)/%s?multiStatements=true", ds.Config.Username, ds.Config.Password, ds.Config.Hostname, ds.Config.Database),
+	)
+}
+
 func (ds MySQL) Ping(ctx context.Context) error {
 	return ds.DB.PingContext(ctx)
 }
@@ -85,41 +85,74 @@ func (ds *Postgres) Connect(ctx context.Context) error {
 func (ds Postgres) Migrate(ctx context.Context, toVersion uint) error {
 	log.Debug().Msgf("Migrating postgres database %s", ds.Config.Database)
 	// migrate database to latest schema
-	usernamePassword := url.UserPassword(ds.Config.Username, ds.Config.Password).String()
-	mig, err := migrate.New(
-		ds.Config.MigrationSource,
-		fmt.Sprintf("postgres://%s@%s/%s?sslmode=%s", usernamePassword, ds.Config.Hostname, ds.Config.Database, ds.Config.SSLMode),
-	)
+	mig, err := ds.newMigrate()
 	if err != nil {
 		return errors.Wrap(err, "Error migrating postgres database")
 	}
 
 	defer mig.Close()
-	currentVersion, _, err := mig.Version()
+	err = migrateTo(mig, ds.Config.Database, toVersion)
 	if err != nil {
-		if err == migrate.ErrNilVersion {
-			currentVersion = 0
-		} else {
-			return errors.Wrap(err, "Error migrating postgres database")
-		}
+		return errors.Wrap(err, "Error migrating postgres database")
 	}
 
-	if currentVersion == toVersion {
-		log.Debug().Msg("Migrations already up-to-date")
-		return nil
+	log.Debug().Msgf("Migrations for database %s up-to-date.", ds.Config.Database)
+	return nil
+}
+
+func (ds Postgres) MigrateDown(ctx context.Context, steps uint) error {
+	mig, err := ds.newMigrate()
+	if err != nil {
+		return errors.Wrap(err, "Error migrating postgres database")
 	}
 
-	numStepsToMigrate := toVersion - currentVersion
-	log.Debug().Msgf("Applying %d migration(s)", numStepsToMigrate)
-	err = mig.Steps(int(numStepsToMigrate))
+	defer mig.Close()
+	err = mig.Steps(-int(steps))
 	if err != nil {
 		return errors.Wrap(err, "Error migrating postgres database")
 	}
 
-	log.Debug().Msgf("Migrations for database %s up-to-date.", ds.Config.Database)
 	return nil
 }
 
+func (ds Postgres) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	mig, err := ds.newMigrate()
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error getting postgres database version")
+	}
+
+	defer mig.Close()
+	version, dirty, err := migrationVersion(mig)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "Error getting postgres database version")
+	}
+
+	return version, dirty, nil
+}
+
+func (ds Postgres) ForceMigrationVersion(ctx context.Context, version int) error {
+	mig, err := ds.newMigrate()
+	if err != nil {
+		return errors.Wrap(err, "Error forcing postgres database version")
+	}
+
+	defer mig.Close()
+	err = mig.Force(version)
+	if err != nil {
+		return errors.Wrap(err, "Error forcing postgres database version")
+	}
+
+	return nil
+}
+
+func (ds Postgres) newMigrate() (*migrate.Migrate, error) {
+	usernamePassword := url.UserPassword(ds.Config.Username, ds.Config.Password).String()
+	return newMigrate(
+		ds.Config.MigrationSource,
+		fmt.Sprintf("postgres://%s@%s/%s?sslmode=%s", usernamePassword, ds.Config.Hostname, ds.Config.Database, ds.Config.SSLMode),
+	)
+}
+
 func (ds Postgres) Ping(ctx context.Context) error {
 	return ds.DB.PingContext(ctx)
 }
@@ -16,6 +16,7 @@ const (
 	EventTypeAccessRevoked = "access_revoked"
 	EventTypeCreated       = "created"
 	EventTypeDeleted       = "deleted"
+	EventTypeRestored      = "restored"
 	EventTypeUpdated       = "updated"
 )
 
@@ -31,7 +32,24 @@ func NewService(env service.Env, repo EventRepository) EventService {
 	}
 }
 
+type untrackedKey struct{}
+
+// WithoutTracking returns a copy of ctx in which no events are tracked, e.g.
+// for changes made in a transaction that is always rolled back
+func WithoutTracking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, untrackedKey{}, true)
+}
+
+func isTracked(ctx context.Context) bool {
+	untracked, _ := ctx.Value(untrackedKey{}).(bool)
+	return !untracked
+}
+
 func (svc EventService) TrackResourceCreated(ctx context.Context, resourceType string, resourceId string, meta interface{}) {
+	if !isTracked(ctx) {
+		return
+	}
+
 	go svc.TrackResourceCreatedSync(context.Background(), resourceType, resourceId, meta)
 }
 
@@ -46,6 +64,10 @@ func (svc EventService) TrackResourceCreatedSync(ctx context.Context, resourceTy
 }
 
 func (svc EventService) TrackResourceUpdated(ctx context.Context, resourceType string, resourceId string, meta interface{}) {
+	if !isTracked(ctx) {
+		return
+	}
+
 	go svc.TrackResourceUpdatedSync(context.Background(), resourceType, resourceId, meta)
 }
 
@@ -60,6 +82,10 @@ func (svc EventService) TrackResourceUpdatedSync(ctx context.Context, resourceTy
 }
 
 func (svc EventService) TrackResourceDeleted(ctx context.Context, resourceType string, resourceId string, meta interface{}) {
+	if !isTracked(ctx) {
+		return
+	}
+
 	go svc.TrackResourceDeletedSync(context.Background(), resourceType, resourceId, meta)
 }
 
@@ -73,11 +99,37 @@ func (svc EventService) TrackResourceDeletedSync(ctx context.Context, resourceTy
 	})
 }
 
+func (svc EventService) TrackResourceRestored(ctx context.Context, resourceType string, resourceId string, meta interface{}) {
+	if !isTracked(ctx) {
+		return
+	}
+
+	go svc.TrackResourceRestoredSync(context.Background(), resourceType, resourceId, meta)
+}
+
+func (svc EventService) TrackResourceRestoredSync(ctx context.Context, resourceType string, resourceId string, meta interface{}) error {
+	return svc.TrackResourceEventSync(ctx, CreateResourceEventSpec{
+		Type:         fmt.Sprintf("%s.%s", resourceType, EventTypeRestored),
+		Source:       EventSourceApi,
+		ResourceType: resourceType,
+		ResourceId:   resourceId,
+		Meta:         meta,
+	})
+}
+
 func (svc EventService) TrackResourceEvent(ctx context.Context, resourceEventSpec CreateResourceEventSpec) {
+	if !isTracked(ctx) {
+		return
+	}
+
 	go svc.TrackResourceEventSync(context.Background(), resourceEventSpec)
 }
 
 func (svc EventService) TrackResourceEventSync(ctx context.Context, resourceEventSpec CreateResourceEventSpec) error {
+	if !isTracked(ctx) {
+		return nil
+	}
+
 	resourceEvent, err := resourceEventSpec.ToResourceEvent()
 	if err != nil {
 		return err
@@ -87,10 +139,18 @@ func (svc EventService) TrackResourceEventSync(ctx context.Context, resourceEven
 }
 
 func (svc EventService) TrackResourceEvents(ctx context.Context, resourceEventSpecs []CreateResourceEventSpec) {
+	if !isTracked(ctx) {
+		return
+	}
+
 	go svc.TrackResourceEventsSync(context.Background(), resourceEventSpecs)
 }
 
 func (svc EventService) TrackResourceEventsSync(ctx context.Context, resourceEventSpecs []CreateResourceEventSpec) error {
+	if !isTracked(ctx) {
+		return nil
+	}
+
 	resourceEvents := make([]ResourceEventModel, 0)
 	for _, resourceEventSpec := range resourceEventSpecs {
 		resourceEvent, err := resourceEventSpec.ToResourceEvent()
@@ -124,6 +184,10 @@ func (svc EventService) ListResourceEvents(ctx context.Context, listParams ListR
 }
 
 func (svc EventService) TrackAccessGrantedEvent(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec) {
+	if !isTracked(ctx) {
+		return
+	}
+
 	go svc.TrackAccessGrantedEventSync(context.Background(), objectType, objectId, relation, subjectType, subjectId, subjectRelation, wntCtx)
 }
 
@@ -142,6 +206,10 @@ func (svc EventService) TrackAccessGrantedEventSync(ctx context.Context, objectT
 }
 
 func (svc EventService) TrackAccessRevokedEvent(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec) {
+	if !isTracked(ctx) {
+		return
+	}
+
 	go svc.TrackAccessRevokedEventSync(context.Background(), objectType, objectId, relation, subjectType, subjectId, subjectRelation, wntCtx)
 }
 
@@ -159,11 +227,15 @@ func (svc EventService) TrackAccessRevokedEventSync(ctx context.Context, objectT
 	})
 }
 
-func (svc EventService) TrackAccessAllowedEvent(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec) {
-	go svc.TrackAccessAllowedEventSync(context.Background(), objectType, objectId, relation, subjectType, subjectId, subjectRelation, wntCtx)
+func (svc EventService) TrackAccessAllowedEvent(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec, meta interface{}) {
+	if !isTracked(ctx) {
+		return
+	}
+
+	go svc.TrackAccessAllowedEventSync(context.Background(), objectType, objectId, relation, subjectType, subjectId, subjectRelation, wntCtx, meta)
 }
 
-func (svc EventService) TrackAccessAllowedEventSync(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec) error {
+func (svc EventService) TrackAccessAllowedEventSync(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec, meta interface{}) error {
 	return svc.TrackAccessEventSync(ctx, CreateAccessEventSpec{
 		Type:            fmt.Sprintf("%s.%s", objectType, EventTypeAccessAllowed),
 		Source:          EventSourceApi,
@@ -174,14 +246,19 @@ func (svc EventService) TrackAccessAllowedEventSync(ctx context.Context, objectT
 		SubjectId:       subjectId,
 		SubjectRelation: subjectRelation,
 		Context:         wntCtx,
+		Meta:            meta,
 	})
 }
 
-func (svc EventService) TrackAccessDeniedEvent(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec) {
-	go svc.TrackAccessDeniedEventSync(context.Background(), objectType, objectId, relation, subjectType, subjectId, subjectRelation, wntCtx)
+func (svc EventService) TrackAccessDeniedEvent(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec, meta interface{}) {
+	if !isTracked(ctx) {
+		return
+	}
+
+	go svc.TrackAccessDeniedEventSync(context.Background(), objectType, objectId, relation, subjectType, subjectId, subjectRelation, wntCtx, meta)
 }
 
-func (svc EventService) TrackAccessDeniedEventSync(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec) error {
+func (svc EventService) TrackAccessDeniedEventSync(ctx context.Context, objectType string, objectId string, relation string, subjectType string, subjectId string, subjectRelation string, wntCtx wntContext.ContextSetSpec, meta interface{}) error {
 	return svc.TrackAccessEventSync(ctx, CreateAccessEventSpec{
 		Type:            fmt.Sprintf("%s.%s", objectType, EventTypeAccessDenied),
 		Source:          EventSourceApi,
@@ -192,14 +269,23 @@ func (svc EventService) TrackAccessDeniedEventSync(ctx context.Context, objectTy
 		SubjectId:       subjectId,
 		SubjectRelation: subjectRelation,
 		Context:         wntCtx,
+		Meta:            meta,
 	})
 }
 
 func (svc EventService) TrackAccessEvent(ctx context.Context, accessEventSpec CreateAccessEventSpec) {
+	if !isTracked(ctx) {
+		return
+	}
+
 	go svc.TrackAccessEventSync(context.Background(), accessEventSpec)
 }
 
 func (svc EventService) TrackAccessEventSync(ctx context.Context, accessEventSpec CreateAccessEventSpec) error {
+	if !isTracked(ctx) {
+		return nil
+	}
+
 	accessEvent, err := accessEventSpec.ToAccessEvent()
 	if err != nil {
 		return err
@@ -209,10 +295,18 @@ func (svc EventService) TrackAccessEventSync(ctx context.Context, accessEventSpe
 }
 
 func (svc EventService) TrackAccessEvents(ctx context.Context, accessEventSpecs []CreateAccessEventSpec) {
+	if !isTracked(ctx) {
+		return
+	}
+
 	go svc.TrackAccessEventsSync(context.Background(), accessEventSpecs)
 }
 
 func (svc EventService) TrackAccessEventsSync(ctx context.Context, accessEventSpecs []CreateAccessEventSpec) error {
+	if !isTracked(ctx) {
+		return nil
+	}
+
 	accessEvents := make([]AccessEventModel, 0)
 	for _, accessEventSpec := range accessEventSpecs {
 		accessEvent, err := accessEventSpec.ToAccessEvent()
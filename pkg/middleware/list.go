@@ -2,9 +2,12 @@ package middleware
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/warrant-dev/warrant/pkg/service"
@@ -22,6 +25,10 @@ const (
 	paramNameBeforeId         = "beforeId"
 	paramNameAfterValue       = "afterValue"
 	paramNameBeforeValue      = "beforeValue"
+	paramNameNextCursor       = "nextCursor"
+	paramNamePrevCursor       = "prevCursor"
+	headerNextCursor          = "Warrant-Next-Cursor"
+	headerPrevCursor          = "Warrant-Prev-Cursor"
 	defaultLimit              = 25
 	defaultPage               = 1
 	contextKeyLimit       key = iota
@@ -40,6 +47,14 @@ const (
 
 type SortOrder int
 
+func (so SortOrder) Reverse() SortOrder {
+	if so == SortOrderAsc {
+		return SortOrderDesc
+	}
+
+	return SortOrderAsc
+}
+
 func (so SortOrder) String() string {
 	if so == SortOrderAsc {
 		return "ASC"
@@ -74,6 +89,122 @@ func (lp ListParams) UseCursorPagination() bool {
 	return lp.AfterId != "" || lp.BeforeId != "" || lp.AfterValue != nil || lp.BeforeValue != nil
 }
 
+// QuerySortOrder returns the order in which a query should select results.
+// Pages before a cursor are selected in reverse order (so that the LIMIT
+// applies to the results closest to the cursor) and then reversed using
+// ReversePage to restore the requested order.
+func (lp ListParams) QuerySortOrder() SortOrder {
+	if lp.BeforeId != "" {
+		return lp.SortOrder.Reverse()
+	}
+
+	return lp.SortOrder
+}
+
+// ReversePage restores the requested order of a page of results selected using QuerySortOrder
+func ReversePage[T any](listParams ListParams, results []T) {
+	if listParams.BeforeId == "" {
+		return
+	}
+
+	for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+		results[i], results[j] = results[j], results[i]
+	}
+}
+
+// NullValue is the sortBy value of a result whose sortBy attribute is null.
+// Null values sort before all other values.
+type NullValue struct{}
+
+// KeysetCondition returns a condition matching the results that come after the
+// result with the given id and sortBy value when sorting by column in sortOrder
+// and then by idColumn. Null values sort first in ascending order and last in
+// descending order. Pass the reverse of sortOrder to match the results before it.
+func KeysetCondition(column string, idColumn string, sortOrder SortOrder, value interface{}, id string) (string, []interface{}) {
+	_, isNull := value.(NullValue)
+	switch {
+	case sortOrder == SortOrderAsc && isNull:
+		return fmt.Sprintf("(%s IS NOT NULL OR (%s > ? AND %s IS NULL))", column, idColumn, column), []interface{}{id}
+	case sortOrder == SortOrderAsc:
+		return fmt.Sprintf("(%s > ? OR (%s > ? AND %s = ?))", column, idColumn, column), []interface{}{value, id, value}
+	case isNull:
+		return fmt.Sprintf("(%s < ? AND %s IS NULL)", idColumn, column), []interface{}{id}
+	default:
+		return fmt.Sprintf("(%s < ? OR (%s < ? AND %s = ?) OR %s IS NULL)", column, idColumn, column, column), []interface{}{value, id, value}
+	}
+}
+
+// Cursor identifies a position in a list of results by the id and, if the list
+// is not sorted by its default sortBy, the sortBy value of a result
+type Cursor struct {
+	ID    string      `json:"id"`
+	Value interface{} `json:"value,omitempty"`
+	Null  bool        `json:"null,omitempty"` // NOTE: true if the sortBy value of the result is null
+}
+
+func NewCursor(id string, value interface{}) Cursor {
+	switch v := value.(type) {
+	case time.Time:
+		value = v.UTC().Format(time.RFC3339Nano)
+	case NullValue:
+		return Cursor{
+			ID:   id,
+			Null: true,
+		}
+	}
+
+	return Cursor{
+		ID:    id,
+		Value: value,
+	}
+}
+
+func (cursor Cursor) String() string {
+	jsonStr, err := json.Marshal(cursor)
+	if err != nil {
+		log.Error().Err(err).Msgf("error marshaling cursor with id %s", cursor.ID)
+		return ""
+	}
+
+	return base64.URLEncoding.EncodeToString(jsonStr)
+}
+
+func ParseCursor(val string) (*Cursor, error) {
+	var cursor Cursor
+	jsonStr, err := base64.URLEncoding.DecodeString(val)
+	if err != nil {
+		return nil, fmt.Errorf("must be a cursor returned by a previous list request")
+	}
+
+	err = json.Unmarshal(jsonStr, &cursor)
+	if err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("must be a cursor returned by a previous list request")
+	}
+
+	return &cursor, nil
+}
+
+type CursorSpec interface {
+	ToCursor(sortBy string) Cursor
+}
+
+// SetCursorHeaders sets the cursors of the next and previous pages of results
+// on the response. Clients pass these back as the nextCursor and prevCursor
+// params to page through results.
+func SetCursorHeaders[T CursorSpec](w http.ResponseWriter, listParams ListParams, results []T) {
+	if len(results) == 0 {
+		return
+	}
+
+	if len(results) == listParams.Limit || listParams.BeforeId != "" {
+		w.Header().Set(headerNextCursor, results[len(results)-1].ToCursor(listParams.SortBy).String())
+	}
+
+	if listParams.AfterId != "" || (listParams.BeforeId != "" && len(results) == listParams.Limit) {
+		w.Header().Set(headerPrevCursor, results[0].ToCursor(listParams.SortBy).String())
+	}
+}
+
 type GetDefaultSortByFunc func() string
 type GetSupportedSortBys func() []string
 
@@ -158,6 +289,45 @@ func ListMiddleware[T ListParamParser](next http.Handler) http.Handler {
 		afterValueParam := urlQueryParams.Get(paramNameAfterValue)
 		beforeValueParam := urlQueryParams.Get(paramNameBeforeValue)
 
+		// nextCursor and prevCursor are shorthand for afterId/afterValue and beforeId/beforeValue
+		afterValueNull := false
+		beforeValueNull := false
+		if urlQueryParams.Has(paramNameNextCursor) {
+			cursor, err := ParseCursor(urlQueryParams.Get(paramNameNextCursor))
+			if err != nil {
+				service.SendErrorResponse(w, service.NewInvalidParameterError(paramNameNextCursor, err.Error()))
+				return
+			}
+
+			afterIdParam = cursor.ID
+			urlQueryParams.Set(paramNameAfterId, cursor.ID)
+			if cursor.Null {
+				afterValueNull = true
+				urlQueryParams.Set(paramNameAfterValue, "")
+			} else if cursor.Value != nil {
+				afterValueParam = fmt.Sprint(cursor.Value)
+				urlQueryParams.Set(paramNameAfterValue, afterValueParam)
+			}
+		}
+
+		if urlQueryParams.Has(paramNamePrevCursor) {
+			cursor, err := ParseCursor(urlQueryParams.Get(paramNamePrevCursor))
+			if err != nil {
+				service.SendErrorResponse(w, service.NewInvalidParameterError(paramNamePrevCursor, err.Error()))
+				return
+			}
+
+			beforeIdParam = cursor.ID
+			urlQueryParams.Set(paramNameBeforeId, cursor.ID)
+			if cursor.Null {
+				beforeValueNull = true
+				urlQueryParams.Set(paramNameBeforeValue, "")
+			} else if cursor.Value != nil {
+				beforeValueParam = fmt.Sprint(cursor.Value)
+				urlQueryParams.Set(paramNameBeforeValue, beforeValueParam)
+			}
+		}
+
 		page, err := ParsePage(pageParam)
 		if err != nil {
 			service.SendErrorResponse(w, service.NewInvalidParameterError(paramNamePage, err.Error()))
@@ -221,7 +391,9 @@ func ListMiddleware[T ListParamParser](next http.Handler) http.Handler {
 		}
 
 		var afterValue interface{} = nil
-		if urlQueryParams.Has(paramNameAfterValue) {
+		if afterValueNull {
+			afterValue = NullValue{}
+		} else if urlQueryParams.Has(paramNameAfterValue) {
 			afterValue, err = ParseValue(afterValueParam, sortBy, listParamParser)
 			if err != nil {
 				service.SendErrorResponse(w, service.NewInvalidParameterError(paramNameAfterValue, err.Error()))
@@ -230,7 +402,9 @@ func ListMiddleware[T ListParamParser](next http.Handler) http.Handler {
 		}
 
 		var beforeValue interface{} = nil
-		if urlQueryParams.Has(paramNameBeforeValue) {
+		if beforeValueNull {
+			beforeValue = NullValue{}
+		} else if urlQueryParams.Has(paramNameBeforeValue) {
 			beforeValue, err = ParseValue(beforeValueParam, sortBy, listParamParser)
 			if err != nil {
 				service.SendErrorResponse(w, service.NewInvalidParameterError(paramNameBeforeValue, err.Error()))
@@ -0,0 +1,23 @@
+package retention
+
+import (
+	"net/http"
+
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+func (svc RetentionService) Routes() []service.Route {
+	return []service.Route{
+		// get
+		{
+			Pattern: "/v1/retention",
+			Method:  "GET",
+			Handler: service.NewRouteHandler(svc, GetStatsHandler),
+		},
+	}
+}
+
+func GetStatsHandler(svc RetentionService, w http.ResponseWriter, r *http.Request) error {
+	service.SendJSONResponse(w, svc.GetStats())
+	return nil
+}
@@ -0,0 +1,133 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+var mysqlTables = map[string]string{
+	ResourceFeature:     "feature",
+	ResourcePermission:  "permission",
+	ResourcePricingTier: "pricingTier",
+	ResourceRole:        "role",
+	ResourceTenant:      "tenant",
+	ResourceUser:        "user",
+}
+
+type MySQLRepository struct {
+	database.SQLRepository
+}
+
+func NewMySQLRepository(db *database.MySQL) MySQLRepository {
+	return MySQLRepository{
+		database.NewSQLRepository(&db.SQL),
+	}
+}
+
+func (repo MySQLRepository) Purge(ctx context.Context, resource string, deletedBefore time.Time, batchSize int) (int64, error) {
+	var query string
+	args := []interface{}{deletedBefore}
+	switch resource {
+	case ResourceWarrantHistory:
+		query = `
+			DELETE FROM warrantHistory
+			WHERE validTo < ?
+			LIMIT ?
+		`
+	case ResourceObjectTypeHistory:
+		query = `
+			DELETE FROM objectTypeHistory
+			WHERE validTo < ?
+			LIMIT ?
+		`
+	case ResourceContext:
+		// NOTE: contexts aren't always deleted along with their warrant, so
+		// the contexts of purgeable warrants are purged along with them
+		query = `
+			DELETE FROM context
+			WHERE
+				deletedAt < ? OR
+				warrantId IN (SELECT id FROM warrant WHERE deletedAt < ?)
+			LIMIT ?
+		`
+		args = append(args, deletedBefore)
+	case ResourceWarrant:
+		query = `
+			DELETE FROM warrant
+			WHERE deletedAt < ?
+			LIMIT ?
+		`
+	case ResourceObject:
+		// NOTE: objects still referenced by a resource (e.g. a user) are kept
+		query = `
+			DELETE FROM object
+			WHERE
+				deletedAt < ? AND
+				NOT EXISTS (SELECT 1 FROM feature WHERE feature.objectId = object.id) AND
+				NOT EXISTS (SELECT 1 FROM permission WHERE permission.objectId = object.id) AND
+				NOT EXISTS (SELECT 1 FROM pricingTier WHERE pricingTier.objectId = object.id) AND
+				NOT EXISTS (SELECT 1 FROM role WHERE role.objectId = object.id) AND
+				NOT EXISTS (SELECT 1 FROM tenant WHERE tenant.objectId = object.id) AND
+				NOT EXISTS (SELECT 1 FROM user WHERE user.objectId = object.id)
+			LIMIT ?
+		`
+	case ResourceObjectType:
+		query = `
+			DELETE FROM objectType
+			WHERE deletedAt < ?
+			LIMIT ?
+		`
+	case ResourceChange:
+		query = `
+			DELETE FROM changeLog
+			WHERE createdAt < ?
+			LIMIT ?
+		`
+	default:
+		table, ok := mysqlTables[resource]
+		if !ok {
+			return 0, fmt.Errorf("unsupported resource %s", resource)
+		}
+
+		query = fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE deletedAt < ?
+			LIMIT ?
+		`, table)
+	}
+
+	args = append(args, batchSize)
+	result, err := repo.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("Unable to purge deleted %s rows from mysql", resource))
+	}
+
+	rowsPurged, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("Unable to purge deleted %s rows from mysql", resource))
+	}
+
+	return rowsPurged, nil
+}
+
+func (repo MySQLRepository) AdvanceHistoryHorizon(ctx context.Context, horizon time.Time) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE historyHorizon
+			SET horizon = GREATEST(horizon, ?)
+			WHERE
+				id = 1
+		`,
+		horizon,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to advance history horizon in mysql")
+	}
+
+	return nil
+}
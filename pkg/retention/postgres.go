@@ -0,0 +1,166 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+var postgresTables = map[string]string{
+	ResourceFeature:     "feature",
+	ResourcePermission:  "permission",
+	ResourcePricingTier: "pricing_tier",
+	ResourceRole:        "role",
+	ResourceTenant:      "tenant",
+	ResourceUser:        `"user"`,
+}
+
+type PostgresRepository struct {
+	database.SQLRepository
+}
+
+func NewPostgresRepository(db *database.Postgres) PostgresRepository {
+	return PostgresRepository{
+		database.NewSQLRepository(&db.SQL),
+	}
+}
+
+// NOTE: postgres doesn't support DELETE ... LIMIT, so each batch of rows is selected in a subquery
+func (repo PostgresRepository) Purge(ctx context.Context, resource string, deletedBefore time.Time, batchSize int) (int64, error) {
+	var query string
+	args := []interface{}{deletedBefore}
+	switch resource {
+	case ResourceWarrantHistory:
+		query = `
+			DELETE FROM warrant_history
+			WHERE id IN (
+				SELECT id
+				FROM warrant_history
+				WHERE valid_to < ?
+				LIMIT ?
+			)
+		`
+	case ResourceObjectTypeHistory:
+		query = `
+			DELETE FROM object_type_history
+			WHERE id IN (
+				SELECT id
+				FROM object_type_history
+				WHERE valid_to < ?
+				LIMIT ?
+			)
+		`
+	case ResourceContext:
+		// NOTE: contexts aren't always deleted along with their warrant, so
+		// the contexts of purgeable warrants are purged along with them
+		query = `
+			DELETE FROM context
+			WHERE id IN (
+				SELECT id
+				FROM context
+				WHERE
+					deleted_at < ? OR
+					warrant_id IN (SELECT id FROM warrant WHERE deleted_at < ?)
+				LIMIT ?
+			)
+		`
+		args = append(args, deletedBefore)
+	case ResourceWarrant:
+		query = `
+			DELETE FROM warrant
+			WHERE id IN (
+				SELECT id
+				FROM warrant
+				WHERE deleted_at < ?
+				LIMIT ?
+			)
+		`
+	case ResourceObject:
+		// NOTE: objects still referenced by a resource (e.g. a user) are kept
+		query = `
+			DELETE FROM object
+			WHERE id IN (
+				SELECT id
+				FROM object
+				WHERE
+					deleted_at < ? AND
+					NOT EXISTS (SELECT 1 FROM feature WHERE feature.object_id = object.id) AND
+					NOT EXISTS (SELECT 1 FROM permission WHERE permission.object_id = object.id) AND
+					NOT EXISTS (SELECT 1 FROM pricing_tier WHERE pricing_tier.object_id = object.id) AND
+					NOT EXISTS (SELECT 1 FROM role WHERE role.object_id = object.id) AND
+					NOT EXISTS (SELECT 1 FROM tenant WHERE tenant.object_id = object.id) AND
+					NOT EXISTS (SELECT 1 FROM "user" WHERE "user".object_id = object.id)
+				LIMIT ?
+			)
+		`
+	case ResourceObjectType:
+		query = `
+			DELETE FROM object_type
+			WHERE id IN (
+				SELECT id
+				FROM object_type
+				WHERE deleted_at < ?
+				LIMIT ?
+			)
+		`
+	case ResourceChange:
+		query = `
+			DELETE FROM change_log
+			WHERE id IN (
+				SELECT id
+				FROM change_log
+				WHERE created_at < ?
+				LIMIT ?
+			)
+		`
+	default:
+		table, ok := postgresTables[resource]
+		if !ok {
+			return 0, fmt.Errorf("unsupported resource %s", resource)
+		}
+
+		query = fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE id IN (
+				SELECT id
+				FROM %s
+				WHERE deleted_at < ?
+				LIMIT ?
+			)
+		`, table, table)
+	}
+
+	args = append(args, batchSize)
+	result, err := repo.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("Unable to purge deleted %s rows from postgres", resource))
+	}
+
+	rowsPurged, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("Unable to purge deleted %s rows from postgres", resource))
+	}
+
+	return rowsPurged, nil
+}
+
+func (repo PostgresRepository) AdvanceHistoryHorizon(ctx context.Context, horizon time.Time) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`
+			UPDATE history_horizon
+			SET horizon = GREATEST(horizon, ?)
+			WHERE
+				id = 1
+		`,
+		horizon,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Unable to advance history horizon in postgres")
+	}
+
+	return nil
+}
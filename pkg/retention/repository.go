@@ -0,0 +1,40 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/warrant-dev/warrant/pkg/database"
+)
+
+type RetentionRepository interface {
+	// Purge permanently deletes up to batchSize rows of the given resource that
+	// were soft-deleted before deletedBefore (or, for changes, created before
+	// deletedBefore) and returns the number of rows deleted
+	Purge(ctx context.Context, resource string, deletedBefore time.Time, batchSize int) (int64, error)
+	// AdvanceHistoryHorizon moves the earliest time point-in-time checks can be
+	// answered for up to horizon, unless it is already later
+	AdvanceHistoryHorizon(ctx context.Context, horizon time.Time) error
+}
+
+func NewRepository(db database.Database) (RetentionRepository, error) {
+	switch db.Type() {
+	case database.TypeMySQL:
+		mysql, ok := db.(*database.MySQL)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s database config", database.TypeMySQL)
+		}
+
+		return NewMySQLRepository(mysql), nil
+	case database.TypePostgres:
+		postgres, ok := db.(*database.Postgres)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s database config", database.TypePostgres)
+		}
+
+		return NewPostgresRepository(postgres), nil
+	default:
+		return nil, fmt.Errorf("unsupported database type %s specified", db.Type())
+	}
+}
@@ -0,0 +1,192 @@
+package retention
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/warrant-dev/warrant/pkg/config"
+	"github.com/warrant-dev/warrant/pkg/service"
+)
+
+type RetentionService struct {
+	service.BaseService
+	repo   RetentionRepository
+	config config.RetentionConfig
+	stats  *retentionStats
+}
+
+// retentionStats holds the purge metrics of this process since startup
+type retentionStats struct {
+	mu                sync.Mutex
+	runs              int64
+	failedRuns        int64
+	rowsPurged        map[string]int64
+	lastRunAt         *time.Time
+	lastRunDurationMs int64
+	lastError         string
+}
+
+func NewService(env service.Env, repo RetentionRepository, retentionConfig config.RetentionConfig) RetentionService {
+	return RetentionService{
+		BaseService: service.NewBaseService(env),
+		repo:        repo,
+		config:      retentionConfig,
+		stats: &retentionStats{
+			rowsPurged: make(map[string]int64),
+		},
+	}
+}
+
+// Purge permanently deletes all rows soft-deleted more than the given number
+// of days ago.
+func (svc RetentionService) Purge(ctx context.Context, days int) (*PurgeResultSpec, error) {
+	if days == 0 {
+		days = svc.config.Days
+	}
+
+	if days <= 0 {
+		return nil, service.NewInvalidParameterError("days", "must be greater than 0 if retention days are not configured")
+	}
+
+	start := time.Now().UTC()
+	result, purgeErr := svc.purge(ctx, purgeOrder, start.AddDate(0, 0, -days))
+	svc.stats.record(start, *result, purgeErr)
+	if purgeErr != nil {
+		log.Err(purgeErr).Msgf("Error purging rows deleted before %s", result.DeletedBefore)
+		return nil, purgeErr
+	}
+
+	log.Info().Msgf("Purged %d rows deleted before %s", result.TotalPurged, result.DeletedBefore)
+	return result, nil
+}
+
+// PurgeChanges permanently deletes all changefeed changes created more than the
+// configured number of change days ago. Watchers can't resume from the cursor
+// of a purged change.
+func (svc RetentionService) PurgeChanges(ctx context.Context) (*PurgeResultSpec, error) {
+	if svc.config.ChangeDays <= 0 {
+		return nil, service.NewInvalidRequestError("Purging changes is disabled")
+	}
+
+	start := time.Now().UTC()
+	result, purgeErr := svc.purge(ctx, []string{ResourceChange}, start.AddDate(0, 0, -svc.config.ChangeDays))
+	svc.stats.record(start, *result, purgeErr)
+	if purgeErr != nil {
+		log.Err(purgeErr).Msgf("Error purging changes created before %s", result.DeletedBefore)
+		return nil, purgeErr
+	}
+
+	log.Info().Msgf("Purged %d changes created before %s", result.TotalPurged, result.DeletedBefore)
+	return result, nil
+}
+
+// purge deletes the rows of each of the given resources deleted before
+// deletedBefore in batches of the configured batch size, so a purge never
+// holds locks on a large number of rows at once
+func (svc RetentionService) purge(ctx context.Context, resources []string, deletedBefore time.Time) (*PurgeResultSpec, error) {
+	batchSize := svc.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	result := PurgeResultSpec{
+		DeletedBefore: deletedBefore,
+		RowsPurged:    make(map[string]int64),
+	}
+	for _, resource := range resources {
+		// NOTE: the history horizon is advanced before any history is purged
+		// so point-in-time checks never see partially purged history
+		if resource == ResourceWarrantHistory {
+			err := svc.repo.AdvanceHistoryHorizon(ctx, deletedBefore)
+			if err != nil {
+				return &result, err
+			}
+		}
+
+		for {
+			rowsPurged, err := svc.repo.Purge(ctx, resource, deletedBefore, batchSize)
+			if err != nil {
+				return &result, err
+			}
+
+			result.RowsPurged[resource] += rowsPurged
+			result.TotalPurged += rowsPurged
+			if rowsPurged < int64(batchSize) {
+				break
+			}
+		}
+	}
+
+	return &result, nil
+}
+
+// StartPurging purges rows older than the configured retention days and
+// changes older than the configured change days each purgeInterval until ctx
+// is cancelled.
+func (svc RetentionService) StartPurging(ctx context.Context, purgeInterval time.Duration) {
+	ticker := time.NewTicker(purgeInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// NOTE: errors are logged and recorded in the retention stats
+				if svc.config.Days > 0 {
+					svc.Purge(ctx, svc.config.Days)
+				}
+
+				if svc.config.ChangeDays > 0 {
+					svc.PurgeChanges(ctx)
+				}
+			}
+		}
+	}()
+}
+
+func (svc RetentionService) GetStats() RetentionStatsSpec {
+	return svc.stats.toSpec(svc.config.Days)
+}
+
+func (stats *retentionStats) record(start time.Time, result PurgeResultSpec, err error) {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	stats.runs++
+	stats.lastRunAt = &start
+	stats.lastRunDurationMs = time.Since(start).Milliseconds()
+	for resource, rowsPurged := range result.RowsPurged {
+		stats.rowsPurged[resource] += rowsPurged
+	}
+
+	if err != nil {
+		stats.failedRuns++
+		stats.lastError = err.Error()
+	} else {
+		stats.lastError = ""
+	}
+}
+
+func (stats *retentionStats) toSpec(days int) RetentionStatsSpec {
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	spec := RetentionStatsSpec{
+		Days:              days,
+		Runs:              stats.runs,
+		FailedRuns:        stats.failedRuns,
+		RowsPurged:        make(map[string]int64),
+		LastRunAt:         stats.lastRunAt,
+		LastRunDurationMs: stats.lastRunDurationMs,
+		LastError:         stats.lastError,
+	}
+	for resource, rowsPurged := range stats.rowsPurged {
+		spec.RowsPurged[resource] = rowsPurged
+		spec.TotalPurged += rowsPurged
+	}
+
+	return spec
+}
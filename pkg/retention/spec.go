@@ -0,0 +1,55 @@
+package retention
+
+import "time"
+
+const (
+	ResourceWarrantHistory    = "warrant-history"
+	ResourceObjectTypeHistory = "object-type-history"
+	ResourceContext           = "context"
+	ResourceWarrant           = "warrant"
+	ResourceFeature           = "feature"
+	ResourcePermission        = "permission"
+	ResourcePricingTier       = "pricing-tier"
+	ResourceRole              = "role"
+	ResourceTenant            = "tenant"
+	ResourceUser              = "user"
+	ResourceObject            = "object"
+	ResourceObjectType        = "object-type"
+	ResourceChange            = "change" // NOTE: changes are purged by creation time instead of deletion time
+)
+
+// NOTE: resources are purged in this order so rows are purged before any rows they reference
+// NOTE: history ending before the retention window is purged too, and the
+// history horizon is advanced to the start of the window so point-in-time
+// checks before it are rejected instead of missing the purged history
+var purgeOrder = []string{
+	ResourceWarrantHistory,
+	ResourceObjectTypeHistory,
+	ResourceContext,
+	ResourceWarrant,
+	ResourceFeature,
+	ResourcePermission,
+	ResourcePricingTier,
+	ResourceRole,
+	ResourceTenant,
+	ResourceUser,
+	ResourceObject,
+	ResourceObjectType,
+}
+
+type PurgeResultSpec struct {
+	DeletedBefore time.Time        `json:"deletedBefore"`
+	RowsPurged    map[string]int64 `json:"rowsPurged"`
+	TotalPurged   int64            `json:"totalPurged"`
+}
+
+type RetentionStatsSpec struct {
+	Days              int              `json:"days"`
+	Runs              int64            `json:"runs"`
+	FailedRuns        int64            `json:"failedRuns"`
+	RowsPurged        map[string]int64 `json:"rowsPurged"`
+	TotalPurged       int64            `json:"totalPurged"`
+	LastRunAt         *time.Time       `json:"lastRunAt,omitempty"`
+	LastRunDurationMs int64            `json:"lastRunDurationMs"`
+	LastError         string           `json:"lastError,omitempty"`
+}
@@ -28,9 +28,20 @@ const (
 	EnableSessionAuthKey = "EnableSessionAuth"
 )
 
+// Headers API key callers can set to make a request on behalf of a user (and
+// tenant) on endpoints that support session auth, e.g. /v2/authorize and the
+// lists of a user's roles, permissions, and tenants
+const (
+	HeaderImpersonateUserId   = "Warrant-Impersonate-User-Id"
+	HeaderImpersonateTenantId = "Warrant-Impersonate-Tenant-Id"
+)
+
 type AuthInfo struct {
 	UserId   string
 	TenantId string
+
+	// Impersonated is true if an API key caller is making the request on behalf of UserId
+	Impersonated bool
 }
 
 type AuthMiddlewareFunc func(next http.Handler, config *config.Config, options map[string]interface{}) http.Handler
@@ -63,8 +74,28 @@ func DefaultAuthMiddleware(next http.Handler, config *config.Config, options map
 				return
 			}
 			authInfo = &AuthInfo{}
+
+			impersonatedUserId := r.Header.Get(HeaderImpersonateUserId)
+			impersonatedTenantId := r.Header.Get(HeaderImpersonateTenantId)
+			if impersonatedUserId != "" || impersonatedTenantId != "" {
+				if enableSessionAuth, _ := sessionAuthEnabled(options); !enableSessionAuth {
+					SendErrorResponse(w, NewInvalidRequestError("Impersonation is not supported for this endpoint"))
+					return
+				}
+
+				if impersonatedUserId == "" {
+					SendErrorResponse(w, NewMissingRequiredParameterError(HeaderImpersonateUserId))
+					return
+				}
+
+				authInfo = &AuthInfo{
+					UserId:       impersonatedUserId,
+					TenantId:     impersonatedTenantId,
+					Impersonated: true,
+				}
+			}
 		case "Bearer":
-			enableSessionAuth, ok := options["enableSessionAuth"].(bool)
+			enableSessionAuth, ok := sessionAuthEnabled(options)
 			if !ok {
 				SendErrorResponse(w, NewUnauthorizedError("Error validating token"))
 				logger.Err(fmt.Errorf("enableSessionAuth must be of type bool"))
@@ -178,6 +209,37 @@ func DefaultAuthMiddleware(next http.Handler, config *config.Config, options map
 	})
 }
 
+// ScopeToSession returns the tenantId a request for the given user and tenant
+// is scoped to. API key requests can access any user and tenant, while session
+// token (and impersonated) requests can only access their own user and, if the
+// session has one, their own tenant, which is used when tenantId is empty.
+func ScopeToSession(authInfo *AuthInfo, userId string, tenantId string) (string, error) {
+	if authInfo == nil || authInfo.UserId == "" {
+		return tenantId, nil
+	}
+
+	if userId != authInfo.UserId {
+		return "", NewForbiddenError("Session requests can only access their own user")
+	}
+
+	if authInfo.TenantId == "" {
+		return tenantId, nil
+	}
+
+	if tenantId != "" && tenantId != authInfo.TenantId {
+		return "", NewForbiddenError("Session requests can only access their own tenant")
+	}
+
+	return authInfo.TenantId, nil
+}
+
+// sessionAuthEnabled returns whether the route the auth middleware options
+// were created for accepts session tokens. ok is false if the option is missing.
+func sessionAuthEnabled(options map[string]interface{}) (enabled bool, ok bool) {
+	enabled, ok = options[EnableSessionAuthKey].(bool)
+	return enabled, ok
+}
+
 // GetAuthInfoFromRequestContext returns the AuthInfo object from the given context
 func GetAuthInfoFromRequestContext(context context.Context) *AuthInfo {
 	contextVal := context.Value(authInfoKey)
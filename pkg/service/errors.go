@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 const (
@@ -84,6 +85,39 @@ func NewInvalidRequestError(msg string) *InvalidRequestError {
 	}
 }
 
+// LineError is an error on a specific line of a text request body
+type LineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// InvalidLinesError type
+type InvalidLinesError struct {
+	*genericError
+	Lines []LineError `json:"lines"`
+}
+
+func NewInvalidLinesError(lines []LineError) *InvalidLinesError {
+	return &InvalidLinesError{
+		genericError: NewGenericError(
+			"InvalidLinesError",
+			ErrorInvalidRequest,
+			http.StatusBadRequest,
+			fmt.Sprintf("%d line(s) are invalid", len(lines)),
+		),
+		Lines: lines,
+	}
+}
+
+func (err *InvalidLinesError) Error() string {
+	lineErrors := make([]string, 0)
+	for _, lineError := range err.Lines {
+		lineErrors = append(lineErrors, fmt.Sprintf("line %d: %s", lineError.Line, lineError.Message))
+	}
+
+	return fmt.Sprintf("%s: %s", err.GetTag(), strings.Join(lineErrors, "; "))
+}
+
 // InvalidParameterError type
 type InvalidParameterError struct {
 	*genericError
@@ -12,6 +12,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
 )
 
 var validate *validator.Validate
@@ -19,10 +20,15 @@ var validate *validator.Validate
 func init() {
 	validate = validator.New()
 	validate.RegisterValidation("required_if_oneof", requiredIfOneOf)
+	validate.RegisterValidation("excluded_unless_oneof", excludedUnlessOneOf)
 	validate.RegisterValidation("valid_object_id", validObjectId)
+	validate.RegisterValidation("valid_object_id_or_path", validObjectIdOrPath)
+	validate.RegisterValidation("valid_subject_id_or_path", validSubjectIdOrPath)
 	validate.RegisterValidation("valid_object_type", validObjectType)
 	validate.RegisterValidation("valid_relation", validRelation)
 	validate.RegisterValidation("valid_inheritif", validInheritIf)
+	validate.RegisterValidation("valid_rule_count", validRuleCount)
+	validate.RegisterValidation("valid_attribute", validAttribute)
 }
 
 func requiredIfOneOf(fl validator.FieldLevel) bool {
@@ -52,6 +58,33 @@ func requiredIfOneOf(fl validator.FieldLevel) bool {
 	return true
 }
 
+func excludedUnlessOneOf(fl validator.FieldLevel) bool {
+	tagParts := strings.Split(fl.Param(), " ")
+	otherFieldName := tagParts[0]
+	validValues := tagParts[1:]
+
+	var otherFieldValue reflect.Value
+	switch fl.Parent().Kind() {
+	case reflect.Ptr:
+		otherFieldValue = fl.Parent().Elem().FieldByName(otherFieldName)
+	default:
+		otherFieldValue = fl.Parent().FieldByName(otherFieldName)
+	}
+
+	for _, validValue := range validValues {
+		if otherFieldValue.String() == validValue {
+			return true
+		}
+	}
+
+	switch fl.Field().Kind() {
+	case reflect.Slice, reflect.Map, reflect.Ptr, reflect.Interface, reflect.Chan, reflect.Func:
+		return fl.Field().IsNil()
+	default:
+		return fl.Field().IsZero()
+	}
+}
+
 func validObjectId(fl validator.FieldLevel) bool {
 	value := fl.Field().String()
 	if value == "" || value == "*" {
@@ -62,6 +95,41 @@ func validObjectId(fl validator.FieldLevel) bool {
 	return regExp.Match([]byte(value))
 }
 
+// validObjectIdOrPath accepts object ids and paths of object ids (e.g.
+// bucket/folder/file or bucket/folder/*). Only hierarchical object types can
+// have path object ids, so the path itself is validated against the object
+// type by ObjectTypeSpec.ValidateObjectId.
+func validObjectIdOrPath(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	regExp := regexp.MustCompile(`^[a-zA-Z0-9_\-\.@\|]*$`)
+	for _, segment := range strings.Split(value, "/") {
+		if segment != "*" && !regExp.Match([]byte(segment)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validSubjectIdOrPath accepts the same object ids and paths as
+// validObjectIdOrPath, except for prefix wildcards (e.g. bucket/folder/*),
+// which only apply to the object of a warrant.
+func validSubjectIdOrPath(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "*" {
+		return true
+	}
+
+	regExp := regexp.MustCompile(`^[a-zA-Z0-9_\-\.@\|]*$`)
+	for _, segment := range strings.Split(value, "/") {
+		if !regExp.Match([]byte(segment)) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func validObjectType(fl validator.FieldLevel) bool {
 	value := fl.Field().String()
 	if value == "" {
@@ -89,18 +157,69 @@ func validInheritIf(fl validator.FieldLevel) bool {
 	}
 
 	switch value {
-	case "anyOf", "allOf", "noneOf":
+	case "anyOf", "allOf", "noneOf", "butNot", "atLeast", "condition":
 		return true
 	default:
 		return validRelation(fl)
 	}
 }
 
+func validRuleCount(fl validator.FieldLevel) bool {
+	tagParts := strings.Split(fl.Param(), " ")
+	if len(tagParts) != 2 {
+		return false
+	}
+
+	var parent reflect.Value
+	switch fl.Parent().Kind() {
+	case reflect.Ptr:
+		parent = fl.Parent().Elem()
+	default:
+		parent = fl.Parent()
+	}
+
+	numRules := 0
+	if fl.Field().Kind() == reflect.Slice {
+		numRules = fl.Field().Len()
+	}
+
+	switch parent.FieldByName(tagParts[0]).String() {
+	case "butNot":
+		return numRules == 2
+	case "atLeast":
+		return int64(numRules) >= parent.FieldByName(tagParts[1]).Int()
+	default:
+		return true
+	}
+}
+
+func validAttribute(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+
+	regExp := regexp.MustCompile(`^(object|subject|context)(\.[a-zA-Z0-9_\-]+)+$`)
+	return regExp.Match([]byte(value))
+}
+
 func IsArray(data []byte) bool {
 	x := bytes.TrimLeft(data, "\t\r\n ")
 	return len(x) > 0 && x[0] == '['
 }
 
+// YAMLToJSON converts a YAML document (or a JSON document, which is valid
+// YAML) to JSON so it can be parsed using json tags
+func YAMLToJSON(contents []byte) ([]byte, error) {
+	var document interface{}
+	err := yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(document)
+}
+
 func ParseJSONBytes(body []byte, obj interface{}) error {
 	err := json.Unmarshal(body, &obj)
 	if err != nil {
@@ -177,6 +296,10 @@ func ValidateStruct(obj interface{}) error {
 					return NewMissingRequiredParameterError(fieldName)
 				case "required_if_oneof":
 					return NewMissingRequiredParameterError(fieldName)
+				case "excluded_unless_oneof":
+					tagParts := strings.Split(err.Param(), " ")
+					otherFieldName := strings.ToLower(tagParts[0][:1]) + tagParts[0][1:]
+					return NewInvalidParameterError(fieldName, fmt.Sprintf("must not be provided unless %s is one of %s", otherFieldName, strings.Join(tagParts[1:], ", ")))
 				case "oneof":
 					validValues := strings.Join(strings.Split(err.Param(), " "), ", ")
 					return NewInvalidParameterError(fieldName, fmt.Sprintf("must be one of %s", validValues))
@@ -184,8 +307,16 @@ func ValidateStruct(obj interface{}) error {
 					return NewInvalidParameterError(fieldName, "must be provided and can only contain lower-case alphanumeric characters and/or '-' and '_'")
 				case "valid_object_id":
 					return NewInvalidParameterError(fieldName, "must be provided and can only contain alphanumeric characters and/or '-', '_', '@', and '|'")
+				case "valid_object_id_or_path":
+					return NewInvalidParameterError(fieldName, "must be provided and can only contain alphanumeric characters and/or '-', '_', '@', and '|', and '/' for hierarchical object types")
+				case "valid_subject_id_or_path":
+					return NewInvalidParameterError(fieldName, "must be provided and can only contain alphanumeric characters and/or '-', '_', '@', and '|', and '/' for hierarchical object types, but no '*' path segments")
 				case "valid_inheritif":
-					return NewInvalidParameterError(fieldName, "must be provided and can only be 'anyOf', 'allOf', 'noneOf', or a valid relation name")
+					return NewInvalidParameterError(fieldName, "must be provided and can only be 'anyOf', 'allOf', 'noneOf', 'butNot', 'atLeast', 'condition', or a valid relation name")
+				case "valid_rule_count":
+					return NewInvalidParameterError(fieldName, "must contain exactly 2 rules if inheritIf is 'butNot' and at least count rules if inheritIf is 'atLeast'")
+				case "valid_attribute":
+					return NewInvalidParameterError(fieldName, "must be an attribute of the object, subject, or context (e.g. 'object.owner' or 'subject.clearance')")
 				default:
 					return NewInvalidRequestError("Invalid request body")
 				}